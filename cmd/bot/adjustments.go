@@ -0,0 +1,473 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/orders"
+	"github.com/eddiefleurent/scranton_strangler/internal/strategy"
+	"github.com/eddiefleurent/scranton_strangler/internal/util"
+	"github.com/google/uuid"
+)
+
+const (
+	// adjustmentFillTimeout bounds how long a single adjustment leg may rest before it is canceled
+	adjustmentFillTimeout = 2 * time.Minute
+	// adjustmentCancelTimeout bounds the wait for the broker to confirm a timed-out adjustment is canceled
+	adjustmentCancelTimeout = 45 * time.Second
+)
+
+// rollStep describes how a roll moves a position through the state machine
+type rollStep struct {
+	label          string               // Down the roll belongs to, used in logs and adjustment descriptions
+	startCondition string               // Condition for entering StateAdjusting
+	doneState      models.PositionState // State once both legs have filled
+	doneCondition  string
+	option         models.FourthDownOption // Fourth Down option the roll carries out, recorded once it fills
+}
+
+var (
+	// secondDownRoll rolls the untested side in to the target delta and resumes First Down monitoring
+	secondDownRoll = rollStep{"Second Down", models.ConditionRollUntested, models.StateFirstDown, models.ConditionAdjustmentComplete,
+		models.OptionNone}
+	// thirdDownStraddle rolls the untested side to the tested strike and stays in Third Down
+	thirdDownStraddle = rollStep{"Third Down", models.ConditionExecuteAdjustment, models.StateThirdDown, models.ConditionStraddleFormed,
+		models.OptionNone}
+)
+
+// checkAdjustmentsForPosition runs the Football System for a single position.
+// First Down -> Second Down when spot comes within SecondDownThreshold points of a
+// short strike, then the untested side is rolled to the target delta for extra credit.
+// Second Down -> Third Down once the tested strike is breached, where the untested side
+// is rolled to the tested strike to form a straddle. Third Down -> Fourth Down when spot
+// nears the adjusted breakeven, where the Fourth Down decision engine takes over.
+func (tc *TradingCycle) checkAdjustmentsForPosition(position *models.Position) {
+	// Work from the stored copy so exits placed earlier in this cycle are visible
+	current, found := tc.bot.storage.GetPositionByID(position.ID)
+	if !found {
+		return
+	}
+	position = &current
+
+	if position.ExitOrderID != "" {
+		tc.bot.logger.Printf("Position %s has pending exit order %s, skipping adjustments",
+			shortID(position.ID), position.ExitOrderID)
+		return
+	}
+
+	// A roll or punt is watched by the order manager until it ends; one still marked
+	// without a watch was left unresolved by an earlier run or an unknown outcome
+	if position.HasWorkingAdjustment() {
+		if orderID, err := strconv.Atoi(position.AdjustmentOrderID); err == nil && tc.bot.orderManager.IsWatching(orderID) {
+			tc.bot.logger.Printf("Position %s has adjustment order %d working, skipping adjustments",
+				shortID(position.ID), orderID)
+			return
+		}
+		// The watch may have settled the position since it was read
+		if current, found := tc.bot.storage.GetPositionByID(position.ID); !found ||
+			current.AdjustmentOrderID != position.AdjustmentOrderID {
+			return
+		}
+		tc.settleStaleAdjustment(position)
+		return
+	}
+
+	if position.GetCurrentState() == models.StateOpen {
+		if err := position.TransitionState(models.StateFirstDown, models.ConditionStartManagement); err != nil {
+			tc.bot.logger.Printf("Failed to start management for position %s: %v", shortID(position.ID), err)
+			return
+		}
+		if err := tc.bot.storage.UpdatePosition(position); err != nil {
+			tc.bot.logger.Printf("Failed to persist First Down for position %s: %v", shortID(position.ID), err)
+			return
+		}
+		tc.bot.logger.Printf("Position %s entered First Down", shortID(position.ID))
+	}
+
+	if !position.IsInManagement() {
+		return
+	}
+
+	quote, err := tc.bot.broker.GetQuote(position.Symbol)
+	if err != nil || quote == nil || quote.Last <= 0 {
+		tc.bot.logger.Printf("Adjustment check for position %s skipped: no usable quote (%v)", shortID(position.ID), err)
+		return
+	}
+	spot := quote.Last
+	tc.bot.journal.NoteSpot(position.Symbol, spot)
+	threshold := tc.bot.config.Strategy.Adjustments.SecondDownThreshold
+	tested, challenged := strategy.TestedSide(position, spot, threshold)
+
+	switch position.GetCurrentState() {
+	case models.StateFirstDown:
+		if !challenged {
+			return
+		}
+		tc.bot.logger.Printf("Position %s %s strike challenged: spot %.2f within %.2f points (Put %.2f / Call %.2f)",
+			shortID(position.ID), tested, spot, threshold, position.PutStrike, position.CallStrike)
+		if err := position.TransitionState(models.StateSecondDown, models.ConditionStrikeChallenged); err != nil {
+			tc.bot.logger.Printf("Failed to enter Second Down for position %s: %v", shortID(position.ID), err)
+			return
+		}
+		if err := tc.bot.storage.UpdatePosition(position); err != nil {
+			tc.bot.logger.Printf("Failed to persist Second Down for position %s: %v", shortID(position.ID), err)
+			return
+		}
+		if strategy.StrikeBreached(position, tested, spot) {
+			tc.enterThirdDown(position, tested, spot)
+			return
+		}
+		tc.rollUntestedSide(position, tested, spot)
+	case models.StateSecondDown:
+		if strategy.StrikeBreached(position, tested, spot) {
+			tc.enterThirdDown(position, tested, spot)
+			return
+		}
+		if !challenged {
+			tc.bot.logger.Printf("Position %s recovered: spot %.2f no longer within %.2f points of a strike",
+				shortID(position.ID), spot, threshold)
+			if err := position.TransitionState(models.StateFirstDown, models.ConditionPriceRecovered); err != nil {
+				tc.bot.logger.Printf("Failed to return position %s to First Down: %v", shortID(position.ID), err)
+				return
+			}
+			if err := tc.bot.storage.UpdatePosition(position); err != nil {
+				tc.bot.logger.Printf("Failed to persist First Down for position %s: %v", shortID(position.ID), err)
+			}
+			return
+		}
+		tc.rollUntestedSide(position, tested, spot)
+	case models.StateThirdDown:
+		if strategy.ApproachingBreakeven(position, tested, spot, threshold) {
+			tc.enterFourthDown(position, spot)
+			return
+		}
+		// Retry the straddle if an earlier attempt did not complete
+		if position.PutStrike != position.CallStrike {
+			tc.formStraddle(position, tested)
+		}
+	case models.StateFourthDown:
+		if position.GetFourthDownOption() == models.OptionNone {
+			tc.runFourthDown(position, spot)
+		}
+	}
+}
+
+// enterThirdDown records a breached strike and repairs the position into a straddle
+func (tc *TradingCycle) enterThirdDown(position *models.Position, tested broker.OptionType, spot float64) {
+	tc.bot.logger.Printf("Position %s %s strike breached: spot %.2f (Put %.2f / Call %.2f)",
+		shortID(position.ID), tested, spot, position.PutStrike, position.CallStrike)
+	if err := position.TransitionState(models.StateThirdDown, models.ConditionStrikeBreached); err != nil {
+		tc.bot.logger.Printf("Failed to enter Third Down for position %s: %v", shortID(position.ID), err)
+		return
+	}
+	if err := tc.bot.storage.UpdatePosition(position); err != nil {
+		tc.bot.logger.Printf("Failed to persist Third Down for position %s: %v", shortID(position.ID), err)
+		return
+	}
+	tc.formStraddle(position, tested)
+}
+
+// rollUntestedSide closes the untested leg and sells a new target-delta leg on the same
+// side and expiration, recording the extra credit as a roll adjustment.
+func (tc *TradingCycle) rollUntestedSide(position *models.Position, tested broker.OptionType, spot float64) {
+	if !position.CanAdjust() {
+		tc.bot.logger.Printf("Position %s has no adjustments remaining, not rolling", shortID(position.ID))
+		return
+	}
+
+	roll, err := tc.bot.strategy.FindUntestedRoll(position, tested, spot)
+	if err != nil {
+		tc.bot.logger.Printf("No untested-side roll for position %s: %v", shortID(position.ID), err)
+		return
+	}
+	tc.executeRoll(position, roll, secondDownRoll)
+}
+
+// formStraddle rolls the untested leg to the tested strike. The position stays in
+// Third Down so the reduced Third Down profit target applies from here on.
+func (tc *TradingCycle) formStraddle(position *models.Position, tested broker.OptionType) {
+	if !position.CanAdjust() {
+		tc.bot.logger.Printf("Position %s has no adjustments remaining, not forming straddle", shortID(position.ID))
+		return
+	}
+
+	roll, err := tc.bot.strategy.FindStraddleRoll(position, tested)
+	if err != nil {
+		tc.bot.logger.Printf("No straddle roll for position %s: %v", shortID(position.ID), err)
+		return
+	}
+	tc.executeRoll(position, roll, thirdDownStraddle)
+}
+
+// executeRoll buys back the old leg and sells the new one as a single multileg order for a
+// net credit, then moves the position through StateAdjusting as described by step.
+func (tc *TradingCycle) executeRoll(position *models.Position, roll *strategy.RollOrder, step rollStep) {
+	if !tc.claimAdjustment(position) {
+		return
+	}
+	if !tc.releaseLinkedOrders(position, "rolling") {
+		tc.releaseAdjustment(position)
+		return
+	}
+
+	tickSize, err := tc.bot.broker.GetTickSize(position.Symbol)
+	if err != nil {
+		tc.bot.logger.Printf("Warning: Failed to get tick size for %s, using default 0.01: %v", position.Symbol, err)
+		tickSize = 0.01
+	}
+	netPrice := math.Max(util.FloorToTick(roll.NetCredit(), tickSize), tickSize)
+
+	oldSymbol := broker.BuildOptionSymbol(position.Symbol, position.Expiration, roll.Side, roll.OldStrike)
+	newSymbol := broker.BuildOptionSymbol(position.Symbol, position.Expiration, roll.Side, roll.NewStrike)
+	legs := []broker.OrderLeg{
+		{OptionSymbol: oldSymbol, Side: broker.SideBuyToClose, Quantity: position.Quantity},
+		{OptionSymbol: newSymbol, Side: broker.SideSellToOpen, Quantity: position.Quantity},
+	}
+	tag := fmt.Sprintf("roll-%s-%d", shortID(position.ID), time.Now().Unix())
+
+	tc.bot.logger.Printf("%s: rolling untested %s for position %s: buy %s, sell %s for $%.2f net credit",
+		step.label, roll.Side, shortID(position.ID), oldSymbol, newSymbol, netPrice)
+
+	ctx, cancel := context.WithTimeout(tc.bot.ctx, 15*time.Second)
+	resp, err := tc.bot.broker.PlaceMultiLegOrderCtx(ctx, legs, broker.NetCredit, netPrice,
+		string(broker.DurationDay), tag)
+	cancel()
+	if err != nil || resp == nil {
+		tc.bot.logger.Printf("Failed to place roll for position %s: %v", shortID(position.ID), err)
+		tc.releaseAdjustment(position)
+		return
+	}
+	tc.bot.journalOrderPlaced(position, resp.Order.ID, netPrice, fmt.Sprintf("%s roll", step.label))
+	tc.recordAdjustmentOrder(position, resp.Order.ID)
+	tc.watchAdjustment(position.ID, resp.Order.ID, netPrice, "roll", func(position *models.Position, filled int, credit float64) {
+		tc.applyRoll(position, roll, step, len(legs), filled, credit)
+	})
+}
+
+// applyRoll records a filled roll of filled contracts on position and saves it
+func (tc *TradingCycle) applyRoll(position *models.Position, roll *strategy.RollOrder, step rollStep,
+	legCount, filled int, credit float64) {
+	if err := position.TransitionState(models.StateAdjusting, step.startCondition); err != nil {
+		tc.bot.logger.Printf("Failed to mark position %s as adjusting: %v", shortID(position.ID), err)
+	}
+	position.Adjustments = append(position.Adjustments, models.Adjustment{
+		Date:        time.Now().UTC(),
+		Type:        models.AdjustmentRoll,
+		Description: fmt.Sprintf("%s: rolled untested %s %.2f -> %.2f", step.label, roll.Side, roll.OldStrike, roll.NewStrike),
+		OldStrike:   roll.OldStrike,
+		NewStrike:   roll.NewStrike,
+		Credit:      credit,
+	})
+	position.Fees += tc.bot.feeSchedule().OrderFees(legCount, filled)
+	if roll.Side == broker.OptionTypePut {
+		position.PutStrike = roll.NewStrike
+	} else {
+		position.CallStrike = roll.NewStrike
+	}
+	if err := position.TransitionState(step.doneState, step.doneCondition); err != nil {
+		tc.bot.logger.Printf("Failed to complete adjustment for position %s: %v", shortID(position.ID), err)
+	}
+	if step.option != models.OptionNone {
+		position.SetFourthDownOption(step.option)
+	}
+	position.AdjustmentOrderID = ""
+	if err := tc.bot.storage.UpdatePosition(position); err != nil {
+		tc.bot.logger.Printf("Failed to persist roll for position %s: %v", shortID(position.ID), err)
+		return
+	}
+
+	tc.bot.logger.Printf("%s: position %s rolled untested %s %.2f -> %.2f for $%.2f credit (net credit now $%.2f)",
+		step.label, shortID(position.ID), roll.Side, roll.OldStrike, roll.NewStrike, credit, position.GetNetCredit())
+}
+
+// watchAdjustment hands a placed roll or punt to the order manager, which polls it in the
+// background so the trading cycle is not held up while it works. Once the order ends, the
+// stored position is settled under exitMu: an order that executed nothing releases it,
+// otherwise contracts the order did not complete are split off and apply records the fill
+// on the rest. An order whose outcome is unknown keeps its marker for a later cycle to settle.
+func (tc *TradingCycle) watchAdjustment(positionID string, orderID int, limit float64, kind string,
+	apply func(position *models.Position, filled int, credit float64)) {
+	tc.bot.orderManager.WatchOrder(orderID, adjustmentFillTimeout, func(order *broker.Order, err error) {
+		id := shortID(positionID)
+		if err != nil {
+			tc.bot.logger.Printf("CRITICAL: %s order %d for position %s may still be working: %v", kind, orderID, id, err)
+			return
+		}
+
+		tc.bot.exitMu.Lock()
+		defer tc.bot.exitMu.Unlock()
+
+		stored, found := tc.bot.storage.GetPositionByID(positionID)
+		if !found || stored.AdjustmentOrderID != strconv.Itoa(orderID) {
+			tc.bot.logger.Printf("Warning: Position %s no longer holds %s order %d, not applying it", id, kind, orderID)
+			return
+		}
+		position := &stored
+
+		filled, credit := tc.adjustmentFilled(position, order, limit)
+		if filled == 0 {
+			tc.bot.logger.Printf("The %s of position %s did not fill: order %d %s", kind, id, order.ID, order.Status)
+			tc.releaseAdjustment(position)
+			return
+		}
+		if !tc.splitOffUnfilled(position, filled, kind) {
+			return
+		}
+		apply(position, filled, credit)
+	})
+}
+
+// adjustmentFilled returns how many of position's contracts an adjustment order completed
+// and its net price per contract, falling back to limit when the broker reported none. A
+// filled order counts as complete; any other order is read leg by leg, and contracts on
+// legs past the completed rolls are reported as an uneven fill.
+func (tc *TradingCycle) adjustmentFilled(position *models.Position, order *broker.Order, limit float64) (int, float64) {
+	quantity := position.Quantity
+	fill := orders.SummarizeFill(order)
+	if strings.EqualFold(order.Status, "filled") {
+		fill.Quantity = quantity
+	} else {
+		tc.bot.orderManager.ReportUnevenFill(position.ID, order.ID, fill)
+	}
+	price := fill.Price
+	if price == 0 {
+		price = fillPrice(order, limit)
+	}
+	return min(fill.Quantity, quantity), price
+}
+
+// splitOffUnfilled handles an adjustment that completed only filled of a position's
+// contracts. The filled contracts stay on position, which the caller goes on to adjust;
+// the rest, still on the old legs, move to a new position with their share of fees and
+// realized P&L so both are priced, stopped and reconciled on the legs they hold. It
+// returns false if the remainder could not be saved.
+func (tc *TradingCycle) splitOffUnfilled(position *models.Position, filled int, kind string) bool {
+	if filled >= position.Quantity {
+		return true
+	}
+	remainder := position.Clone()
+	remainder.ID = uuid.New().String()
+	remainder.Quantity = position.Quantity - filled
+	remainder.AdjustmentOrderID = ""
+	share := float64(remainder.Quantity) / float64(position.Quantity)
+	remainder.Fees = position.Fees * share
+	remainder.RealizedPnL = position.RealizedPnL * share
+	remainder.DayStartPnL = position.DayStartPnL * share
+	if err := tc.bot.storage.AddPosition(&remainder); err != nil {
+		tc.bot.logger.Printf("CRITICAL: %d contracts of position %s were not part of its partial %s and could not be tracked: %v",
+			remainder.Quantity, shortID(position.ID), kind, err)
+		return false
+	}
+
+	tc.bot.logger.Printf("Partial %s: %d of %d contracts of position %s filled; the other %d stay on the old legs as position %s",
+		kind, filled, position.Quantity, shortID(position.ID), remainder.Quantity, shortID(remainder.ID))
+	position.Fees -= remainder.Fees
+	position.RealizedPnL -= remainder.RealizedPnL
+	position.DayStartPnL -= remainder.DayStartPnL
+	position.Quantity = filled
+	return true
+}
+
+// claimAdjustment marks position as having an adjustment working before its order is
+// placed, so the position monitor and exits leave it alone until the order settles. It
+// returns false when a close order or another adjustment already holds the position.
+func (tc *TradingCycle) claimAdjustment(position *models.Position) bool {
+	tc.bot.exitMu.Lock()
+	defer tc.bot.exitMu.Unlock()
+
+	if stored, found := tc.bot.storage.GetPositionByID(position.ID); found {
+		if stored.ExitOrderID != "" {
+			tc.bot.logger.Printf("Position %s has close order %s, not adjusting", shortID(position.ID), stored.ExitOrderID)
+			return false
+		}
+		if stored.HasWorkingAdjustment() {
+			tc.bot.logger.Printf("Position %s already has adjustment order %s, not adjusting",
+				shortID(position.ID), stored.AdjustmentOrderID)
+			return false
+		}
+	}
+
+	position.AdjustmentOrderID = models.AdjustmentOrderPending
+	if err := tc.bot.storage.UpdatePosition(position); err != nil {
+		tc.bot.logger.Printf("Failed to mark position %s as adjusting, not adjusting: %v", shortID(position.ID), err)
+		position.AdjustmentOrderID = ""
+		return false
+	}
+	return true
+}
+
+// recordAdjustmentOrder replaces the pending marker with the placed order's ID
+func (tc *TradingCycle) recordAdjustmentOrder(position *models.Position, orderID int) {
+	position.AdjustmentOrderID = strconv.Itoa(orderID)
+	if err := tc.bot.storage.UpdatePosition(position); err != nil {
+		tc.bot.logger.Printf("Failed to record adjustment order %d for position %s: %v", orderID, shortID(position.ID), err)
+	}
+}
+
+// releaseAdjustment clears the marker of an adjustment that changed nothing
+func (tc *TradingCycle) releaseAdjustment(position *models.Position) {
+	position.AdjustmentOrderID = ""
+	if err := tc.bot.storage.UpdatePosition(position); err != nil {
+		tc.bot.logger.Printf("CRITICAL: Failed to clear the adjustment marker of position %s, it stays unmonitored: %v",
+			shortID(position.ID), err)
+	}
+}
+
+// settleStaleAdjustment resolves an adjustment marker left by a roll or punt whose outcome
+// was unknown. A recorded order is canceled and confirmed; if it executed nothing the
+// marker is cleared. An order that executed contracts changed the legs in ways the
+// position does not record, so it stays out of monitoring until reconciled by hand.
+func (tc *TradingCycle) settleStaleAdjustment(position *models.Position) {
+	id := shortID(position.ID)
+	if position.AdjustmentOrderID == models.AdjustmentOrderPending {
+		tc.bot.logger.Printf("Warning: Position %s was claimed for an adjustment that never recorded an order, releasing it", id)
+		tc.releaseAdjustment(position)
+		return
+	}
+	orderID, err := strconv.Atoi(position.AdjustmentOrderID)
+	if err != nil {
+		tc.bot.logger.Printf("Warning: Position %s has invalid adjustment order ID %q, releasing it",
+			id, position.AdjustmentOrderID)
+		tc.releaseAdjustment(position)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(tc.bot.ctx, adjustmentCancelTimeout)
+	defer cancel()
+	order, err := tc.bot.orderManager.CancelAndConfirm(ctx, orderID)
+	if err != nil {
+		tc.bot.logger.Printf("CRITICAL: Adjustment order %d for position %s is still unresolved: %v", orderID, id, err)
+		return
+	}
+	for _, leg := range order.Legs {
+		if leg.ExecQuantity > 0 {
+			tc.bot.logger.Printf("CRITICAL: Adjustment order %d for position %s ended %s after executing %s %.0f; "+
+				"the position stays out of monitoring until it is reconciled with the broker",
+				orderID, id, order.Status, leg.OptionSymbol, leg.ExecQuantity)
+			return
+		}
+	}
+	if order.ExecQuantity > 0 || strings.EqualFold(order.Status, "filled") {
+		tc.bot.logger.Printf("CRITICAL: Adjustment order %d for position %s ended %s; "+
+			"the position stays out of monitoring until it is reconciled with the broker", orderID, id, order.Status)
+		return
+	}
+
+	tc.bot.logger.Printf("Adjustment order %d for position %s ended %s without executing, releasing the position",
+		orderID, id, order.Status)
+	tc.releaseAdjustment(position)
+}
+
+// fillPrice returns the average fill price of an order, falling back to its limit price
+func fillPrice(order *broker.Order, limit float64) float64 {
+	if order != nil && order.AvgFillPrice != 0 {
+		return math.Abs(order.AvgFillPrice)
+	}
+	return limit
+}
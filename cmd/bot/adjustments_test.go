@@ -0,0 +1,308 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newAdjustmentTestPosition returns an open 400/460 strangle stored in the test bot
+func newAdjustmentTestPosition(t *testing.T, tb *TestBot, expiration time.Time) *models.Position {
+	t.Helper()
+	position := models.NewPosition("adj-test-position", "SPY", 400, 460, expiration, 1)
+	require.NoError(t, position.TransitionState(models.StateSubmitted, models.ConditionOrderPlaced))
+	position.Quantity = 1
+	position.CreditReceived = 2.50
+	require.NoError(t, position.TransitionState(models.StateOpen, models.ConditionOrderFilled))
+	require.NoError(t, tb.mockStorage.AddPosition(position))
+	return position
+}
+
+func adjustmentTestChain() []broker.Option {
+	return []broker.Option{
+		{Strike: 400, OptionType: "put", Bid: 3.00, Ask: 3.20, Greeks: &broker.Greeks{Delta: -0.30}},
+		{Strike: 435, OptionType: "call", Bid: 1.50, Ask: 1.60, Greeks: &broker.Greeks{Delta: 0.16}},
+		{Strike: 460, OptionType: "call", Bid: 0.20, Ask: 0.30, Greeks: &broker.Greeks{Delta: 0.03}},
+	}
+}
+
+// rollLegs returns the buy-to-close and sell-to-open legs of a single-contract roll
+func rollLegs(oldSymbol, newSymbol string) []broker.OrderLeg {
+	return []broker.OrderLeg{
+		{OptionSymbol: oldSymbol, Side: broker.SideBuyToClose, Quantity: 1},
+		{OptionSymbol: newSymbol, Side: broker.SideSellToOpen, Quantity: 1},
+	}
+}
+
+func orderStatus(id int, status string, avgFill float64) *broker.OrderResponse {
+	resp := &broker.OrderResponse{}
+	resp.Order.ID = id
+	resp.Order.Status = status
+	resp.Order.AvgFillPrice = avgFill
+	return resp
+}
+
+func TestCheckAdjustments_SecondDownRollsUntestedSide(t *testing.T) {
+	tb := createTestBot(t)
+	defer tb.cancel()
+	tb.config.Strategy.Adjustments.Enabled = true
+	tb.config.Strategy.Adjustments.SecondDownThreshold = 10
+
+	expiration := time.Now().UTC().AddDate(0, 0, 30).Truncate(24 * time.Hour)
+	position := newAdjustmentTestPosition(t, tb, expiration)
+	expStr := expiration.Format("2006-01-02")
+
+	tb.mockBroker.On("GetQuote", "SPY").Return(&broker.QuoteItem{Last: 408}, nil)
+	tb.mockBroker.On("GetOptionChainCtx", mock.Anything, "SPY", expStr, true).Return(adjustmentTestChain(), nil)
+	tb.mockBroker.On("GetTickSize", "SPY").Return(0.01, nil)
+
+	oldCall := broker.BuildOptionSymbol("SPY", expiration, broker.OptionTypeCall, 460)
+	newCall := broker.BuildOptionSymbol("SPY", expiration, broker.OptionTypeCall, 435)
+	tb.mockBroker.On("PlaceMultiLegOrderCtx", mock.Anything, rollLegs(oldCall, newCall), broker.NetCredit, 1.30, "day", mock.Anything).
+		Return(orderStatus(101, "open", 0), nil)
+	tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 101).Return(orderStatus(101, "filled", -1.30), nil).
+		Run(func(mock.Arguments) {
+			// The working roll is recorded before the order manager watches it
+			working, _ := tb.mockStorage.GetPositionByID(position.ID)
+			assert.Equal(t, "101", working.AdjustmentOrderID)
+		})
+
+	NewTradingCycle(tb.Bot).checkAdjustmentsForPosition(position)
+	tb.orderManager.WaitForWatches()
+
+	stored, found := tb.mockStorage.GetPositionByID(position.ID)
+	require.True(t, found)
+	assert.Empty(t, stored.AdjustmentOrderID)
+	assert.Equal(t, models.StateFirstDown, stored.GetCurrentState())
+	assert.Equal(t, 435.0, stored.CallStrike)
+	assert.Equal(t, 400.0, stored.PutStrike)
+	require.Len(t, stored.Adjustments, 1)
+	assert.Equal(t, models.AdjustmentRoll, stored.Adjustments[0].Type)
+	assert.Equal(t, 460.0, stored.Adjustments[0].OldStrike)
+	assert.Equal(t, 435.0, stored.Adjustments[0].NewStrike)
+	assert.InDelta(t, 1.30, stored.Adjustments[0].Credit, 1e-9)
+	assert.InDelta(t, 3.80, stored.GetNetCredit(), 1e-9)
+	tb.mockBroker.AssertExpectations(t)
+}
+
+func TestCheckAdjustments_RejectedRollLeavesPositionUnchanged(t *testing.T) {
+	tb := createTestBot(t)
+	defer tb.cancel()
+	tb.config.Strategy.Adjustments.Enabled = true
+	tb.config.Strategy.Adjustments.SecondDownThreshold = 10
+
+	expiration := time.Now().UTC().AddDate(0, 0, 30).Truncate(24 * time.Hour)
+	position := newAdjustmentTestPosition(t, tb, expiration)
+
+	tb.mockBroker.On("GetQuote", "SPY").Return(&broker.QuoteItem{Last: 408}, nil)
+	tb.mockBroker.On("GetOptionChainCtx", mock.Anything, "SPY", expiration.Format("2006-01-02"), true).
+		Return(adjustmentTestChain(), nil)
+	tb.mockBroker.On("GetTickSize", "SPY").Return(0.01, nil)
+	tb.mockBroker.On("PlaceMultiLegOrderCtx", mock.Anything, mock.Anything, broker.NetCredit, 1.30, "day", mock.Anything).
+		Return(orderStatus(201, "open", 0), nil)
+	tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 201).Return(orderStatus(201, "rejected", 0), nil)
+
+	NewTradingCycle(tb.Bot).checkAdjustmentsForPosition(position)
+	tb.orderManager.WaitForWatches()
+
+	stored, found := tb.mockStorage.GetPositionByID(position.ID)
+	require.True(t, found)
+	assert.Equal(t, models.StateSecondDown, stored.GetCurrentState())
+	assert.Equal(t, 460.0, stored.CallStrike)
+	assert.Empty(t, stored.Adjustments)
+	assert.Empty(t, stored.AdjustmentOrderID)
+	tb.mockBroker.AssertExpectations(t)
+}
+
+func TestCheckAdjustments_StaleAdjustmentIsSettledFirst(t *testing.T) {
+	t.Run("order that executed nothing releases the position", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+		tb.config.Strategy.Adjustments.Enabled = true
+
+		position := newAdjustmentTestPosition(t, tb, time.Now().UTC().AddDate(0, 0, 30).Truncate(24*time.Hour))
+		position.AdjustmentOrderID = "501"
+		require.NoError(t, tb.mockStorage.UpdatePosition(position))
+
+		tb.mockBroker.On("CancelOrderCtx", mock.Anything, 501).Return(orderStatus(501, "ok", 0), nil)
+		tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 501).Return(orderStatus(501, "canceled", 0), nil)
+
+		NewTradingCycle(tb.Bot).checkAdjustmentsForPosition(position)
+
+		stored, found := tb.mockStorage.GetPositionByID(position.ID)
+		require.True(t, found)
+		assert.Empty(t, stored.AdjustmentOrderID)
+		tb.mockBroker.AssertNotCalled(t, "GetQuote", mock.Anything)
+	})
+
+	t.Run("order that executed legs keeps the position out of monitoring", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+		tb.config.Strategy.Adjustments.Enabled = true
+
+		position := newAdjustmentTestPosition(t, tb, time.Now().UTC().AddDate(0, 0, 30).Truncate(24*time.Hour))
+		position.AdjustmentOrderID = "502"
+		require.NoError(t, tb.mockStorage.UpdatePosition(position))
+
+		filled := orderStatus(502, "filled", 1.30)
+		filled.Order.Legs = []broker.OrderLegStatus{
+			{OptionSymbol: "SPY_OLD", Side: string(broker.SideBuyToClose), Quantity: 1, ExecQuantity: 1},
+			{OptionSymbol: "SPY_NEW", Side: string(broker.SideSellToOpen), Quantity: 1, ExecQuantity: 1},
+		}
+		tb.mockBroker.On("CancelOrderCtx", mock.Anything, 502).Return(nil, errors.New("order already filled"))
+		tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 502).Return(filled, nil)
+
+		NewTradingCycle(tb.Bot).checkAdjustmentsForPosition(position)
+
+		stored, found := tb.mockStorage.GetPositionByID(position.ID)
+		require.True(t, found)
+		assert.Equal(t, "502", stored.AdjustmentOrderID)
+		assert.False(t, isMonitoredPosition(&stored))
+	})
+}
+
+func TestCheckAdjustments_WorkingRollIsLeftToItsWatch(t *testing.T) {
+	tb := createTestBot(t)
+	defer tb.cancel()
+	tb.config.Strategy.Adjustments.Enabled = true
+	tb.config.Strategy.Adjustments.SecondDownThreshold = 10
+
+	expiration := time.Now().UTC().AddDate(0, 0, 30).Truncate(24 * time.Hour)
+	position := newAdjustmentTestPosition(t, tb, expiration)
+
+	tb.mockBroker.On("GetQuote", "SPY").Return(&broker.QuoteItem{Last: 408}, nil)
+	tb.mockBroker.On("GetOptionChainCtx", mock.Anything, "SPY", expiration.Format("2006-01-02"), true).
+		Return(adjustmentTestChain(), nil)
+	tb.mockBroker.On("GetTickSize", "SPY").Return(0.01, nil)
+	tb.mockBroker.On("PlaceMultiLegOrderCtx", mock.Anything, mock.Anything, broker.NetCredit, 1.30, "day", mock.Anything).
+		Return(orderStatus(601, "open", 0), nil)
+	tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 601).Return(orderStatus(601, "open", 0), nil)
+
+	// The cycle returns while the roll is still working
+	NewTradingCycle(tb.Bot).checkAdjustmentsForPosition(position)
+	require.True(t, tb.orderManager.IsWatching(601))
+
+	// The next cycle leaves it to the watch instead of canceling it as stale
+	NewTradingCycle(tb.Bot).checkAdjustmentsForPosition(position)
+	stored, found := tb.mockStorage.GetPositionByID(position.ID)
+	require.True(t, found)
+	assert.Equal(t, "601", stored.AdjustmentOrderID)
+	tb.mockBroker.AssertNotCalled(t, "CancelOrderCtx", mock.Anything, mock.Anything)
+
+	// Shutdown ends the watch and leaves the marker for the next run to settle
+	close(tb.stop)
+	tb.orderManager.WaitForWatches()
+	assert.False(t, tb.orderManager.IsWatching(601))
+	stored, _ = tb.mockStorage.GetPositionByID(position.ID)
+	assert.Equal(t, "601", stored.AdjustmentOrderID)
+}
+
+func TestCheckAdjustments_PartialRollSplitsOffUnrolledContracts(t *testing.T) {
+	tb := createTestBot(t)
+	defer tb.cancel()
+	tb.config.Strategy.Adjustments.Enabled = true
+	tb.config.Strategy.Adjustments.SecondDownThreshold = 10
+
+	expiration := time.Now().UTC().AddDate(0, 0, 30).Truncate(24 * time.Hour)
+	position := newAdjustmentTestPosition(t, tb, expiration)
+	position.Quantity = 3
+	require.NoError(t, tb.mockStorage.UpdatePosition(position))
+
+	tb.mockBroker.On("GetQuote", "SPY").Return(&broker.QuoteItem{Last: 408}, nil)
+	tb.mockBroker.On("GetOptionChainCtx", mock.Anything, "SPY", expiration.Format("2006-01-02"), true).
+		Return(adjustmentTestChain(), nil)
+	tb.mockBroker.On("GetTickSize", "SPY").Return(0.01, nil)
+
+	oldCall := broker.BuildOptionSymbol("SPY", expiration, broker.OptionTypeCall, 460)
+	newCall := broker.BuildOptionSymbol("SPY", expiration, broker.OptionTypeCall, 435)
+	tb.mockBroker.On("PlaceMultiLegOrderCtx", mock.Anything, mock.Anything, broker.NetCredit, 1.30, "day", mock.Anything).
+		Return(orderStatus(401, "open", 0), nil)
+	// The order was canceled after two of the three rolls completed
+	canceled := orderStatus(401, "canceled", 0)
+	canceled.Order.Legs = []broker.OrderLegStatus{
+		{OptionSymbol: oldCall, Side: string(broker.SideBuyToClose), Quantity: 3, ExecQuantity: 2, AvgFillPrice: 0.25},
+		{OptionSymbol: newCall, Side: string(broker.SideSellToOpen), Quantity: 3, ExecQuantity: 2, AvgFillPrice: 1.55},
+	}
+	tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 401).Return(canceled, nil)
+
+	NewTradingCycle(tb.Bot).checkAdjustmentsForPosition(position)
+	tb.orderManager.WaitForWatches()
+
+	stored, found := tb.mockStorage.GetPositionByID(position.ID)
+	require.True(t, found)
+	assert.Equal(t, 2, stored.Quantity)
+	assert.Equal(t, 435.0, stored.CallStrike)
+	require.Len(t, stored.Adjustments, 1)
+	assert.InDelta(t, 1.30, stored.Adjustments[0].Credit, 1e-9)
+
+	positions := tb.mockStorage.GetCurrentPositions()
+	require.Len(t, positions, 2)
+	for _, p := range positions {
+		if p.ID == position.ID {
+			continue
+		}
+		assert.Equal(t, 1, p.Quantity)
+		assert.Equal(t, 460.0, p.CallStrike)
+		assert.Equal(t, models.StateSecondDown, p.GetCurrentState())
+		assert.Empty(t, p.Adjustments)
+	}
+}
+
+func TestCheckAdjustments_BreachedStrikeFormsThirdDownStraddle(t *testing.T) {
+	tb := createTestBot(t)
+	defer tb.cancel()
+	tb.config.Strategy.Adjustments.Enabled = true
+	tb.config.Strategy.Adjustments.SecondDownThreshold = 10
+
+	expiration := time.Now().UTC().AddDate(0, 0, 30).Truncate(24 * time.Hour)
+	position := newAdjustmentTestPosition(t, tb, expiration)
+	chain := append(adjustmentTestChain(),
+		broker.Option{Strike: 400, OptionType: "call", Bid: 8.90, Ask: 9.10, Greeks: &broker.Greeks{Delta: 0.52}})
+
+	tb.mockBroker.On("GetQuote", "SPY").Return(&broker.QuoteItem{Last: 398}, nil)
+	tb.mockBroker.On("GetOptionChainCtx", mock.Anything, "SPY", expiration.Format("2006-01-02"), false).Return(chain, nil)
+	tb.mockBroker.On("GetTickSize", "SPY").Return(0.01, nil)
+
+	oldCall := broker.BuildOptionSymbol("SPY", expiration, broker.OptionTypeCall, 460)
+	newCall := broker.BuildOptionSymbol("SPY", expiration, broker.OptionTypeCall, 400)
+	tb.mockBroker.On("PlaceMultiLegOrderCtx", mock.Anything, rollLegs(oldCall, newCall), broker.NetCredit, 8.75, "day", mock.Anything).
+		Return(orderStatus(301, "open", 0), nil)
+	tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 301).Return(orderStatus(301, "filled", 8.75), nil)
+
+	NewTradingCycle(tb.Bot).checkAdjustmentsForPosition(position)
+	tb.orderManager.WaitForWatches()
+
+	stored, found := tb.mockStorage.GetPositionByID(position.ID)
+	require.True(t, found)
+	assert.Equal(t, models.StateThirdDown, stored.GetCurrentState())
+	assert.Equal(t, 400.0, stored.PutStrike)
+	assert.Equal(t, 400.0, stored.CallStrike)
+	require.Len(t, stored.Adjustments, 1)
+	assert.Contains(t, stored.Adjustments[0].Description, "Third Down")
+	assert.InDelta(t, 8.75, stored.Adjustments[0].Credit, 1e-9)
+	assert.Equal(t, 0.25, tb.strategy.ProfitTargetFor(&stored))
+	tb.mockBroker.AssertExpectations(t)
+}
+
+func TestCheckAdjustments_UnchallengedPositionEntersFirstDownOnly(t *testing.T) {
+	tb := createTestBot(t)
+	defer tb.cancel()
+	tb.config.Strategy.Adjustments.Enabled = true
+	tb.config.Strategy.Adjustments.SecondDownThreshold = 10
+
+	position := newAdjustmentTestPosition(t, tb, time.Now().UTC().AddDate(0, 0, 30))
+	tb.mockBroker.On("GetQuote", "SPY").Return(&broker.QuoteItem{Last: 430}, nil)
+
+	NewTradingCycle(tb.Bot).checkAdjustmentsForPosition(position)
+
+	stored, found := tb.mockStorage.GetPositionByID(position.ID)
+	require.True(t, found)
+	assert.Equal(t, models.StateFirstDown, stored.GetCurrentState())
+	assert.Empty(t, stored.Adjustments)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/dashboard"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/strategy"
+)
+
+const (
+	// commandQueueSize bounds dashboard commands waiting for the main loop
+	commandQueueSize = 8
+	// heartbeatInterval is how often the main loop reports to dashboard clients between cycles
+	heartbeatInterval = 15 * time.Second
+)
+
+// applyCommand carries out an operator command from the dashboard. It runs on the main
+// loop between trading cycles, so a command never interleaves with a cycle's decisions.
+func (b *Bot) applyCommand(cmd dashboard.Command) {
+	b.logger.Printf("Dashboard command %s requested by %s (position=%s reason=%q)",
+		cmd.Kind, cmd.RequestedBy, shortID(cmd.PositionID), cmd.Reason)
+
+	var message string
+	var err error
+	switch cmd.Kind {
+	case dashboard.CommandClosePosition:
+		message, err = b.closePositionManually(cmd.PositionID)
+	case dashboard.CommandPauseEntries:
+		message, err = b.pauseEntries(cmd)
+	case dashboard.CommandResumeEntries:
+		message, err = b.resumeEntries()
+	case dashboard.CommandReconcile:
+		message, err = b.reconcileNow()
+	default:
+		err = fmt.Errorf("unknown command %q", cmd.Kind)
+	}
+
+	if err != nil {
+		b.logger.Printf("Dashboard command %s failed: %v", cmd.Kind, err)
+	} else {
+		b.logger.Printf("Dashboard command %s applied: %s", cmd.Kind, message)
+	}
+	cmd.Respond(message, err)
+}
+
+// closePositionManually sends a close order for one position with a manual exit reason
+func (b *Bot) closePositionManually(id string) (string, error) {
+	position, found := b.storage.GetPositionByID(id)
+	if !found {
+		return "", fmt.Errorf("position %s not found", id)
+	}
+	if position.ExitOrderID != "" {
+		return "", fmt.Errorf("position %s already has close order %s", shortID(id), position.ExitOrderID)
+	}
+
+	NewTradingCycle(b).executeExit(&position, strategy.ExitReasonManual)
+
+	stored, found := b.storage.GetPositionByID(id)
+	if !found || stored.ExitOrderID == "" {
+		return "", fmt.Errorf("no close order was placed for position %s in state %s; see the bot log",
+			shortID(id), position.GetCurrentState())
+	}
+	return fmt.Sprintf("Close order %s placed for position %s", stored.ExitOrderID, shortID(id)), nil
+}
+
+// pauseEntries stops new entries until they are resumed; the pause survives restarts
+func (b *Bot) pauseEntries(cmd dashboard.Command) (string, error) {
+	if pause := b.storage.GetEntryPause(); pause != nil {
+		return fmt.Sprintf("Entries already paused since %s", pause.PausedAt.Format(time.RFC3339)), nil
+	}
+	pause := &models.EntryPause{
+		Reason:   cmd.Reason,
+		PausedAt: time.Now().UTC(),
+		PausedBy: cmd.RequestedBy,
+	}
+	if err := b.storage.SetEntryPause(pause); err != nil {
+		return "", fmt.Errorf("failed to persist entry pause: %w", err)
+	}
+	return "New entries paused", nil
+}
+
+// resumeEntries lifts an entry pause
+func (b *Bot) resumeEntries() (string, error) {
+	if b.storage.GetEntryPause() == nil {
+		return "Entries were not paused", nil
+	}
+	if err := b.storage.SetEntryPause(nil); err != nil {
+		return "", fmt.Errorf("failed to clear entry pause: %w", err)
+	}
+	return "New entries resumed", nil
+}
+
+// reconcileNow runs the trading cycle's reconciliation outside the cycle
+func (b *Bot) reconcileNow() (string, error) {
+	before := b.storage.GetCurrentPositions()
+	after, err := NewTradingCycle(b).reconciler.reconcile(before)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Reconciled %d position(s); %d remain active", len(before), len(after)), nil
+}
+
+// entriesPaused reports whether an operator has paused new entries, logging the pause
+func (tc *TradingCycle) entriesPaused() bool {
+	pause := tc.bot.storage.GetEntryPause()
+	if pause == nil {
+		return false
+	}
+	reason := pause.Reason
+	if reason == "" {
+		reason = "no reason given"
+	}
+	tc.bot.logger.Printf("New entries paused since %s by %s (%s)",
+		pause.PausedAt.Format(time.RFC3339), pause.PausedBy, reason)
+	return true
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/dashboard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// runCommand applies a dashboard command and returns the bot's outcome
+func runCommand(t *testing.T, tb *TestBot, cmd dashboard.Command) dashboard.CommandResult {
+	t.Helper()
+	result := make(chan dashboard.CommandResult, 1)
+	cmd.Result = result
+	tb.applyCommand(cmd)
+	select {
+	case res := <-result:
+		return res
+	default:
+		t.Fatal("Expected the bot to respond to the command")
+		return dashboard.CommandResult{}
+	}
+}
+
+func TestApplyCommand_PauseAndResumeEntries(t *testing.T) {
+	tb := createTestBot(t)
+	defer tb.cancel()
+	tc := NewTradingCycle(tb.Bot)
+	assert.False(t, tc.entriesPaused())
+
+	res := runCommand(t, tb, dashboard.Command{Kind: dashboard.CommandPauseEntries, Reason: "FOMC at 14:00", RequestedBy: "10.0.0.5"})
+	require.NoError(t, res.Err)
+	pause := tb.mockStorage.GetEntryPause()
+	require.NotNil(t, pause)
+	assert.Equal(t, "FOMC at 14:00", pause.Reason)
+	assert.Equal(t, "10.0.0.5", pause.PausedBy)
+	assert.True(t, tc.entriesPaused())
+
+	// Pausing again keeps the original pause
+	res = runCommand(t, tb, dashboard.Command{Kind: dashboard.CommandPauseEntries, Reason: "again"})
+	require.NoError(t, res.Err)
+	assert.Contains(t, res.Message, "already paused")
+	assert.Equal(t, "FOMC at 14:00", tb.mockStorage.GetEntryPause().Reason)
+
+	res = runCommand(t, tb, dashboard.Command{Kind: dashboard.CommandResumeEntries})
+	require.NoError(t, res.Err)
+	assert.Nil(t, tb.mockStorage.GetEntryPause())
+	assert.False(t, tc.entriesPaused())
+}
+
+func TestApplyCommand_PauseFailsWhenNotPersisted(t *testing.T) {
+	tb := createTestBot(t)
+	defer tb.cancel()
+	tb.mockStorage.SetSaveError(errors.New("disk full"))
+
+	res := runCommand(t, tb, dashboard.Command{Kind: dashboard.CommandPauseEntries})
+	require.Error(t, res.Err)
+	assert.Contains(t, res.Err.Error(), "disk full")
+}
+
+func TestApplyCommand_ClosePosition(t *testing.T) {
+	t.Run("places a close order at the current value", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+		defer close(tb.stop) // Ends the close order poller
+		expiration := time.Now().UTC().AddDate(0, 0, 30).Truncate(24 * time.Hour)
+		expStr := expiration.Format("2006-01-02")
+		position := newAdjustmentTestPosition(t, tb, expiration)
+
+		tb.mockBroker.On("GetOptionChainCtx", mock.Anything, "SPY", expStr, false).Return([]broker.Option{
+			{Strike: 400, OptionType: "put", Bid: 1.15, Ask: 1.25},
+			{Strike: 460, OptionType: "call", Bid: 0.55, Ask: 0.65},
+		}, nil)
+		tb.mockBroker.On("GetTickSize", "SPY").Return(0.01, nil)
+		tb.mockBroker.On("CloseStranglePositionCtx", mock.Anything, "SPY", 400.0, 460.0, expStr, 1, 1.80, mock.Anything).
+			Return(orderStatus(701, "open", 0), nil)
+		tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 701).Return(orderStatus(701, "open", 0), nil).Maybe()
+
+		res := runCommand(t, tb, dashboard.Command{Kind: dashboard.CommandClosePosition, PositionID: position.ID})
+		require.NoError(t, res.Err)
+		assert.Contains(t, res.Message, "701")
+
+		stored, found := tb.mockStorage.GetPositionByID(position.ID)
+		require.True(t, found)
+		assert.Equal(t, "701", stored.ExitOrderID)
+	})
+
+	t.Run("position with a working close is refused", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+		position := newAdjustmentTestPosition(t, tb, time.Now().AddDate(0, 0, 30))
+		position.ExitOrderID = "555"
+		require.NoError(t, tb.mockStorage.UpdatePosition(position))
+
+		res := runCommand(t, tb, dashboard.Command{Kind: dashboard.CommandClosePosition, PositionID: position.ID})
+		require.Error(t, res.Err)
+		assert.Contains(t, res.Err.Error(), "555")
+		tb.mockBroker.AssertNotCalled(t, "CloseStranglePositionCtx", mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown position is refused", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+
+		res := runCommand(t, tb, dashboard.Command{Kind: dashboard.CommandClosePosition, PositionID: "missing"})
+		require.Error(t, res.Err)
+		assert.Contains(t, res.Err.Error(), "not found")
+	})
+}
+
+func TestApplyCommand_ReconcileReportsBrokerFailure(t *testing.T) {
+	tb := createTestBot(t)
+	defer tb.cancel()
+	tb.mockBroker.On("GetPositionsCtx", mock.Anything).Return([]broker.PositionItem(nil), errors.New("broker down")).Once()
+
+	res := runCommand(t, tb, dashboard.Command{Kind: dashboard.CommandReconcile})
+	require.Error(t, res.Err)
+	assert.Contains(t, res.Err.Error(), "broker down")
+
+	tb.mockBroker.On("GetPositionsCtx", mock.Anything).Return([]broker.PositionItem{}, nil)
+	res = runCommand(t, tb, dashboard.Command{Kind: dashboard.CommandReconcile})
+	require.NoError(t, res.Err)
+	assert.Contains(t, res.Message, "Reconciled 0 position(s)")
+}
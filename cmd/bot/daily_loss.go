@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/strategy"
+)
+
+// tradingDay returns today's New York trading day as YYYY-MM-DD, the key used for daily P&L
+func (tc *TradingCycle) tradingDay() string {
+	return tc.tradingDayOf(time.Now())
+}
+
+// tradingDayOf returns the New York trading day of t as YYYY-MM-DD
+func (tc *TradingCycle) tradingDayOf(t time.Time) string {
+	if tc.bot.nyLocation != nil {
+		t = t.In(tc.bot.nyLocation)
+	}
+	return t.Format("2006-01-02")
+}
+
+// checkDailyLossLimit enforces risk.max_daily_loss. It returns true when new entries are
+// halted for today, either by a halt recorded earlier in the session (possibly before a
+// restart) or by a breach detected now. A new breach is persisted and, when
+// risk.flatten_on_daily_loss is set, closes all open positions.
+func (tc *TradingCycle) checkDailyLossLimit(positions []models.Position) bool {
+	today := tc.tradingDay()
+	if halt := tc.bot.storage.GetTradingHalt(); halt.ActiveOn(today) {
+		tc.bot.logger.Printf("Trading halted for %s: %s", today, halt.Reason)
+		return true
+	}
+
+	limitPct := tc.bot.config.Risk.MaxDailyLoss
+	if limitPct <= 0 {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(tc.bot.ctx, 10*time.Second)
+	defer cancel()
+	equity, err := tc.bot.broker.GetAccountBalanceCtx(ctx)
+	if err != nil || equity <= 0 {
+		tc.bot.logger.Printf("Warning: Could not check daily loss limit, account equity unavailable (%.2f): %v", equity, err)
+		return false
+	}
+
+	dailyPnL := tc.dailyPnL(today, positions)
+
+	// Equity already reflects today's P&L; measure the limit against the start of the day
+	startEquity := equity - dailyPnL
+	if startEquity <= 0 {
+		startEquity = equity
+	}
+	lossLimit := startEquity * limitPct / 100
+	if dailyPnL > -lossLimit {
+		tc.bot.logger.Printf("Daily P&L: $%.2f (loss limit -$%.2f)", dailyPnL, lossLimit)
+		return false
+	}
+
+	halt := &models.TradingHalt{
+		Date: today,
+		Reason: fmt.Sprintf("daily loss $%.2f exceeds max_daily_loss %.1f%% of equity ($%.2f)",
+			-dailyPnL, limitPct, lossLimit),
+		TriggeredAt: time.Now().UTC(),
+		DailyPnL:    dailyPnL,
+		LossLimit:   lossLimit,
+		Flattened:   tc.bot.config.Risk.FlattenOnDailyLoss,
+	}
+	tc.bot.logger.Printf("CRITICAL: Daily loss limit breached, halting new entries for %s: %s", today, halt.Reason)
+	if err := tc.bot.storage.SetTradingHalt(halt); err != nil {
+		tc.bot.logger.Printf("CRITICAL: Failed to persist trading halt, it will not survive a restart: %v", err)
+	}
+
+	if halt.Flattened {
+		tc.flattenPositions()
+	}
+	return true
+}
+
+// dailyPnL returns the P&L made today: realized P&L booked today plus the change in each
+// open position's unrealized P&L since its day-start mark. A position closed today books
+// its whole P&L at the close, so the mark it carried into today is taken back out.
+func (tc *TradingCycle) dailyPnL(today string, positions []models.Position) float64 {
+	total := tc.bot.storage.GetDailyPnL(today)
+	for _, closed := range tc.bot.storage.GetHistory() {
+		if closed.DayStartDate == today && tc.tradingDayOf(closed.ExitDate) == today {
+			total -= closed.DayStartPnL
+		}
+	}
+	for i := range positions {
+		switch positions[i].GetCurrentState() {
+		case models.StateIdle, models.StateSubmitted, models.StateClosed:
+			continue
+		}
+		pnl, err := tc.bot.strategy.CalculatePositionPnL(&positions[i])
+		if err != nil {
+			tc.bot.logger.Printf("Warning: Using stored P&L for position %s in daily loss check: %v",
+				shortID(positions[i].ID), err)
+			pnl = positions[i].CurrentPnL
+		}
+		total += pnl - tc.dayStartMark(&positions[i], today, pnl)
+	}
+	return total
+}
+
+// dayStartMark returns the unrealized P&L a position started today with. The first check
+// of the day marks it: zero for a position entered today, its current P&L otherwise. The
+// mark is persisted so checks after a restart measure from the same point.
+func (tc *TradingCycle) dayStartMark(position *models.Position, today string, pnl float64) float64 {
+	if position.DayStartDate == today {
+		return position.DayStartPnL
+	}
+	mark := pnl
+	if tc.tradingDayOf(position.EntryDate) == today {
+		mark = 0
+	}
+
+	// Exits write positions under exitMu; mark the stored copy so neither write is lost
+	tc.bot.exitMu.Lock()
+	defer tc.bot.exitMu.Unlock()
+	stored, found := tc.bot.storage.GetPositionByID(position.ID)
+	if !found {
+		return mark
+	}
+	if stored.DayStartDate != today {
+		stored.DayStartDate, stored.DayStartPnL = today, mark
+		if err := tc.bot.storage.UpdatePosition(&stored); err != nil {
+			tc.bot.logger.Printf("Warning: Failed to persist day-start P&L for position %s: %v", shortID(position.ID), err)
+		}
+	}
+	position.DayStartDate, position.DayStartPnL = stored.DayStartDate, stored.DayStartPnL
+	return position.DayStartPnL
+}
+
+// flattenPositions closes every open position after a daily loss breach. Positions are
+// re-read from storage so closes already placed this cycle are not sent twice.
+func (tc *TradingCycle) flattenPositions() {
+	positions := tc.bot.storage.GetCurrentPositions()
+	tc.bot.logger.Printf("Flattening %d position(s) after daily loss limit breach", len(positions))
+	for i := range positions {
+		if positions[i].ExitOrderID != "" {
+			tc.bot.logger.Printf("Position %s already has close order %s, skipping",
+				shortID(positions[i].ID), positions[i].ExitOrderID)
+			continue
+		}
+		tc.executeExit(&positions[i], strategy.ExitReasonDailyLoss)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDailyLossLimit(t *testing.T) {
+	t.Run("halt recorded today blocks entries without broker calls", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+		tc := NewTradingCycle(tb.Bot)
+		require.NoError(t, tb.mockStorage.SetTradingHalt(&models.TradingHalt{Date: tc.tradingDay(), Reason: "earlier breach"}))
+
+		assert.True(t, tc.checkDailyLossLimit(nil))
+		tb.mockBroker.AssertNotCalled(t, "GetAccountBalanceCtx", mock.Anything)
+	})
+
+	t.Run("halt from a previous day is ignored", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+		tb.config.Risk.MaxDailyLoss = 2.0
+		require.NoError(t, tb.mockStorage.SetTradingHalt(&models.TradingHalt{Date: "2020-01-02", Reason: "old breach"}))
+		tb.mockBroker.On("GetAccountBalanceCtx", mock.Anything).Return(100000.0, nil)
+		tc := NewTradingCycle(tb.Bot)
+		tb.mockStorage.SetDailyPnL(tc.tradingDay(), -500)
+
+		assert.False(t, tc.checkDailyLossLimit(nil))
+		assert.Equal(t, "2020-01-02", tb.mockStorage.GetTradingHalt().Date)
+	})
+
+	t.Run("realized loss over the limit persists a halt", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+		tb.config.Risk.MaxDailyLoss = 2.0
+		// Equity after a $3,000 loss on a $100,000 account; the limit is 2% of $100,000
+		tb.mockBroker.On("GetAccountBalanceCtx", mock.Anything).Return(97000.0, nil)
+		tc := NewTradingCycle(tb.Bot)
+		tb.mockStorage.SetDailyPnL(tc.tradingDay(), -3000)
+
+		assert.True(t, tc.checkDailyLossLimit(nil))
+
+		halt := tb.mockStorage.GetTradingHalt()
+		require.NotNil(t, halt)
+		assert.Equal(t, tc.tradingDay(), halt.Date)
+		assert.InDelta(t, -3000, halt.DailyPnL, 0.001)
+		assert.InDelta(t, 2000, halt.LossLimit, 0.001)
+		assert.False(t, halt.Flattened)
+		assert.Contains(t, halt.Reason, "max_daily_loss")
+
+		// A second cycle uses the stored halt
+		assert.True(t, tc.checkDailyLossLimit(nil))
+		tb.mockBroker.AssertNumberOfCalls(t, "GetAccountBalanceCtx", 1)
+	})
+
+	t.Run("unrealized loss counts and flatten skips pending closes", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+		tb.config.Risk.MaxDailyLoss = 2.0
+		tb.config.Risk.FlattenOnDailyLoss = true
+
+		expiration := time.Now().AddDate(0, 0, 30)
+		position := newAdjustmentTestPosition(t, tb, expiration)
+		position.ExitOrderID = "555" // Close already working from this cycle's exit check
+		require.NoError(t, tb.mockStorage.UpdatePosition(position))
+
+		// $250 credit against $3,250 to close: $3,000 unrealized loss
+		tb.mockBroker.On("GetOptionChainCtx", mock.Anything, "SPY", expiration.Format("2006-01-02"), false).Return([]broker.Option{
+			{Strike: 400, OptionType: "put", Bid: 15.25, Ask: 15.75},
+			{Strike: 460, OptionType: "call", Bid: 16.75, Ask: 17.25},
+		}, nil)
+		tb.mockBroker.On("GetAccountBalanceCtx", mock.Anything).Return(97000.0, nil)
+		tc := NewTradingCycle(tb.Bot)
+
+		assert.True(t, tc.checkDailyLossLimit(tb.mockStorage.GetCurrentPositions()))
+
+		halt := tb.mockStorage.GetTradingHalt()
+		require.NotNil(t, halt)
+		assert.True(t, halt.Flattened)
+		assert.InDelta(t, -3000, halt.DailyPnL, 0.001)
+		tb.mockBroker.AssertNotCalled(t, "CloseStranglePositionCtx", mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("carried-over position counts only today's change", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+		tb.config.Risk.MaxDailyLoss = 2.0
+
+		expiration := time.Now().AddDate(0, 0, 30)
+		position := newAdjustmentTestPosition(t, tb, expiration)
+		position.EntryDate = time.Now().AddDate(0, 0, -3)
+		require.NoError(t, tb.mockStorage.UpdatePosition(position))
+
+		// $250 credit against $3,250 to close: $3,000 unrealized loss, most of it from earlier days
+		tb.mockBroker.On("GetOptionChainCtx", mock.Anything, "SPY", expiration.Format("2006-01-02"), false).Return([]broker.Option{
+			{Strike: 400, OptionType: "put", Bid: 15.25, Ask: 15.75},
+			{Strike: 460, OptionType: "call", Bid: 16.75, Ask: 17.25},
+		}, nil)
+		tb.mockBroker.On("GetAccountBalanceCtx", mock.Anything).Return(97000.0, nil)
+		tc := NewTradingCycle(tb.Bot)
+		today := tc.tradingDay()
+
+		// The first check of the day marks the position where it stands
+		assert.InDelta(t, 0, tc.dailyPnL(today, tb.mockStorage.GetCurrentPositions()), 0.001)
+		stored, found := tb.mockStorage.GetPositionByID(position.ID)
+		require.True(t, found)
+		assert.Equal(t, today, stored.DayStartDate)
+		assert.InDelta(t, -3000, stored.DayStartPnL, 0.001)
+
+		// A mark from the morning leaves only the move since then
+		stored.DayStartPnL = -2800
+		require.NoError(t, tb.mockStorage.UpdatePosition(&stored))
+		assert.InDelta(t, -200, tc.dailyPnL(today, tb.mockStorage.GetCurrentPositions()), 0.001)
+		assert.False(t, tc.checkDailyLossLimit(tb.mockStorage.GetCurrentPositions()))
+		assert.Nil(t, tb.mockStorage.GetTradingHalt())
+	})
+
+	t.Run("position closed today books only the change since its mark", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+		tc := NewTradingCycle(tb.Bot)
+		today := tc.tradingDay()
+
+		position := newAdjustmentTestPosition(t, tb, time.Now().AddDate(0, 0, 30))
+		position.DayStartDate = today
+		position.DayStartPnL = -2900
+		require.NoError(t, tb.mockStorage.UpdatePosition(position))
+		require.NoError(t, tb.mockStorage.ClosePositionByID(position.ID, -3000, "manual"))
+
+		assert.InDelta(t, -100, tc.dailyPnL(today, nil), 0.001)
+	})
+
+	t.Run("equity unavailable does not halt", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+		tb.config.Risk.MaxDailyLoss = 2.0
+		tb.mockBroker.On("GetAccountBalanceCtx", mock.Anything).Return(0.0, errors.New("timeout"))
+		tc := NewTradingCycle(tb.Bot)
+		tb.mockStorage.SetDailyPnL(tc.tradingDay(), -50000)
+
+		assert.False(t, tc.checkDailyLossLimit(nil))
+		assert.Nil(t, tb.mockStorage.GetTradingHalt())
+	})
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/strategy"
+	"github.com/eddiefleurent/scranton_strangler/internal/util"
+)
+
+// fourthDownLabel prefixes every Fourth Down adjustment description and log line
+const fourthDownLabel = "Fourth Down"
+
+// fourthDownInvert rolls the untested side past the tested strike and stays in Fourth Down
+var fourthDownInvert = rollStep{fourthDownLabel + " (Option A)", models.ConditionExecuteAdjustment,
+	models.StateFourthDown, models.ConditionStrangleInverted, models.OptionA}
+
+// enterFourthDown moves a Third Down position to Fourth Down and runs the decision engine
+func (tc *TradingCycle) enterFourthDown(position *models.Position, spot float64) {
+	tc.bot.logger.Printf("Position %s nearing adjusted breakeven: spot %.2f, net credit $%.2f (Put %.2f / Call %.2f)",
+		shortID(position.ID), spot, position.GetNetCredit(), position.PutStrike, position.CallStrike)
+	if err := position.TransitionState(models.StateFourthDown, models.ConditionAdjustmentFailed); err != nil {
+		tc.bot.logger.Printf("Failed to enter Fourth Down for position %s: %v", shortID(position.ID), err)
+		return
+	}
+	if err := tc.bot.storage.UpdatePosition(position); err != nil {
+		tc.bot.logger.Printf("Failed to persist Fourth Down for position %s: %v", shortID(position.ID), err)
+		return
+	}
+	tc.runFourthDown(position, spot)
+}
+
+// runFourthDown picks Option A, B or C and executes it. Options A and C fall back to
+// holding the straddle (Option B) when no acceptable order can be built. The option is
+// persisted once carried out, so an order that does not fill is decided again next cycle.
+func (tc *TradingCycle) runFourthDown(position *models.Position, spot float64) {
+	option, reason := tc.bot.strategy.ChooseFourthDownOption(position, spot)
+	tc.bot.logger.Printf("Fourth Down decision for position %s: %s - %s", shortID(position.ID), option, reason)
+
+	switch option {
+	case models.OptionA:
+		tested, _ := strategy.TestedSide(position, spot, 0)
+		roll, err := tc.bot.strategy.FindInvertedRoll(position, tested, spot)
+		if err == nil {
+			tc.executeRoll(position, roll, fourthDownInvert)
+			return
+		}
+		tc.bot.logger.Printf("Option A unavailable for position %s: %v", shortID(position.ID), err)
+		reason = fmt.Sprintf("Option A unavailable (%v)", err)
+	case models.OptionC:
+		punt, err := tc.bot.strategy.FindPuntRoll(position, spot)
+		if err == nil {
+			tc.puntPosition(position, punt)
+			return
+		}
+		tc.bot.logger.Printf("Option C unavailable for position %s: %v", shortID(position.ID), err)
+		reason = fmt.Sprintf("Option C unavailable (%v)", err)
+	}
+
+	tc.holdStraddle(position, reason)
+}
+
+// holdStraddle records Option B: no orders are placed and the position is held
+// under the Option B time limit and profit target.
+func (tc *TradingCycle) holdStraddle(position *models.Position, reason string) {
+	position.SetFourthDownOption(models.OptionB)
+	position.Adjustments = append(position.Adjustments, models.Adjustment{
+		Date:        time.Now().UTC(),
+		Type:        models.AdjustmentHold,
+		Description: fmt.Sprintf("%s (Option B): holding %.2f/%.2f - %s", fourthDownLabel, position.PutStrike, position.CallStrike, reason),
+		OldStrike:   position.PutStrike,
+		NewStrike:   position.CallStrike,
+	})
+	if err := tc.bot.storage.UpdatePosition(position); err != nil {
+		tc.bot.logger.Printf("Failed to persist Option B for position %s: %v", shortID(position.ID), err)
+		return
+	}
+	tc.bot.logger.Printf("Position %s holding under Fourth Down Option B", shortID(position.ID))
+}
+
+// puntPosition executes Option C: the current legs are bought back and a new strangle is
+// sold in the later expiration as a single four-leg order for a net credit.
+func (tc *TradingCycle) puntPosition(position *models.Position, punt *strategy.PuntOrder) {
+	if !position.CanRoll() {
+		tc.bot.logger.Printf("Position %s has already been rolled out in time, not punting", shortID(position.ID))
+		return
+	}
+	if !tc.claimAdjustment(position) {
+		return
+	}
+	if !tc.releaseLinkedOrders(position, "punting") {
+		tc.releaseAdjustment(position)
+		return
+	}
+
+	tickSize, err := tc.bot.broker.GetTickSize(position.Symbol)
+	if err != nil {
+		tc.bot.logger.Printf("Warning: Failed to get tick size for %s, using default 0.01: %v", position.Symbol, err)
+		tickSize = 0.01
+	}
+	netPrice := math.Max(util.FloorToTick(punt.NetCredit(), tickSize), tickSize)
+	oldExpiration := position.Expiration.Format("2006-01-02")
+	legs := []broker.OrderLeg{
+		{OptionSymbol: broker.BuildOptionSymbol(position.Symbol, position.Expiration, broker.OptionTypePut, position.PutStrike),
+			Side: broker.SideBuyToClose, Quantity: position.Quantity},
+		{OptionSymbol: broker.BuildOptionSymbol(position.Symbol, position.Expiration, broker.OptionTypeCall, position.CallStrike),
+			Side: broker.SideBuyToClose, Quantity: position.Quantity},
+		{OptionSymbol: broker.BuildOptionSymbol(position.Symbol, punt.ExpirationDate, broker.OptionTypePut, punt.PutStrike),
+			Side: broker.SideSellToOpen, Quantity: position.Quantity},
+		{OptionSymbol: broker.BuildOptionSymbol(position.Symbol, punt.ExpirationDate, broker.OptionTypeCall, punt.CallStrike),
+			Side: broker.SideSellToOpen, Quantity: position.Quantity},
+	}
+	tag := fmt.Sprintf("punt-%s-%d", shortID(position.ID), time.Now().Unix())
+
+	tc.bot.logger.Printf("%s (Option C): punting position %s from %s %.2f/%.2f to %s %.2f/%.2f for $%.2f net credit",
+		fourthDownLabel, shortID(position.ID), oldExpiration, position.PutStrike, position.CallStrike,
+		punt.Expiration, punt.PutStrike, punt.CallStrike, netPrice)
+
+	ctx, cancel := context.WithTimeout(tc.bot.ctx, 15*time.Second)
+	resp, err := tc.bot.broker.PlaceMultiLegOrderCtx(ctx, legs, broker.NetCredit, netPrice,
+		string(broker.DurationDay), tag)
+	cancel()
+	if err != nil || resp == nil {
+		tc.bot.logger.Printf("Failed to place punt for position %s: %v", shortID(position.ID), err)
+		tc.releaseAdjustment(position)
+		return
+	}
+	tc.bot.journalOrderPlaced(position, resp.Order.ID, netPrice, fourthDownLabel+" punt")
+	tc.recordAdjustmentOrder(position, resp.Order.ID)
+	tc.watchAdjustment(position.ID, resp.Order.ID, netPrice, "punt", func(position *models.Position, filled int, credit float64) {
+		tc.applyPunt(position, punt, len(legs), filled, credit)
+	})
+}
+
+// applyPunt records a filled punt of filled contracts on position and saves it
+func (tc *TradingCycle) applyPunt(position *models.Position, punt *strategy.PuntOrder, legCount, filled int, credit float64) {
+	oldExpiration := position.Expiration.Format("2006-01-02")
+	if err := position.TransitionState(models.StateRolling, models.ConditionRollAsPunt); err != nil {
+		tc.bot.logger.Printf("Failed to mark position %s as rolling: %v", shortID(position.ID), err)
+	}
+	position.Adjustments = append(position.Adjustments, models.Adjustment{
+		Date: time.Now().UTC(),
+		Type: models.AdjustmentRoll,
+		Description: fmt.Sprintf("%s (Option C): punted %s %.2f/%.2f -> %s %.2f/%.2f", fourthDownLabel,
+			oldExpiration, position.PutStrike, position.CallStrike, punt.Expiration, punt.PutStrike, punt.CallStrike),
+		OldStrike: position.PutStrike,
+		NewStrike: punt.PutStrike,
+		Credit:    credit,
+	})
+	position.Fees += tc.bot.feeSchedule().OrderFees(legCount, filled)
+	position.PutStrike = punt.PutStrike
+	position.CallStrike = punt.CallStrike
+	position.Expiration = punt.ExpirationDate
+	if err := position.TransitionState(models.StateFirstDown, models.ConditionRollComplete); err != nil {
+		tc.bot.logger.Printf("Failed to complete punt for position %s: %v", shortID(position.ID), err)
+	}
+	// The punted strangle starts over; a later Fourth Down runs the decision engine again
+	position.SetFourthDownOption(models.OptionNone)
+	position.AdjustmentOrderID = ""
+	if err := tc.bot.storage.UpdatePosition(position); err != nil {
+		tc.bot.logger.Printf("Failed to persist punt for position %s: %v", shortID(position.ID), err)
+		return
+	}
+
+	tc.bot.logger.Printf("%s (Option C): position %s punted to %s for $%.2f net credit (net credit now $%.2f)",
+		fourthDownLabel, shortID(position.ID), punt.Expiration, credit, position.GetNetCredit())
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newThirdDownStraddle stores a 400 straddle in Third Down with $12.55 net credit (put breakeven 387.45)
+func newThirdDownStraddle(t *testing.T, tb *TestBot, dte int) *models.Position {
+	t.Helper()
+	expiration := time.Now().UTC().AddDate(0, 0, dte).Truncate(24 * time.Hour)
+	position := newAdjustmentTestPosition(t, tb, expiration)
+	require.NoError(t, position.TransitionState(models.StateFirstDown, models.ConditionStartManagement))
+	require.NoError(t, position.TransitionState(models.StateSecondDown, models.ConditionStrikeChallenged))
+	require.NoError(t, position.TransitionState(models.StateThirdDown, models.ConditionStrikeBreached))
+	position.CallStrike = 400
+	position.Adjustments = []models.Adjustment{
+		{Type: models.AdjustmentRoll, Description: "Second Down: rolled untested call 460.00 -> 435.00", Credit: 1.30},
+		{Type: models.AdjustmentRoll, Description: "Third Down: rolled untested call 435.00 -> 400.00", Credit: 8.75},
+	}
+	require.NoError(t, tb.mockStorage.UpdatePosition(position))
+	return position
+}
+
+func newFourthDownTestBot(t *testing.T) *TestBot {
+	tb := createTestBot(t)
+	tb.config.Strategy.Adjustments.Enabled = true
+	tb.config.Strategy.Adjustments.SecondDownThreshold = 10
+	return tb
+}
+
+func TestFourthDown_InsideBreakevenHoldsStraddle(t *testing.T) {
+	tb := newFourthDownTestBot(t)
+	defer tb.cancel()
+	position := newThirdDownStraddle(t, tb, 40)
+
+	tb.mockBroker.On("GetQuote", "SPY").Return(&broker.QuoteItem{Last: 395}, nil)
+
+	NewTradingCycle(tb.Bot).checkAdjustmentsForPosition(position)
+
+	stored, found := tb.mockStorage.GetPositionByID(position.ID)
+	require.True(t, found)
+	assert.Equal(t, models.StateFourthDown, stored.GetCurrentState())
+	assert.Equal(t, models.OptionB, stored.GetFourthDownOption())
+	require.Len(t, stored.Adjustments, 3)
+	assert.Equal(t, models.AdjustmentHold, stored.Adjustments[2].Type)
+	assert.Contains(t, stored.Adjustments[2].Description, "Option B")
+	assert.InDelta(t, 12.55, stored.GetNetCredit(), 1e-9)
+
+	// A second cycle must not record another decision
+	NewTradingCycle(tb.Bot).checkAdjustmentsForPosition(&stored)
+	stored, _ = tb.mockStorage.GetPositionByID(position.ID)
+	assert.Len(t, stored.Adjustments, 3)
+	tb.mockBroker.AssertNotCalled(t, "PlaceMultiLegOrderCtx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFourthDown_PastBreakevenInvertsStrangle(t *testing.T) {
+	tb := newFourthDownTestBot(t)
+	defer tb.cancel()
+	position := newThirdDownStraddle(t, tb, 40)
+	expiration := position.Expiration
+
+	tb.mockBroker.On("GetQuote", "SPY").Return(&broker.QuoteItem{Last: 385}, nil)
+	tb.mockBroker.On("GetOptionChainCtx", mock.Anything, "SPY", expiration.Format("2006-01-02"), false).Return([]broker.Option{
+		{Strike: 385, OptionType: "call", Bid: 15.90, Ask: 16.10},
+		{Strike: 390, OptionType: "call", Bid: 11.90, Ask: 12.10},
+		{Strike: 400, OptionType: "call", Bid: 4.90, Ask: 5.10},
+	}, nil)
+	tb.mockBroker.On("GetTickSize", "SPY").Return(0.01, nil)
+
+	oldCall := broker.BuildOptionSymbol("SPY", expiration, broker.OptionTypeCall, 400)
+	newCall := broker.BuildOptionSymbol("SPY", expiration, broker.OptionTypeCall, 385)
+	tb.mockBroker.On("PlaceMultiLegOrderCtx", mock.Anything, rollLegs(oldCall, newCall), broker.NetCredit, 11.00, "day", mock.Anything).
+		Return(orderStatus(401, "open", 0), nil)
+	tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 401).Return(orderStatus(401, "filled", -11.00), nil)
+
+	NewTradingCycle(tb.Bot).checkAdjustmentsForPosition(position)
+	tb.orderManager.WaitForWatches()
+
+	stored, found := tb.mockStorage.GetPositionByID(position.ID)
+	require.True(t, found)
+	assert.Equal(t, models.StateFourthDown, stored.GetCurrentState())
+	assert.Equal(t, models.OptionA, stored.GetFourthDownOption())
+	assert.Equal(t, 400.0, stored.PutStrike)
+	assert.Equal(t, 385.0, stored.CallStrike)
+	require.Len(t, stored.Adjustments, 3)
+	assert.Contains(t, stored.Adjustments[2].Description, "Option A")
+	assert.InDelta(t, 11.00, stored.Adjustments[2].Credit, 1e-9)
+	assert.Equal(t, 0.0, tb.strategy.ProfitTargetFor(&stored))
+	tb.mockBroker.AssertExpectations(t)
+}
+
+func TestFourthDown_NearTimeExitPuntsToNextMonthly(t *testing.T) {
+	tb := newFourthDownTestBot(t)
+	defer tb.cancel()
+	position := newThirdDownStraddle(t, tb, 25)
+	expiration := position.Expiration
+
+	nextMonthly := expiration.AddDate(0, 1, 0)
+	for nextMonthly.Weekday() != time.Friday || nextMonthly.Day() < 15 || nextMonthly.Day() > 21 {
+		nextMonthly = nextMonthly.AddDate(0, 0, 1)
+	}
+	nextExp := nextMonthly.Format("2006-01-02")
+
+	tb.mockBroker.On("GetQuote", "SPY").Return(&broker.QuoteItem{Last: 395}, nil)
+	tb.mockBroker.On("GetExpirationsCtx", mock.Anything, "SPY").
+		Return([]string{expiration.Format("2006-01-02"), nextExp}, nil)
+	tb.mockBroker.On("GetOptionChainCtx", mock.Anything, "SPY", expiration.Format("2006-01-02"), false).Return([]broker.Option{
+		{Strike: 400, OptionType: "put", Bid: 8.90, Ask: 9.10},
+		{Strike: 400, OptionType: "call", Bid: 3.90, Ask: 4.10},
+	}, nil)
+	tb.mockBroker.On("GetOptionChainCtx", mock.Anything, "SPY", nextExp, true).Return([]broker.Option{
+		{Strike: 375, OptionType: "put", Bid: 6.90, Ask: 7.10, Greeks: &broker.Greeks{Delta: -0.16}},
+		{Strike: 385, OptionType: "put", Bid: 9.90, Ask: 10.10, Greeks: &broker.Greeks{Delta: -0.28}},
+		{Strike: 415, OptionType: "call", Bid: 6.90, Ask: 7.10, Greeks: &broker.Greeks{Delta: 0.16}},
+	}, nil)
+	tb.mockBroker.On("GetTickSize", "SPY").Return(0.01, nil)
+	legs := []broker.OrderLeg{
+		{OptionSymbol: broker.BuildOptionSymbol("SPY", expiration, broker.OptionTypePut, 400), Side: broker.SideBuyToClose, Quantity: 1},
+		{OptionSymbol: broker.BuildOptionSymbol("SPY", expiration, broker.OptionTypeCall, 400), Side: broker.SideBuyToClose, Quantity: 1},
+		{OptionSymbol: broker.BuildOptionSymbol("SPY", nextMonthly, broker.OptionTypePut, 375), Side: broker.SideSellToOpen, Quantity: 1},
+		{OptionSymbol: broker.BuildOptionSymbol("SPY", nextMonthly, broker.OptionTypeCall, 415), Side: broker.SideSellToOpen, Quantity: 1},
+	}
+	tb.mockBroker.On("PlaceMultiLegOrderCtx", mock.Anything, legs, broker.NetCredit, 1.00, "day", mock.Anything).
+		Return(orderStatus(501, "open", 0), nil)
+	tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 501).Return(orderStatus(501, "filled", -1.00), nil)
+
+	NewTradingCycle(tb.Bot).checkAdjustmentsForPosition(position)
+	tb.orderManager.WaitForWatches()
+
+	stored, found := tb.mockStorage.GetPositionByID(position.ID)
+	require.True(t, found)
+	assert.Equal(t, models.StateFirstDown, stored.GetCurrentState())
+	assert.Equal(t, 375.0, stored.PutStrike)
+	assert.Equal(t, 415.0, stored.CallStrike)
+	assert.Equal(t, nextExp, stored.Expiration.Format("2006-01-02"))
+	assert.False(t, stored.CanRoll(), "only one punt is allowed per position")
+	require.Len(t, stored.Adjustments, 3)
+	assert.Contains(t, stored.Adjustments[2].Description, "Option C")
+	assert.InDelta(t, 1.00, stored.Adjustments[2].Credit, 1e-9)
+	assert.InDelta(t, 13.55, stored.GetNetCredit(), 1e-9)
+	assert.Equal(t, models.OptionNone, stored.GetFourthDownOption(), "the punted strangle decides Fourth Down afresh")
+	tb.mockBroker.AssertExpectations(t)
+}
+
+func TestFourthDown_ReenteredAfterPuntRunsEngineAgain(t *testing.T) {
+	tb := newFourthDownTestBot(t)
+	defer tb.cancel()
+	position := newThirdDownStraddle(t, tb, 40)
+	position.Adjustments = append(position.Adjustments, models.Adjustment{
+		Type: models.AdjustmentRoll, Description: "Fourth Down (Option C): punted 400.00/400.00 to the next monthly",
+	})
+	require.NoError(t, tb.mockStorage.UpdatePosition(position))
+
+	tb.mockBroker.On("GetQuote", "SPY").Return(&broker.QuoteItem{Last: 395}, nil)
+
+	NewTradingCycle(tb.Bot).checkAdjustmentsForPosition(position)
+
+	stored, found := tb.mockStorage.GetPositionByID(position.ID)
+	require.True(t, found)
+	assert.Equal(t, models.StateFourthDown, stored.GetCurrentState())
+	assert.Equal(t, models.OptionB, stored.GetFourthDownOption())
+	require.Len(t, stored.Adjustments, 4)
+	assert.Contains(t, stored.Adjustments[3].Description, "Option B")
+}
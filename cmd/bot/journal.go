@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/journal"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+)
+
+// journalOrderPlaced records an order the bot sent for position
+func (b *Bot) journalOrderPlaced(position *models.Position, orderID int, price float64, detail string) {
+	b.journal.Record(journal.Entry{
+		Kind:       journal.KindOrderPlaced,
+		PositionID: position.ID,
+		Symbol:     position.Symbol,
+		OrderID:    fmt.Sprintf("%d", orderID),
+		Quantity:   position.Quantity,
+		Price:      price,
+		Detail:     detail,
+	})
+}
+
+// journalReconcile records a reconciliation decision about position and counts it under
+// outcome, a short label such as "phantom_removed"
+func (r *Reconciler) journalReconcile(position *models.Position, outcome, decision string) {
+	r.metrics.ReconcileDecision(outcome)
+	r.journal.Record(journal.Entry{
+		Kind:       journal.KindReconcile,
+		PositionID: position.ID,
+		Symbol:     position.Symbol,
+		Quantity:   position.Quantity,
+		Detail:     decision,
+	})
+}
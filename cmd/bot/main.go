@@ -8,6 +8,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"math"
 	"net/http"
@@ -24,6 +25,9 @@ import (
 	"github.com/eddiefleurent/scranton_strangler/internal/broker"
 	"github.com/eddiefleurent/scranton_strangler/internal/config"
 	"github.com/eddiefleurent/scranton_strangler/internal/dashboard"
+	"github.com/eddiefleurent/scranton_strangler/internal/events"
+	"github.com/eddiefleurent/scranton_strangler/internal/journal"
+	"github.com/eddiefleurent/scranton_strangler/internal/metrics"
 	"github.com/eddiefleurent/scranton_strangler/internal/models"
 	"github.com/eddiefleurent/scranton_strangler/internal/orders"
 	"github.com/eddiefleurent/scranton_strangler/internal/retry"
@@ -70,6 +74,26 @@ func generateCorrelationID(logger *log.Logger) string {
 	return hex.EncodeToString(bytes)
 }
 
+// entryRules converts configured IV Rank rules to strategy rules, converting delta from percentage
+func entryRules(rules []config.IVRRule) []strategy.EntryRule {
+	out := make([]strategy.EntryRule, 0, len(rules))
+	for i, r := range rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("ivr_rule_%d", i+1)
+		}
+		out = append(out, strategy.EntryRule{
+			Name:        name,
+			MinIVR:      r.MinIVR,
+			MaxIVR:      r.MaxIVR,
+			DTETarget:   r.TargetDTE,
+			DTERange:    r.DTERange,
+			DeltaTarget: r.Delta / 100,
+		})
+	}
+	return out
+}
+
 // Bot represents the main trading bot instance.
 type Bot struct {
 	config        *config.Config
@@ -82,11 +106,17 @@ type Bot struct {
 	stop          chan struct{}
 	ctx           context.Context // Main bot context for operations
 	orderManager  *orders.Manager
+	commands      chan dashboard.Command // Operator commands from the dashboard; nil when it is disabled
+	journal       *journal.Journal // Transition and order event journal; nil when disabled
+	metrics       *metrics.Recorder // Served at the dashboard's /metrics; nil when it is disabled
 	retryClient   *retry.Client
 	nyLocation    *time.Location // Cached NY timezone location
 	lastPnLUpdate time.Time      // Last time P&L was persisted to reduce write amplification
 	pnlThrottle   time.Duration  // Minimum interval between P&L updates
 	calendarMu    sync.RWMutex   // protects market calendar cache
+	exitMu        sync.Mutex     // serializes close orders from the trading cycle and position monitor
+	lastCycle     time.Time      // When the last trading cycle ran, for the dashboard heartbeat
+	marketOpen    bool           // Market status seen by the last trading cycle
 
 	// Market calendar caching
 	marketCalendar     *broker.MarketCalendarResponse
@@ -141,6 +171,11 @@ func run() int {
 		bot.nyLocation = loc
 	}
 
+	// Metrics are served by the dashboard, so they are only collected when it runs
+	if cfg.Dashboard.Enabled {
+		bot.metrics = metrics.NewRecorder()
+	}
+
 	// Initialize broker client
 	tradierClient, err := broker.NewTradierClient(
 		cfg.Broker.APIKey,
@@ -148,6 +183,7 @@ func run() int {
 		cfg.IsPaperTrading(),
 		cfg.Broker.UseOTOCO,
 		cfg.Strategy.Exit.ProfitTarget,
+		broker.WithRequestObserver(bot.metrics.ObserveBrokerRequest),
 	)
 	if err != nil {
 		log.Printf("Failed to create Tradier client: %v", err)
@@ -155,16 +191,47 @@ func run() int {
 	}
 
 	// Wrap with circuit breaker for resilience
-	bot.broker = broker.NewCircuitBreakerBroker(tradierClient)
+	breaker := broker.NewCircuitBreakerBroker(tradierClient)
+	bot.broker = breaker
 
 	// Initialize storage
-	storagePath := cfg.Storage.Path
-	store, err := storage.NewStorage(storagePath)
+	store, err := storage.NewStorage(cfg.Storage.Backend, cfg.Storage.Path)
 	if err != nil {
 		log.Printf("Failed to initialize storage: %v", err)
 		return 1
 	}
 	bot.storage = store
+	if closer, ok := store.(io.Closer); ok {
+		defer func() { _ = closer.Close() }()
+	}
+	bot.metrics.OnScrape(func() {
+		bot.metrics.SetCircuitBreakerState(breaker.State())
+		open := 0
+		for _, position := range bot.storage.GetCurrentPositions() {
+			if position.GetCurrentState() != models.StateClosed {
+				open++
+			}
+		}
+		bot.metrics.SetOpenPositions(open)
+	})
+
+	// Journal every position state transition and order event
+	if path := strings.TrimSpace(cfg.Storage.JournalPath); path != "" {
+		bot.journal, err = journal.Open(path, logger)
+		if err != nil {
+			log.Printf("Failed to open journal: %v", err)
+			return 1
+		}
+		defer func() { _ = bot.journal.Close() }()
+		models.SetTransitionObserver(bot.journal.ObserveTransition)
+		defer models.SetTransitionObserver(nil)
+		logger.Printf("Journaling position events to %s", path)
+	} else if cfg.Dashboard.Enabled {
+		// The dashboard streams journal events even when none are written to disk
+		bot.journal = journal.New(logger)
+		models.SetTransitionObserver(bot.journal.ObserveTransition)
+		defer models.SetTransitionObserver(nil)
+	}
 
 	// Initialize strategy
 	strategyConfig := &strategy.Config{
@@ -173,9 +240,18 @@ func run() int {
 		DTERange:            cfg.Strategy.Entry.DTERange,
 		DeltaTarget:         cfg.Strategy.Entry.Delta / 100, // Convert from percentage
 		ProfitTarget:        cfg.Strategy.Exit.ProfitTarget,
+		ThirdDownProfitTarget: cfg.Strategy.Exit.ThirdDownProfitTarget,
 		MaxDTE:              cfg.Strategy.Exit.MaxDTE,
 		AllocationPct:       cfg.Strategy.AllocationPct,
 		MinIVPct:            cfg.Strategy.Entry.MinIVPct,
+		MinIVR:              cfg.Strategy.Entry.MinIVR,
+		IVLookbackDays:      cfg.Strategy.Entry.IVLookbackDays,
+		MinIVRReadings:      cfg.Strategy.Entry.MinIVRReadings,
+		RiskFreeRate:        cfg.Strategy.Pricing.RiskFreeRate,
+		DividendYield:       cfg.Strategy.Pricing.DividendYield,
+		IVRRules:            entryRules(cfg.Strategy.Entry.IVRRules),
+		EventWindow:         time.Duration(cfg.Strategy.Events.WindowHours * float64(time.Hour)),
+		EventMinImpact:      events.Impact(cfg.Strategy.Events.MinImpact),
 		MinCredit:           cfg.Strategy.Entry.MinCredit,
 		EscalateLossPct:     cfg.Strategy.EscalateLossPct,
 		StopLossPct:         cfg.Strategy.Exit.StopLossPct,
@@ -186,8 +262,27 @@ func run() int {
 	}
 	bot.strategy = strategy.NewStrangleStrategy(bot.broker, strategyConfig, logger, bot.storage)
 
+	// Load the economic event calendar that blocks entries around FOMC, CPI, etc.
+	if path := strings.TrimSpace(cfg.Strategy.Events.CalendarFile); path != "" {
+		calendar, err := events.LoadFile(path)
+		if err != nil {
+			log.Printf("Failed to load event calendar: %v", err)
+			return 1
+		}
+		bot.strategy.SetEventCalendar(calendar)
+		logger.Printf("Loaded %d economic events from %s (entries blocked %.0fh around %s impact events)",
+			calendar.Len(), path, cfg.Strategy.Events.WindowHours, cfg.Strategy.Events.MinImpact)
+	}
+
 	// Initialize order manager
-	bot.orderManager = orders.NewManager(bot.broker, bot.storage, logger, bot.stop)
+	orderConfig := orders.DefaultConfig
+	orderConfig.Fees = bot.feeSchedule()
+	bot.orderManager = orders.NewManager(bot.broker, bot.storage, logger, bot.stop, orderConfig)
+	bot.orderManager.SetJournal(bot.journal)
+	bot.orderManager.SetOnUnevenFill(func(err error) { bot.metrics.ReconcileRun(err) })
+	if cfg.Strategy.Exit.ProfitTargetOrder {
+		bot.orderManager.SetOnEntryFilled(bot.placeProfitTarget)
+	}
 
 	// Initialize retry client
 	bot.retryClient = retry.NewClient(bot.broker, logger)
@@ -210,14 +305,31 @@ func run() int {
 		}
 		bot.dashLogger = dashLogger
 
+		// Control actions are written to the audit log before the bot applies them
+		var auditLog *dashboard.AuditLog
+		if path := strings.TrimSpace(cfg.Dashboard.AuditLogPath); path != "" {
+			auditLog, err = dashboard.OpenAuditLog(path)
+			if err != nil {
+				log.Printf("Failed to open dashboard audit log: %v", err)
+				return 1
+			}
+			defer func() { _ = auditLog.Close() }()
+		}
+		bot.commands = make(chan dashboard.Command, commandQueueSize)
+
 		dashConfig := dashboard.Config{
 			Port:                cfg.Dashboard.Port,
 			AuthToken:           cfg.Dashboard.AuthToken,
 			AllocationThreshold: cfg.Strategy.AllocationPct * 100, // Convert to percentage
 			ProfitTarget:        cfg.Strategy.Exit.ProfitTarget,
 			StopLossPct:         cfg.Strategy.Exit.StopLossPct,
+			Commands:            bot.commands,
+			AuditLog:            auditLog,
+			Metrics:             bot.metrics.Handler(),
 		}
 		bot.dashServer = dashboard.NewServer(dashConfig, bot.storage, bot.broker, bot.dashLogger)
+		bot.journal.AddListener(bot.dashServer.ObserveJournal)
+		bot.journal.AddListener(bot.metrics.ObserveJournal)
 		logger.Printf("Dashboard enabled at http://0.0.0.0:%d (accessible via localhost:%d)", cfg.Dashboard.Port, cfg.Dashboard.Port)
 	}
 
@@ -247,8 +359,7 @@ func run() int {
 		go func() {
 			if err := bot.dashServer.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 				logger.Printf("Dashboard server error: %v", err)
-				// Emit metric for monitoring dashboard failures
-				logger.Printf("METRIC: dashboard_server_failures=1")
+				bot.metrics.DashboardServerFailed()
 			}
 		}()
 
@@ -297,16 +408,21 @@ func (b *Bot) Run(ctx context.Context) error {
 	}
 	b.logger.Printf("Connected to broker. Account balance: $%.2f", bal)
 
+	// Orders working at shutdown lost their pollers; settle or resume them before comparing with the broker
+	b.resumeInFlightOrders(ctx)
+
 	// Broker-first initialization: sync local storage with broker reality
-	if err := b.performStartupReconciliation(ctx); err != nil {
+	err = b.performStartupReconciliation(ctx)
+	b.metrics.ReconcileRun(err)
+	if err != nil {
 		correlationID := generateCorrelationID(b.logger)
 		b.logger.Printf("Warning: Startup reconciliation failed: %v (correlation_id=%s)", err, correlationID)
 		b.logger.Printf("Continuing with existing local data...")
-
-		// Emit structured metric for monitoring
-		b.logger.Printf("METRIC: reconciliation_failures=1 correlation_id=%s", correlationID)
 	}
 
+	// Stop-loss checks run on their own, shorter interval
+	go NewPositionMonitor(b).Run(ctx)
+
 	// Main trading loop
 	interval := b.config.GetCheckInterval()
 	if interval <= 0 {
@@ -316,6 +432,10 @@ func (b *Bot) Run(ctx context.Context) error {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	// Dashboard clients mark the bot stale when heartbeats stop
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
 	// Run immediately on start
 	b.runTradingCycle()
 
@@ -327,14 +447,44 @@ func (b *Bot) Run(ctx context.Context) error {
 			return nil
 		case <-ticker.C:
 			b.runTradingCycle()
+		case cmd := <-b.commands:
+			b.applyCommand(cmd)
+		case <-heartbeat.C:
+			b.publishHeartbeat()
 		}
 	}
 }
 
+// feeSchedule returns the configured commissions and fees charged on every fill
+func (b *Bot) feeSchedule() models.FeeSchedule {
+	return models.FeeSchedule{
+		PerContract: b.config.Fees.PerContract,
+		PerLeg:      b.config.Fees.PerLeg,
+		MinPerOrder: b.config.Fees.MinPerOrder,
+	}
+}
+
 func (b *Bot) runTradingCycle() {
+	start := time.Now()
 	// Use the new TradingCycle handler
 	tradingCycle := NewTradingCycle(b)
 	tradingCycle.Run()
+	b.lastCycle = time.Now().UTC()
+	b.metrics.ObserveCycle(b.lastCycle, time.Since(start))
+	b.marketOpen = tradingCycle.marketOpen
+	b.publishHeartbeat()
+}
+
+// publishHeartbeat reports to dashboard clients that the main loop is alive
+func (b *Bot) publishHeartbeat() {
+	if b.dashServer == nil {
+		return
+	}
+	b.dashServer.PublishHeartbeat(dashboard.Heartbeat{
+		LastCycle:     b.lastCycle,
+		MarketOpen:    b.marketOpen,
+		EntriesPaused: b.storage.GetEntryPause() != nil,
+	})
 }
 
 
@@ -537,7 +687,8 @@ func (b *Bot) analyzePositionDifferences(brokerPositions []broker.PositionItem,
 		// Log any leftover deficits for visibility
 		for sym, v := range phantomOutstanding {
 			if v > 0 {
-				b.logger.Printf("METRIC: reconciliation_phantom_leftover=1 symbol=%s deficit=%d", sym, v)
+				b.logger.Printf("Reconciliation left %d phantom contract(s) unmatched for %s", v, sym)
+				b.metrics.ReconcileDecision("phantom_leftover")
 			}
 		}
 	}
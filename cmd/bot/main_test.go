@@ -9,6 +9,7 @@ import (
 
 	"github.com/eddiefleurent/scranton_strangler/internal/broker"
 	"github.com/eddiefleurent/scranton_strangler/internal/config"
+	"github.com/eddiefleurent/scranton_strangler/internal/events"
 	"github.com/eddiefleurent/scranton_strangler/internal/models"
 	"github.com/eddiefleurent/scranton_strangler/internal/orders"
 	"github.com/eddiefleurent/scranton_strangler/internal/retry"
@@ -69,6 +70,18 @@ func (m *MockBroker) GetQuote(symbol string) (*broker.QuoteItem, error) {
 	return args.Get(0).(*broker.QuoteItem), args.Error(1)
 }
 
+func (m *MockBroker) GetQuotes(symbols []string) ([]broker.QuoteItem, error) {
+	return m.GetQuotesCtx(context.Background(), symbols)
+}
+
+func (m *MockBroker) GetQuotesCtx(ctx context.Context, symbols []string) ([]broker.QuoteItem, error) {
+	args := m.Called(ctx, symbols)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]broker.QuoteItem), args.Error(1)
+}
+
 func (m *MockBroker) GetExpirations(symbol string) ([]string, error) {
 	args := m.Called(symbol)
 	if args.Get(0) == nil {
@@ -145,6 +158,22 @@ func (m *MockBroker) CancelOrderCtx(ctx context.Context, orderID int) (*broker.O
 	return args.Get(0).(*broker.OrderResponse), args.Error(1)
 }
 
+func (m *MockBroker) ModifyOrder(orderID int, price, tickSize float64, duration string) (*broker.OrderResponse, error) {
+	args := m.Called(orderID, price, tickSize, duration)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*broker.OrderResponse), args.Error(1)
+}
+
+func (m *MockBroker) ModifyOrderCtx(ctx context.Context, orderID int, price, tickSize float64, duration string) (*broker.OrderResponse, error) {
+	args := m.Called(ctx, orderID, price, tickSize, duration)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*broker.OrderResponse), args.Error(1)
+}
+
 func (m *MockBroker) GetOrders() (*broker.OrdersResponse, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
@@ -193,6 +222,22 @@ func (m *MockBroker) PlaceSellToCloseOrder(optionSymbol string, quantity int, ma
 	return args.Get(0).(*broker.OrderResponse), args.Error(1)
 }
 
+func (m *MockBroker) PlaceMultiLegOrder(legs []broker.OrderLeg, priceType broker.NetPriceType, price float64, duration string, tag string) (*broker.OrderResponse, error) {
+	args := m.Called(legs, priceType, price, duration, tag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*broker.OrderResponse), args.Error(1)
+}
+
+func (m *MockBroker) PlaceMultiLegOrderCtx(ctx context.Context, legs []broker.OrderLeg, priceType broker.NetPriceType, price float64, duration string, tag string) (*broker.OrderResponse, error) {
+	args := m.Called(ctx, legs, priceType, price, duration, tag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*broker.OrderResponse), args.Error(1)
+}
+
 func (m *MockBroker) PlaceBuyToCloseMarketOrder(optionSymbol string, quantity int, duration string, tag string) (*broker.OrderResponse, error) {
 	args := m.Called(optionSymbol, quantity, duration, tag)
 	if args.Get(0) == nil {
@@ -306,9 +351,10 @@ func createTestBot(t *testing.T) *TestBot {
 				MinOpenInterest: 10,
 			},
 			Exit: config.ExitConfig{
-				ProfitTarget: 0.5,
-				MaxDTE:       21,
-				StopLossPct:  2.5,
+				ProfitTarget:          0.5,
+				ThirdDownProfitTarget: 0.25,
+				MaxDTE:                21,
+				StopLossPct:           2.5,
 			},
 			EscalateLossPct: 2.0, // 200% loss ratio
 			MaxNewPositionsPerCycle: 1,
@@ -359,9 +405,18 @@ func createTestBot(t *testing.T) *TestBot {
 		DTERange:            []int{cfg.Strategy.Entry.DTERange[0], cfg.Strategy.Entry.DTERange[1]},
 		DeltaTarget:         float64(cfg.Strategy.Entry.Delta) / 100,
 		ProfitTarget:        cfg.Strategy.Exit.ProfitTarget,
+		ThirdDownProfitTarget: cfg.Strategy.Exit.ThirdDownProfitTarget,
 		MaxDTE:              cfg.Strategy.Exit.MaxDTE,
 		AllocationPct:       cfg.Strategy.AllocationPct,
 		MinIVPct:            cfg.Strategy.Entry.MinIVPct,
+		MinIVR:              cfg.Strategy.Entry.MinIVR,
+		IVLookbackDays:      cfg.Strategy.Entry.IVLookbackDays,
+		MinIVRReadings:      cfg.Strategy.Entry.MinIVRReadings,
+		RiskFreeRate:        cfg.Strategy.Pricing.RiskFreeRate,
+		DividendYield:       cfg.Strategy.Pricing.DividendYield,
+		IVRRules:            entryRules(cfg.Strategy.Entry.IVRRules),
+		EventWindow:         time.Duration(cfg.Strategy.Events.WindowHours * float64(time.Hour)),
+		EventMinImpact:      events.Impact(cfg.Strategy.Events.MinImpact),
 		MinCredit:           cfg.Strategy.Entry.MinCredit,
 		EscalateLossPct:     cfg.Strategy.EscalateLossPct,
 		StopLossPct:         cfg.Strategy.Exit.StopLossPct,
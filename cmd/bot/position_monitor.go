@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/strategy"
+)
+
+// extendedHoursWindow is how long SPY options keep trading after the equity close
+const extendedHoursWindow = 15 * time.Minute
+
+// PositionMonitor re-prices open positions on a short interval and closes any that
+// breach the stop-loss threshold. It runs separately from the trading cycle so stop
+// protection does not wait on entry, adjustment or reconciliation work.
+type PositionMonitor struct {
+	bot      *Bot
+	interval time.Duration
+}
+
+// NewPositionMonitor creates a monitor using schedule.stop_loss_check_interval
+func NewPositionMonitor(bot *Bot) *PositionMonitor {
+	return &PositionMonitor{
+		bot:      bot,
+		interval: bot.config.GetStopLossCheckInterval(),
+	}
+}
+
+// Run checks positions every interval while options are trading, until ctx is done or the bot stops
+func (m *PositionMonitor) Run(ctx context.Context) {
+	m.bot.logger.Printf("Position monitor started: stop-loss checks every %v", m.interval)
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.bot.stop:
+			return
+		case <-ticker.C:
+			if m.isMonitoringWindow(time.Now()) {
+				m.Check(ctx)
+			}
+		}
+	}
+}
+
+// isMonitoringWindow reports whether options are trading now, using today's market calendar
+func (m *PositionMonitor) isMonitoringWindow(now time.Time) bool {
+	if m.bot.nyLocation != nil {
+		now = now.In(m.bot.nyLocation)
+	}
+	day, err := m.bot.getTodaysMarketSchedule()
+	if err != nil {
+		day = nil
+	}
+	return inMonitoringWindow(now, day)
+}
+
+// inMonitoringWindow reports whether now, in exchange time, falls in the day's regular
+// session or the 15 minutes of extended option trading after the close. Early closes
+// come from the market calendar; without one the regular 9:30-16:00 session is assumed.
+func inMonitoringWindow(now time.Time, day *broker.MarketDay) bool {
+	if now.Weekday() == time.Saturday || now.Weekday() == time.Sunday {
+		return false
+	}
+
+	openClock, closeClock := "09:30", "16:00"
+	if day != nil {
+		if day.Status == "closed" {
+			return false
+		}
+		if day.Open != nil {
+			openClock, closeClock = day.Open.Start, day.Open.End
+		}
+	}
+
+	loc := now.Location()
+	open, err1 := time.ParseInLocation("15:04", openClock, loc)
+	end, err2 := time.ParseInLocation("15:04", closeClock, loc)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	start := time.Date(now.Year(), now.Month(), now.Day(), open.Hour(), open.Minute(), 0, 0, loc)
+	stop := time.Date(now.Year(), now.Month(), now.Day(), end.Hour(), end.Minute(), 0, 0, loc).
+		Add(extendedHoursWindow)
+	return !now.Before(start) && now.Before(stop)
+}
+
+// Check prices every monitored position with one batched quote request, publishes the
+// P&L to the dashboard and closes positions at or beyond their stop loss
+func (m *PositionMonitor) Check(ctx context.Context) {
+	// Positions closed by a profit-target fill drop out of monitoring here
+	for _, id := range m.bot.orderManager.SyncLinkedOrders(ctx) {
+		m.bot.logger.Printf("Position %s closed by its profit target order, monitoring stopped", shortID(id))
+	}
+
+	var monitored []models.Position
+	for _, position := range m.bot.storage.GetCurrentPositions() {
+		if isMonitoredPosition(&position) {
+			monitored = append(monitored, position)
+		}
+	}
+	if len(monitored) == 0 {
+		m.bot.metrics.SetUnrealizedPnL(0)
+		return
+	}
+
+	symbols := make([]string, 0, 2*len(monitored))
+	for i := range monitored {
+		put, call := legSymbols(&monitored[i])
+		symbols = append(symbols, put, call)
+	}
+
+	quoteCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	quotes, err := m.bot.broker.GetQuotesCtx(quoteCtx, symbols)
+	if err != nil {
+		m.bot.logger.Printf("Warning: Position monitor could not fetch %d option quotes: %v", len(symbols), err)
+		return
+	}
+	bySymbol := make(map[string]broker.QuoteItem, len(quotes))
+	for _, q := range quotes {
+		bySymbol[q.Symbol] = q
+	}
+
+	equity := m.bot.strategy.StopLossEquity(ctx)
+	live := make(map[string]float64, len(monitored))
+	var breached []int
+	for i := range monitored {
+		if m.breachesStopLoss(&monitored[i], bySymbol, equity, live) {
+			breached = append(breached, i)
+		}
+	}
+	m.bot.dashServer.PublishPnL(live)
+	total := 0.0
+	for _, pnl := range live {
+		total += pnl
+	}
+	m.bot.metrics.SetUnrealizedPnL(total)
+
+	for _, i := range breached {
+		NewTradingCycle(m.bot).executeExit(&monitored[i], strategy.ExitReasonStopLoss)
+	}
+}
+
+// breachesStopLoss evaluates one position against its stop-loss threshold and logs the
+// result against the equity fetched once for the whole check. The unrealized P&L of a
+// priced position is stored in live by position ID.
+func (m *PositionMonitor) breachesStopLoss(position *models.Position, quotes map[string]broker.QuoteItem,
+	equity float64, live map[string]float64) bool {
+	id := shortID(position.ID)
+	putSymbol, callSymbol := legSymbols(position)
+	putMid, putOK := quoteMid(quotes[putSymbol])
+	callMid, callOK := quoteMid(quotes[callSymbol])
+	if !putOK || !callOK {
+		m.bot.logger.Printf("Stop check %s: no usable quote (put %s ok=%t, call %s ok=%t), skipping",
+			id, putSymbol, putOK, callSymbol, callOK)
+		return false
+	}
+
+	contracts := float64(position.Quantity) * 100
+	credit := math.Abs(position.GetNetCredit()) * contracts
+	if credit == 0 {
+		m.bot.logger.Printf("Stop check %s: no credit recorded, skipping", id)
+		return false
+	}
+	pnl := credit - (putMid+callMid)*contracts
+	live[position.ID] = pnl
+	stopPct := m.bot.strategy.StopLossFor(credit, equity)
+	stopPnL := -credit * stopPct
+
+	breached := pnl <= stopPnL
+	m.bot.logger.Printf("Stop check %s: P&L $%.2f (%.0f%% of $%.2f credit), stop $%.2f (-%.0f%%), put %.2f call %.2f, breached=%t",
+		id, pnl, pnl/credit*100, credit, stopPnL, stopPct*100, putMid, callMid, breached)
+	if breached {
+		m.bot.logger.Printf("STOP LOSS: position %s breached its stop, closing now", id)
+	}
+	return breached
+}
+
+// isMonitoredPosition reports whether a position is open with no close, roll or punt order working
+func isMonitoredPosition(position *models.Position) bool {
+	if position.ExitOrderID != "" || position.HasWorkingAdjustment() || position.Quantity <= 0 {
+		return false
+	}
+	switch position.GetCurrentState() {
+	case models.StateOpen, models.StateFirstDown, models.StateSecondDown,
+		models.StateThirdDown, models.StateFourthDown:
+		return true
+	}
+	return false
+}
+
+// legSymbols returns the OSI symbols of the short put and short call
+func legSymbols(position *models.Position) (put, call string) {
+	symbol := strings.ToUpper(position.Symbol)
+	put = broker.BuildOptionSymbol(symbol, position.Expiration, broker.OptionTypePut, position.PutStrike)
+	call = broker.BuildOptionSymbol(symbol, position.Expiration, broker.OptionTypeCall, position.CallStrike)
+	return put, call
+}
+
+// quoteMid returns the bid/ask mid, or the last trade when the market is one-sided
+func quoteMid(q broker.QuoteItem) (float64, bool) {
+	if q.Ask > 0 && q.Bid >= 0 && q.Ask >= q.Bid {
+		return (q.Bid + q.Ask) / 2, true
+	}
+	if q.Last > 0 {
+		return q.Last, true
+	}
+	return 0, false
+}
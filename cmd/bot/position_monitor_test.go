@@ -0,0 +1,179 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/metrics"
+	"github.com/eddiefleurent/scranton_strangler/internal/strategy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func optionQuotes(expiration time.Time, putMid, callMid float64) []broker.QuoteItem {
+	return []broker.QuoteItem{
+		{Symbol: broker.BuildOptionSymbol("SPY", expiration, broker.OptionTypePut, 400), Bid: putMid - 0.05, Ask: putMid + 0.05},
+		{Symbol: broker.BuildOptionSymbol("SPY", expiration, broker.OptionTypeCall, 460), Bid: callMid - 0.05, Ask: callMid + 0.05},
+	}
+}
+
+func TestPositionMonitor_Check(t *testing.T) {
+	t.Run("loss within stop is logged and left open", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+		expiration := time.Now().UTC().AddDate(0, 0, 30).Truncate(24 * time.Hour)
+		position := newAdjustmentTestPosition(t, tb, expiration)
+
+		// $250 credit against $600 to close: -$350, inside the 250% stop of -$625
+		tb.mockBroker.On("GetQuotesCtx", mock.Anything, mock.Anything).Return(optionQuotes(expiration, 5.00, 1.00), nil)
+		tb.mockBroker.On("GetAccountBalanceCtx", mock.Anything).Return(100000.0, nil)
+
+		NewPositionMonitor(tb.Bot).Check(tb.ctx)
+
+		stored, found := tb.mockStorage.GetPositionByID(position.ID)
+		require.True(t, found)
+		assert.Empty(t, stored.ExitOrderID)
+		tb.mockBroker.AssertNotCalled(t, "CloseStranglePositionCtx", mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("equity is fetched once for all positions", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+		expiration := time.Now().UTC().AddDate(0, 0, 30).Truncate(24 * time.Hour)
+		position := newAdjustmentTestPosition(t, tb, expiration)
+		second := position.Clone()
+		second.ID = "adj-test-position-2"
+		require.NoError(t, tb.mockStorage.AddPosition(&second))
+
+		tb.mockBroker.On("GetQuotesCtx", mock.Anything, mock.Anything).Return(optionQuotes(expiration, 5.00, 1.00), nil)
+		tb.mockBroker.On("GetAccountBalanceCtx", mock.Anything).Return(100000.0, nil)
+
+		NewPositionMonitor(tb.Bot).Check(tb.ctx)
+
+		tb.mockBroker.AssertNumberOfCalls(t, "GetAccountBalanceCtx", 1)
+	})
+
+	t.Run("priced P&L is exported as a metric", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+		tb.metrics = metrics.NewRecorder()
+		expiration := time.Now().UTC().AddDate(0, 0, 30).Truncate(24 * time.Hour)
+		newAdjustmentTestPosition(t, tb, expiration)
+
+		// $250 credit against $600 to close
+		tb.mockBroker.On("GetQuotesCtx", mock.Anything, mock.Anything).Return(optionQuotes(expiration, 5.00, 1.00), nil)
+		tb.mockBroker.On("GetAccountBalanceCtx", mock.Anything).Return(100000.0, nil)
+
+		NewPositionMonitor(tb.Bot).Check(tb.ctx)
+
+		rec := httptest.NewRecorder()
+		tb.metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		assert.Contains(t, rec.Body.String(), "strangler_unrealized_pnl_dollars -350\n")
+	})
+
+	t.Run("breached stop closes immediately", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+		defer close(tb.stop) // Ends the close order poller
+		expiration := time.Now().UTC().AddDate(0, 0, 30).Truncate(24 * time.Hour)
+		expStr := expiration.Format("2006-01-02")
+		position := newAdjustmentTestPosition(t, tb, expiration)
+
+		// $250 credit against $880 to close: -$630, past the 250% stop of -$625
+		tb.mockBroker.On("GetQuotesCtx", mock.Anything, []string{
+			broker.BuildOptionSymbol("SPY", expiration, broker.OptionTypePut, 400),
+			broker.BuildOptionSymbol("SPY", expiration, broker.OptionTypeCall, 460),
+		}).Return(optionQuotes(expiration, 7.00, 1.80), nil)
+		tb.mockBroker.On("GetAccountBalanceCtx", mock.Anything).Return(100000.0, nil)
+		tb.mockBroker.On("GetOptionChainCtx", mock.Anything, "SPY", expStr, false).Return([]broker.Option{
+			{Strike: 400, OptionType: "put", Bid: 6.95, Ask: 7.05},
+			{Strike: 460, OptionType: "call", Bid: 1.75, Ask: 1.85},
+		}, nil)
+		tb.mockBroker.On("GetTickSize", "SPY").Return(0.01, nil)
+		tb.mockBroker.On("CloseStranglePositionCtx", mock.Anything, "SPY", 400.0, 460.0, expStr, 1, 8.80, mock.Anything).
+			Return(orderStatus(601, "open", 0), nil)
+		tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 601).Return(orderStatus(601, "open", 0), nil).Maybe()
+
+		NewPositionMonitor(tb.Bot).Check(tb.ctx)
+
+		stored, found := tb.mockStorage.GetPositionByID(position.ID)
+		require.True(t, found)
+		assert.Equal(t, "601", stored.ExitOrderID)
+		tb.mockBroker.AssertNumberOfCalls(t, "CloseStranglePositionCtx", 1)
+
+		// A later pass skips the position while its close is working
+		NewPositionMonitor(tb.Bot).Check(tb.ctx)
+		tb.mockBroker.AssertNumberOfCalls(t, "GetQuotesCtx", 1)
+	})
+
+	t.Run("stale copy from the trading cycle does not close twice", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+		expiration := time.Now().UTC().AddDate(0, 0, 30).Truncate(24 * time.Hour)
+		position := newAdjustmentTestPosition(t, tb, expiration)
+		stale := position.Clone()
+
+		position.ExitOrderID = "601" // Placed by the monitor after the cycle read its positions
+		require.NoError(t, tb.mockStorage.UpdatePosition(position))
+
+		NewTradingCycle(tb.Bot).executeExit(&stale, strategy.ExitReasonStopLoss)
+		tb.mockBroker.AssertNotCalled(t, "CloseStranglePositionCtx", mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("position with a working roll is neither priced nor closed", func(t *testing.T) {
+		tb := createTestBot(t)
+		defer tb.cancel()
+		expiration := time.Now().UTC().AddDate(0, 0, 30).Truncate(24 * time.Hour)
+		position := newAdjustmentTestPosition(t, tb, expiration)
+		stale := position.Clone()
+
+		position.AdjustmentOrderID = "101" // Claimed by a roll after the caller read its positions
+		require.NoError(t, tb.mockStorage.UpdatePosition(position))
+
+		NewPositionMonitor(tb.Bot).Check(tb.ctx)
+		NewTradingCycle(tb.Bot).executeExit(&stale, strategy.ExitReasonStopLoss)
+
+		tb.mockBroker.AssertNotCalled(t, "GetQuotesCtx", mock.Anything, mock.Anything)
+		tb.mockBroker.AssertNotCalled(t, "CloseStranglePositionCtx", mock.Anything, mock.Anything, mock.Anything,
+			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestInMonitoringWindow(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	at := func(hour, minute int) time.Time { return time.Date(2025, 3, 4, hour, minute, 0, 0, ny) } // Tuesday
+
+	earlyClose := &broker.MarketDay{Status: "open"}
+	earlyClose.Open = &struct {
+		Start string `json:"start"`
+		End   string `json:"end"`
+	}{Start: "09:30", End: "13:00"}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		day  *broker.MarketDay
+		want bool
+	}{
+		{"before open", at(9, 29), nil, false},
+		{"regular session", at(11, 0), nil, true},
+		{"extended window", at(16, 10), nil, true},
+		{"after extended window", at(16, 15), nil, false},
+		{"weekend", time.Date(2025, 3, 8, 11, 0, 0, 0, ny), nil, false},
+		{"holiday", at(11, 0), &broker.MarketDay{Status: "closed"}, false},
+		{"early close extended window", at(13, 10), earlyClose, true},
+		{"after early close window", at(13, 20), earlyClose, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, inMonitoringWindow(tt.now, tt.day))
+		})
+	}
+}
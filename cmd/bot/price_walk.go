@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/orders"
+	"github.com/eddiefleurent/scranton_strangler/internal/strategy"
+)
+
+// entryPriceWalk returns the walk for a new strangle's entry order, or nil when walking is
+// disabled or the legs cannot be quoted, in which case the order rests at its first price
+func (tc *TradingCycle) entryPriceWalk(order *strategy.StrangleOrder, expiration time.Time) *orders.PriceWalk {
+	if !tc.bot.config.Execution.PriceWalk {
+		return nil
+	}
+	symbol := strings.ToUpper(order.Symbol)
+	put, call, err := tc.quoteLegs(
+		broker.BuildOptionSymbol(symbol, expiration, broker.OptionTypePut, order.PutStrike),
+		broker.BuildOptionSymbol(symbol, expiration, broker.OptionTypeCall, order.CallStrike))
+	if err != nil {
+		tc.bot.logger.Printf("Warning: Entry will not be price-walked: %v", err)
+		return nil
+	}
+
+	return tc.newPriceWalk(models.ExecutionEntry, order.Symbol, order.Credit, put.Bid+call.Bid)
+}
+
+// exitPriceWalk returns the walk for a close order, or nil when walking is disabled, the exit
+// is an emergency that must not wait, or the legs cannot be quoted. A profit-target exit never
+// walks past maxDebit, so it keeps at least its target profit, and is not walked at all when
+// mid is already above maxDebit.
+func (tc *TradingCycle) exitPriceWalk(position *models.Position, reason strategy.ExitReason, maxDebit float64) *orders.PriceWalk {
+	if !tc.bot.config.Execution.PriceWalk || isEmergencyExit(reason) {
+		return nil
+	}
+	put, call, err := tc.quoteLegs(legSymbols(position))
+	if err != nil {
+		tc.bot.logger.Printf("Warning: Exit for position %s will not be price-walked: %v", shortID(position.ID), err)
+		return nil
+	}
+	putMid, putOK := quoteMid(put)
+	callMid, callOK := quoteMid(call)
+	if !putOK || !callOK || put.Ask <= 0 || call.Ask <= 0 {
+		tc.bot.logger.Printf("Warning: Exit for position %s will not be price-walked: one-sided leg quotes",
+			shortID(position.ID))
+		return nil
+	}
+
+	walk := tc.newPriceWalk(models.ExecutionExit, position.Symbol, putMid+callMid, put.Ask+call.Ask)
+	if walk == nil {
+		return nil
+	}
+	if reason == strategy.ExitReasonProfitTarget {
+		if walk.Start > maxDebit {
+			tc.bot.logger.Printf("Profit-target exit for position %s will not be price-walked: mid $%.2f is above the $%.2f target debit",
+				shortID(position.ID), walk.Start, maxDebit)
+			return nil
+		}
+		walk.Limit = min(walk.Limit, maxDebit)
+	}
+	return walk
+}
+
+// newPriceWalk builds a walk from the configured step and concession, or nil if the prices are unusable
+func (tc *TradingCycle) newPriceWalk(side models.ExecutionSide, symbol string, mid, natural float64) *orders.PriceWalk {
+	tickSize, err := tc.bot.broker.GetTickSize(symbol)
+	if err != nil {
+		tc.bot.logger.Printf("Warning: Failed to get tick size for %s, using default 0.01: %v", symbol, err)
+		tickSize = 0.01
+	}
+	cfg := tc.bot.config.Execution
+	walk, err := orders.NewPriceWalk(side, mid, natural, tickSize, cfg.StepTicks, cfg.MaxConcessionTicks,
+		tc.bot.config.GetPriceWalkStepInterval())
+	if err != nil {
+		tc.bot.logger.Printf("Warning: Cannot price-walk %s %s order: %v", symbol, side, err)
+		return nil
+	}
+	return walk
+}
+
+// quoteLegs fetches the put and call quotes of a strangle in one request
+func (tc *TradingCycle) quoteLegs(putSymbol, callSymbol string) (put, call broker.QuoteItem, err error) {
+	ctx, cancel := context.WithTimeout(tc.bot.ctx, 10*time.Second)
+	defer cancel()
+	quotes, err := tc.bot.broker.GetQuotesCtx(ctx, []string{putSymbol, callSymbol})
+	if err != nil {
+		return put, call, fmt.Errorf("failed to quote %s and %s: %w", putSymbol, callSymbol, err)
+	}
+	var foundPut, foundCall bool
+	for _, q := range quotes {
+		switch q.Symbol {
+		case putSymbol:
+			put, foundPut = q, true
+		case callSymbol:
+			call, foundCall = q, true
+		}
+	}
+	if !foundPut || !foundCall {
+		return put, call, fmt.Errorf("missing quote for %s or %s", putSymbol, callSymbol)
+	}
+	return put, call, nil
+}
+
+// isEmergencyExit reports whether a close must go out at its protective price without walking
+func isEmergencyExit(reason strategy.ExitReason) bool {
+	switch reason {
+	case strategy.ExitReasonStopLoss, strategy.ExitReasonDailyLoss,
+		strategy.ExitReasonEscalate, strategy.ExitReasonError:
+		return true
+	}
+	return false
+}
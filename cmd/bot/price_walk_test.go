@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/strategy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitPriceWalk(t *testing.T) {
+	tb := createTestBot(t)
+	defer tb.cancel()
+	tb.config.Execution.PriceWalk = true
+	tb.config.Execution.StepTicks = 1
+	tb.config.Execution.MaxConcessionTicks = 20
+	expiration := time.Now().UTC().AddDate(0, 0, 30).Truncate(24 * time.Hour)
+	position := newAdjustmentTestPosition(t, tb, expiration)
+
+	// Mid $1.10, natural $1.20
+	tb.mockBroker.On("GetQuotesCtx", mock.Anything, mock.Anything).Return(optionQuotes(expiration, 0.60, 0.50), nil)
+	tb.mockBroker.On("GetTickSize", "SPY").Return(0.01, nil)
+	tc := NewTradingCycle(tb.Bot)
+
+	walk := tc.exitPriceWalk(position, strategy.ExitReasonTime, 1.25)
+	require.NotNil(t, walk)
+	assert.InDelta(t, 1.10, walk.Start, 1e-9)
+	assert.InDelta(t, 1.20, walk.Limit, 1e-9, "the walk stops at the natural price")
+
+	walk = tc.exitPriceWalk(position, strategy.ExitReasonProfitTarget, 1.15)
+	require.NotNil(t, walk)
+	assert.InDelta(t, 1.15, walk.Limit, 1e-9, "a profit-target exit never pays more than its target debit")
+
+	assert.Nil(t, tc.exitPriceWalk(position, strategy.ExitReasonProfitTarget, 1.05),
+		"a profit-target exit is not walked from a mid above its target debit")
+
+	tb.mockBroker.Calls = nil
+	assert.Nil(t, tc.exitPriceWalk(position, strategy.ExitReasonStopLoss, 8.80))
+	tb.mockBroker.AssertNotCalled(t, "GetQuotesCtx", mock.Anything, mock.Anything)
+
+	tb.config.Execution.PriceWalk = false
+	assert.Nil(t, tc.exitPriceWalk(position, strategy.ExitReasonTime, 1.25))
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/orders"
+	"github.com/eddiefleurent/scranton_strangler/internal/util"
+)
+
+// profitTargetDebit returns the per-spread debit at which the position's resting
+// profit-target order buys it back, rounded down to the symbol's tick
+func (b *Bot) profitTargetDebit(position *models.Position) float64 {
+	tickSize, err := b.broker.GetTickSize(position.Symbol)
+	if err != nil || tickSize <= 0 {
+		tickSize = 0.01
+	}
+	debit := math.Abs(position.GetNetCredit()) * (1 - b.strategy.ProfitTargetFor(position))
+	return math.Max(util.FloorToTick(debit, tickSize), tickSize)
+}
+
+// placeProfitTarget rests a GTC buy-to-close at the position's profit target. It is
+// registered to run when an entry fills; ensureProfitTargets covers everything else.
+func (b *Bot) placeProfitTarget(positionID string) {
+	position, found := b.storage.GetPositionByID(positionID)
+	if !found || !needsProfitTarget(&position) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(b.ctx, 15*time.Second)
+	defer cancel()
+	if err := b.orderManager.PlaceProfitTarget(ctx, positionID, b.profitTargetDebit(&position)); err != nil {
+		b.logger.Printf("Warning: No profit target order for position %s, exit checks still apply: %v",
+			shortID(positionID), err)
+	}
+}
+
+// ensureProfitTargets places missing profit-target orders and re-prices those whose target
+// changed, e.g. after a roll added credit or Third Down lowered the target
+func (tc *TradingCycle) ensureProfitTargets(positions []models.Position) {
+	for i := range positions {
+		position, found := tc.bot.storage.GetPositionByID(positions[i].ID)
+		if !found || !needsProfitTarget(&position) {
+			continue
+		}
+		target := tc.bot.profitTargetDebit(&position)
+		if link := position.WorkingOrder(models.OrderRoleProfitTarget); link != nil {
+			if math.Abs(link.LimitPrice-target) < 1e-9 {
+				continue
+			}
+			tc.bot.logger.Printf("Position %s profit target moved from $%.2f to $%.2f, re-pricing order %s",
+				shortID(position.ID), link.LimitPrice, target, link.OrderID)
+			ctx, cancel := context.WithTimeout(tc.bot.ctx, 15*time.Second)
+			if err := tc.bot.orderManager.RepriceProfitTarget(ctx, position.ID, target); err != nil {
+				// It may have just filled; the position monitor's order sync will pick that up
+				tc.bot.logger.Printf("Warning: Profit target for position %s left at $%.2f: %v",
+					shortID(position.ID), link.LimitPrice, err)
+			}
+			cancel()
+			continue
+		}
+		tc.bot.placeProfitTarget(position.ID)
+	}
+}
+
+// releaseLinkedOrders cancels a position's resting profit-target order, and waits for the broker
+// to confirm, before another order changes or closes its legs. It refreshes position from storage
+// and returns false when the caller must not send its order: the profit target filled first,
+// or a cancel could not be confirmed and the old order may still be working.
+func (tc *TradingCycle) releaseLinkedOrders(position *models.Position, action string) bool {
+	stored, found := tc.bot.storage.GetPositionByID(position.ID)
+	if !found || stored.WorkingOrder(models.OrderRoleProfitTarget) == nil {
+		return true
+	}
+
+	err := tc.bot.orderManager.CancelLinkedOrders(tc.bot.ctx, position.ID)
+	switch {
+	case err == nil:
+		if latest, ok := tc.bot.storage.GetPositionByID(position.ID); ok {
+			*position = latest
+		}
+		return true
+	case errors.Is(err, orders.ErrProfitTargetFilled):
+		tc.bot.logger.Printf("Position %s was closed by its profit target order before %s", shortID(position.ID), action)
+	default:
+		tc.bot.logger.Printf("CRITICAL: Not %s for position %s while its linked orders may still be working: %v",
+			action, shortID(position.ID), err)
+	}
+	return false
+}
+
+// needsProfitTarget reports whether a position should carry a resting profit-target order
+func needsProfitTarget(position *models.Position) bool {
+	if position.ExitOrderID != "" || position.HasWorkingAdjustment() || position.Quantity <= 0 || position.GetNetCredit() <= 0 {
+		return false
+	}
+	switch position.GetCurrentState() {
+	case models.StateOpen, models.StateFirstDown, models.StateSecondDown,
+		models.StateThirdDown, models.StateFourthDown:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/orders"
+	"github.com/eddiefleurent/scranton_strangler/internal/strategy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newProfitTargetTestBot returns a test bot with a 400/460 position ($2.50 credit) carrying
+// a working profit-target order 701 at $1.25, and a fast cancel confirmation timeout
+func newProfitTargetTestBot(t *testing.T) (*TestBot, *models.Position, time.Time) {
+	t.Helper()
+	tb := createTestBot(t)
+	tb.config.Strategy.Exit.ProfitTargetOrder = true
+	tb.orderManager = orders.NewManager(tb.mockBroker, tb.mockStorage, tb.logger, tb.stop, orders.Config{
+		PollInterval:  10 * time.Millisecond,
+		CancelTimeout: 50 * time.Millisecond,
+	})
+
+	expiration := time.Now().UTC().AddDate(0, 0, 30).Truncate(24 * time.Hour)
+	position := newAdjustmentTestPosition(t, tb, expiration)
+	position.LinkOrder(models.OrderRoleProfitTarget, "701", 1.25)
+	require.NoError(t, tb.mockStorage.UpdatePosition(position))
+	return tb, position, expiration
+}
+
+// priceNear matches a limit price up to floating point noise from tick rounding
+func priceNear(want float64) interface{} {
+	return mock.MatchedBy(func(got float64) bool { return math.Abs(got-want) < 1e-9 })
+}
+
+func TestPlaceProfitTarget_OnEntryFill(t *testing.T) {
+	tb := createTestBot(t)
+	defer tb.cancel()
+	expiration := time.Now().UTC().AddDate(0, 0, 30).Truncate(24 * time.Hour)
+	position := newAdjustmentTestPosition(t, tb, expiration)
+
+	// 50% of a $2.50 credit: rest a GTC buy-to-close at $1.25
+	tb.mockBroker.On("GetTickSize", "SPY").Return(0.01, nil)
+	tb.mockBroker.On("CloseStranglePositionCtx", mock.Anything, "SPY", 400.0, 460.0,
+		expiration.Format("2006-01-02"), 1, priceNear(1.25), mock.Anything).Return(orderStatus(701, "open", 0), nil).Once()
+
+	tb.placeProfitTarget(position.ID)
+	tb.placeProfitTarget(position.ID) // Already linked, no second order
+
+	stored, found := tb.mockStorage.GetPositionByID(position.ID)
+	require.True(t, found)
+	link := stored.WorkingOrder(models.OrderRoleProfitTarget)
+	require.NotNil(t, link)
+	assert.Equal(t, "701", link.OrderID)
+	assert.InDelta(t, 1.25, link.LimitPrice, 1e-9)
+	assert.Empty(t, stored.ExitOrderID, "a resting profit target is not the exit order")
+	tb.mockBroker.AssertExpectations(t)
+}
+
+func TestExecuteExit_CancelsProfitTargetBeforeClose(t *testing.T) {
+	tb, position, expiration := newProfitTargetTestBot(t)
+	defer tb.cancel()
+	defer close(tb.stop)
+
+	var calls []string
+	tb.mockBroker.On("CancelOrderCtx", mock.Anything, 701).Return(orderStatus(701, "ok", 0), nil).
+		Run(func(mock.Arguments) { calls = append(calls, "cancel") })
+	tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 701).Return(orderStatus(701, "canceled", 0), nil)
+	tb.mockBroker.On("GetOptionChainCtx", mock.Anything, "SPY", expiration.Format("2006-01-02"), false).
+		Return([]broker.Option{
+			{Strike: 400, OptionType: "put", Bid: 6.95, Ask: 7.05},
+			{Strike: 460, OptionType: "call", Bid: 1.75, Ask: 1.85},
+		}, nil)
+	tb.mockBroker.On("GetTickSize", "SPY").Return(0.01, nil)
+	tb.mockBroker.On("CloseStranglePositionCtx", mock.Anything, "SPY", 400.0, 460.0, mock.Anything, 1, 8.80, mock.Anything).
+		Return(orderStatus(702, "open", 0), nil).
+		Run(func(mock.Arguments) { calls = append(calls, "close") })
+	tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 702).Return(orderStatus(702, "open", 0), nil).Maybe()
+
+	NewTradingCycle(tb.Bot).executeExit(position, strategy.ExitReasonStopLoss)
+
+	assert.Equal(t, []string{"cancel", "close"}, calls)
+	stored, found := tb.mockStorage.GetPositionByID(position.ID)
+	require.True(t, found)
+	assert.Equal(t, "702", stored.ExitOrderID)
+	assert.Nil(t, stored.WorkingOrder(models.OrderRoleProfitTarget))
+	require.Len(t, stored.LinkedOrders, 2)
+	assert.Equal(t, models.OrderLinkCancelled, stored.LinkedOrders[0].Status)
+	assert.Equal(t, models.OrderRoleStopLoss, stored.LinkedOrders[1].Role)
+	assert.Equal(t, "702", stored.LinkedOrders[1].OrderID)
+}
+
+func TestExecuteExit_UnconfirmedCancelBlocksClose(t *testing.T) {
+	tb, position, _ := newProfitTargetTestBot(t)
+	defer tb.cancel()
+
+	tb.mockBroker.On("CancelOrderCtx", mock.Anything, 701).Return(nil, errors.New("gateway timeout"))
+	tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 701).Return(orderStatus(701, "open", 0), nil)
+
+	NewTradingCycle(tb.Bot).executeExit(position, strategy.ExitReasonStopLoss)
+
+	tb.mockBroker.AssertNotCalled(t, "CloseStranglePositionCtx", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	stored, found := tb.mockStorage.GetPositionByID(position.ID)
+	require.True(t, found)
+	link := stored.WorkingOrder(models.OrderRoleProfitTarget)
+	require.NotNil(t, link, "the profit target must stay linked while it may be working")
+	assert.Contains(t, link.LastError, "gateway timeout")
+	assert.Empty(t, stored.ExitOrderID)
+}
+
+func TestExecuteExit_ProfitTargetFilledDuringCancel(t *testing.T) {
+	tb, position, _ := newProfitTargetTestBot(t)
+	defer tb.cancel()
+
+	tb.mockBroker.On("CancelOrderCtx", mock.Anything, 701).Return(nil, errors.New("order already filled"))
+	tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 701).Return(orderStatus(701, "filled", 1.20), nil)
+
+	NewTradingCycle(tb.Bot).executeExit(position, strategy.ExitReasonStopLoss)
+
+	tb.mockBroker.AssertNotCalled(t, "CloseStranglePositionCtx", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	_, found := tb.mockStorage.GetPositionByID(position.ID)
+	assert.False(t, found, "position should be closed by the profit target fill")
+	history := tb.mockStorage.GetHistory()
+	require.Len(t, history, 1)
+	assert.Equal(t, string(strategy.ExitReasonProfitTarget), history[0].ExitReason)
+	assert.InDelta(t, 130.0, history[0].CurrentPnL, 1e-9) // ($2.50 - $1.20) x 100
+}
+
+func TestPositionMonitor_ProfitTargetFillStopsMonitoring(t *testing.T) {
+	tb, position, _ := newProfitTargetTestBot(t)
+	defer tb.cancel()
+
+	orders := &broker.OrdersResponse{}
+	orders.Orders.Order = []broker.Order{{ID: 701, Status: "filled", AvgFillPrice: 1.25}}
+	tb.mockBroker.On("GetOrdersCtx", mock.Anything).Return(orders, nil)
+
+	NewPositionMonitor(tb.Bot).Check(tb.ctx)
+
+	_, found := tb.mockStorage.GetPositionByID(position.ID)
+	assert.False(t, found)
+	tb.mockBroker.AssertNotCalled(t, "GetQuotesCtx", mock.Anything, mock.Anything)
+	history := tb.mockStorage.GetHistory()
+	require.Len(t, history, 1)
+	assert.InDelta(t, 125.0, history[0].CurrentPnL, 1e-9)
+}
+
+func TestEnsureProfitTargets_RepricesAfterTargetChange(t *testing.T) {
+	tb, position, _ := newProfitTargetTestBot(t)
+	defer tb.cancel()
+
+	// A roll added $1.30 of credit: the target debit moves from $1.25 to $1.90
+	position.Adjustments = append(position.Adjustments, models.Adjustment{Type: models.AdjustmentRoll, Credit: 1.30})
+	require.NoError(t, tb.mockStorage.UpdatePosition(position))
+
+	tb.mockBroker.On("GetTickSize", "SPY").Return(0.01, nil)
+	tb.mockBroker.On("ModifyOrderCtx", mock.Anything, 701, priceNear(1.90), 0.01, "").
+		Return(orderStatus(701, "ok", 0), nil).Once()
+
+	tc := NewTradingCycle(tb.Bot)
+	tc.ensureProfitTargets(tb.mockStorage.GetCurrentPositions())
+	tc.ensureProfitTargets(tb.mockStorage.GetCurrentPositions()) // Up to date, nothing to do
+
+	stored, found := tb.mockStorage.GetPositionByID(position.ID)
+	require.True(t, found)
+	link := stored.WorkingOrder(models.OrderRoleProfitTarget)
+	require.NotNil(t, link)
+	assert.Equal(t, "701", link.OrderID, "the order is re-priced in place")
+	assert.InDelta(t, 1.90, link.LimitPrice, 1e-9)
+	require.Len(t, stored.LinkedOrders, 1)
+	tb.mockBroker.AssertNotCalled(t, "CancelOrderCtx", mock.Anything, mock.Anything)
+	tb.mockBroker.AssertNotCalled(t, "CloseStranglePositionCtx", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -7,10 +7,13 @@ import (
 	"math"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/journal"
+	"github.com/eddiefleurent/scranton_strangler/internal/metrics"
 	"github.com/eddiefleurent/scranton_strangler/internal/models"
 	"github.com/eddiefleurent/scranton_strangler/internal/storage"
 	"github.com/google/uuid"
@@ -23,6 +26,8 @@ type Reconciler struct {
 	logger         *log.Logger
 	coldStartOnce  sync.Once
 	phantomThreshold time.Duration
+	journal        *journal.Journal // Records reconciliation decisions; nil disables
+	metrics        *metrics.Recorder // Counts reconciliations and their decisions; nil disables
 }
 
 // NewReconciler creates a new position reconciler
@@ -44,13 +49,23 @@ const positionsFetchTimeout = 8 * time.Second
 // 3. Cold start: stored positions are empty while broker has positions.
 //    We log this and rely on the orphan-detection pass below to create recovery positions.
 func (r *Reconciler) ReconcilePositions(storedPositions []models.Position) []models.Position {
+	positions, err := r.reconcile(storedPositions)
+	r.metrics.ReconcileRun(err)
+	if err != nil {
+		r.logger.Printf("Reconciliation skipped: %v", err)
+		return storedPositions // Return unchanged on error
+	}
+	return positions
+}
+
+// reconcile is ReconcilePositions, failing when broker positions cannot be fetched
+func (r *Reconciler) reconcile(storedPositions []models.Position) ([]models.Position, error) {
 	// Get current broker positions with timeout to prevent stuck cycles
 	ctx, cancel := context.WithTimeout(context.Background(), positionsFetchTimeout)
 	defer cancel()
 	brokerPositions, err := r.broker.GetPositionsCtx(ctx)
 	if err != nil {
-		r.logger.Printf("Failed to get broker positions for reconciliation: %v", err)
-		return storedPositions // Return unchanged on error
+		return nil, fmt.Errorf("failed to get broker positions: %w", err)
 	}
 
 	r.logger.Printf("Reconciling %d stored positions with %d broker positions",
@@ -75,6 +90,13 @@ func (r *Reconciler) ReconcilePositions(storedPositions []models.Position) []mod
 			continue
 		}
 
+		// A submitted position has no broker legs until its entry order fills. While that order
+		// is working, or filled and awaits its poller, the position is neither a phantom nor closed.
+		if position.GetCurrentState() == models.StateSubmitted && r.entryOrderLive(&position) {
+			activePositions = append(activePositions, position)
+			continue
+		}
+
 		// PHANTOM POSITION DETECTION: Clean up positions with quantity=0 that never filled
 		// These are created when orders timeout during polling but never actually executed
 		// Check BEFORE updating LastChecked so we can use the old value for age calculation
@@ -113,6 +135,7 @@ func (r *Reconciler) ReconcilePositions(storedPositions []models.Position) []mod
 					activePositions = append(activePositions, position)
 				} else {
 					r.logger.Printf("Successfully cleaned up stale phantom position %s", shortID(position.ID))
+					r.journalReconcile(&position, "phantom_removed", fmt.Sprintf("stale phantom removed after %.1f hours", timeSinceCreation.Hours()))
 				}
 				continue
 			} else if timeSinceCreation > threshold {
@@ -126,6 +149,7 @@ func (r *Reconciler) ReconcilePositions(storedPositions []models.Position) []mod
 					activePositions = append(activePositions, position) // Keep in list if can't delete
 				} else {
 					r.logger.Printf("Successfully cleaned up phantom position %s", shortID(position.ID))
+					r.journalReconcile(&position, "phantom_removed", fmt.Sprintf("phantom removed after %.0f minutes", timeSinceCreation.Minutes()))
 				}
 				continue // Skip to next position
 			}
@@ -156,6 +180,7 @@ func (r *Reconciler) ReconcilePositions(storedPositions []models.Position) []mod
 
 			r.logger.Printf("Position %s closed due to manual intervention. Final P&L: $%.2f",
 				shortID(position.ID), finalPnL)
+			r.journalReconcile(&position, "closed", "closed: no longer open in broker")
 		} else {
 			// Position is still active in broker
 			// Update LastChecked in storage
@@ -192,6 +217,7 @@ func (r *Reconciler) ReconcilePositions(storedPositions []models.Position) []mod
 				r.logger.Printf("Failed to update phantom position: %v", err)
 			} else {
 				r.logger.Printf("Successfully updated phantom position %s with broker data", shortID(phantomToUpdate.ID))
+				r.journalReconcile(phantomToUpdate, "phantom_opened", "phantom matched to broker strangle and opened")
 			}
 		} else {
 			// No phantom found, create a recovery position
@@ -203,12 +229,13 @@ func (r *Reconciler) ReconcilePositions(storedPositions []models.Position) []mod
 				} else {
 					activePositions = append(activePositions, *recoveryPos)
 					r.logger.Printf("Added recovery position %s for orphaned strangle", shortID(recoveryPos.ID))
+					r.journalReconcile(recoveryPos, "recovered", "recovery position created for orphaned broker strangle")
 				}
 			}
 		}
 	}
 
-	return activePositions
+	return activePositions, nil
 }
 
 // orphanedStrangle represents a strangle position found in broker but not in storage
@@ -222,6 +249,34 @@ type orphanedStrangle struct {
 	callCostBasis float64 // Cost basis for call leg from broker
 }
 
+// entryOrderLive reports whether a submitted position's entry order is still working or has
+// filled contracts. An order whose status cannot be fetched counts as live, so a position is
+// never deleted on an unconfirmed assumption; positions without an order ID are not live.
+func (r *Reconciler) entryOrderLive(position *models.Position) bool {
+	if position.EntryOrderID == "" {
+		return false
+	}
+	orderID, err := strconv.Atoi(position.EntryOrderID)
+	if err != nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), positionsFetchTimeout)
+	defer cancel()
+	resp, err := r.broker.GetOrderStatusCtx(ctx, orderID)
+	if err != nil || resp == nil || resp.Order.ID == 0 {
+		r.logger.Printf("Could not check entry order %d of submitted position %s, keeping it: %v",
+			orderID, shortID(position.ID), err)
+		return true
+	}
+
+	switch strings.ToLower(resp.Order.Status) {
+	case "canceled", "cancelled", "rejected", "expired":
+		return resp.Order.ExecQuantity > 0
+	}
+	return true
+}
+
 // findOrphanedStrangles identifies strangle positions in broker that aren't tracked in storage
 func (r *Reconciler) findOrphanedStrangles(brokerPositions []broker.PositionItem, activePositions []models.Position) []orphanedStrangle {
 	var orphaned []orphanedStrangle
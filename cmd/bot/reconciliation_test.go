@@ -51,6 +51,14 @@ func (m *mockBrokerForReconciliation) GetQuote(symbol string) (*broker.QuoteItem
 	return &broker.QuoteItem{Last: 500.0}, nil
 }
 
+func (m *mockBrokerForReconciliation) GetQuotes(symbols []string) ([]broker.QuoteItem, error) {
+	return nil, nil
+}
+
+func (m *mockBrokerForReconciliation) GetQuotesCtx(ctx context.Context, symbols []string) ([]broker.QuoteItem, error) {
+	return nil, nil
+}
+
 func (m *mockBrokerForReconciliation) GetExpirations(symbol string) ([]string, error) {
 	return nil, nil
 }
@@ -117,6 +125,14 @@ func (m *mockBrokerForReconciliation) CancelOrderCtx(ctx context.Context, orderI
 	return &broker.OrderResponse{}, nil
 }
 
+func (m *mockBrokerForReconciliation) ModifyOrder(orderID int, price, tickSize float64, duration string) (*broker.OrderResponse, error) {
+	return &broker.OrderResponse{}, nil
+}
+
+func (m *mockBrokerForReconciliation) ModifyOrderCtx(ctx context.Context, orderID int, price, tickSize float64, duration string) (*broker.OrderResponse, error) {
+	return &broker.OrderResponse{}, nil
+}
+
 func (m *mockBrokerForReconciliation) GetOrders() (*broker.OrdersResponse, error) {
 	return &broker.OrdersResponse{}, nil
 }
@@ -145,6 +161,16 @@ func (m *mockBrokerForReconciliation) PlaceSellToCloseOrder(optionSymbol string,
 	return &broker.OrderResponse{}, nil
 }
 
+func (m *mockBrokerForReconciliation) PlaceMultiLegOrder(legs []broker.OrderLeg, priceType broker.NetPriceType,
+	price float64, duration string, tag string) (*broker.OrderResponse, error) {
+	return &broker.OrderResponse{}, nil
+}
+
+func (m *mockBrokerForReconciliation) PlaceMultiLegOrderCtx(ctx context.Context, legs []broker.OrderLeg, priceType broker.NetPriceType,
+	price float64, duration string, tag string) (*broker.OrderResponse, error) {
+	return &broker.OrderResponse{}, nil
+}
+
 func (m *mockBrokerForReconciliation) PlaceBuyToCloseMarketOrder(optionSymbol string, quantity int,
 	duration string, tag string) (*broker.OrderResponse, error) {
 	return &broker.OrderResponse{}, nil
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+)
+
+// resumeInFlightOrders picks up entry and exit orders that were working when the bot last
+// stopped, since their polling goroutines did not survive the restart. It runs before startup
+// reconciliation so an order that filled while the bot was down is recorded on its position
+// before broker positions are compared with storage.
+func (b *Bot) resumeInFlightOrders(ctx context.Context) {
+	for _, position := range b.storage.GetCurrentPositions() {
+		orderIDStr, isEntry := inFlightOrder(&position)
+		if orderIDStr == "" {
+			continue
+		}
+		orderID, err := strconv.Atoi(orderIDStr)
+		if err != nil {
+			b.logger.Printf("Warning: Position %s has invalid order ID %q, cannot resume tracking it",
+				shortID(position.ID), orderIDStr)
+			continue
+		}
+
+		callCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		working, err := b.orderManager.ResumeOrder(callCtx, position.ID, orderID, isEntry)
+		cancel()
+		if err != nil {
+			// Reconciliation will not treat the position as a phantom while its order is unconfirmed
+			b.logger.Printf("Warning: Could not resume order %d for position %s: %v", orderID, shortID(position.ID), err)
+			continue
+		}
+		if working {
+			b.logger.Printf("Resumed tracking of working order %d for position %s", orderID, shortID(position.ID))
+		}
+	}
+}
+
+// inFlightOrder returns the ID of the entry or exit order a position is waiting on, or ""
+func inFlightOrder(position *models.Position) (orderID string, isEntry bool) {
+	switch {
+	case position.GetCurrentState() == models.StateSubmitted:
+		return position.EntryOrderID, true
+	case position.GetCurrentState() != models.StateClosed && position.ExitOrderID != "":
+		return position.ExitOrderID, false
+	}
+	return "", false
+}
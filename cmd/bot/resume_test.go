@@ -0,0 +1,113 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newSubmittedTestPosition stores a position waiting on entry order orderID
+func newSubmittedTestPosition(t *testing.T, tb *TestBot, orderID string) *models.Position {
+	t.Helper()
+	position := models.NewPosition("resume-pos", "SPY", 400, 460, time.Now().UTC().AddDate(0, 0, 45), 2)
+	require.NoError(t, position.TransitionState(models.StateSubmitted, models.ConditionOrderPlaced))
+	position.EntryOrderID = orderID
+	require.NoError(t, tb.mockStorage.AddPosition(position))
+	return position
+}
+
+func TestResumeInFlightOrders_EntryFilledWhileDown(t *testing.T) {
+	tb := createTestBot(t)
+	defer tb.cancel()
+	newSubmittedTestPosition(t, tb, "100")
+
+	filled := orderStatus(100, "filled", 2.40)
+	filled.Order.Type = "credit"
+	filled.Order.Quantity = 2
+	filled.Order.ExecQuantity = 2
+	tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 100).Return(filled, nil)
+
+	tb.resumeInFlightOrders(tb.ctx)
+
+	position, found := tb.mockStorage.GetPositionByID("resume-pos")
+	require.True(t, found)
+	assert.Equal(t, models.StateOpen, position.GetCurrentState())
+	assert.Equal(t, 2, position.Quantity)
+	assert.InDelta(t, 2.40, position.CreditReceived, 1e-9)
+}
+
+func TestResumeInFlightOrders_ExitCanceledWhileDown(t *testing.T) {
+	tb := createTestBot(t)
+	defer tb.cancel()
+	expiration := time.Now().UTC().AddDate(0, 0, 30).Truncate(24 * time.Hour)
+	position := newAdjustmentTestPosition(t, tb, expiration)
+	position.ExitOrderID = "300"
+	position.ExitReason = "time"
+	require.NoError(t, tb.mockStorage.UpdatePosition(position))
+
+	tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 300).Return(orderStatus(300, "canceled", 0), nil)
+
+	tb.resumeInFlightOrders(tb.ctx)
+
+	updated, found := tb.mockStorage.GetPositionByID(position.ID)
+	require.True(t, found)
+	assert.Equal(t, models.StateOpen, updated.GetCurrentState())
+	assert.Empty(t, updated.ExitOrderID, "ended exit order should be cleared so a new exit can be placed")
+}
+
+func TestResumeInFlightOrders_WorkingEntryIsPolled(t *testing.T) {
+	tb := createTestBot(t)
+	defer tb.cancel()
+	defer close(tb.stop)
+	newSubmittedTestPosition(t, tb, "100")
+
+	tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 100).Return(orderStatus(100, "open", 0), nil)
+
+	tb.resumeInFlightOrders(tb.ctx)
+
+	position, found := tb.mockStorage.GetPositionByID("resume-pos")
+	require.True(t, found)
+	assert.Equal(t, models.StateSubmitted, position.GetCurrentState())
+}
+
+func TestReconcilePositions_KeepsSubmittedPositionWithWorkingOrder(t *testing.T) {
+	tb := createTestBot(t)
+	defer tb.cancel()
+	position := newSubmittedTestPosition(t, tb, "100")
+	position.LastChecked = time.Now().UTC().Add(-time.Hour) // Well past the phantom threshold
+	require.NoError(t, tb.mockStorage.UpdatePosition(position))
+
+	tb.mockBroker.On("GetPositionsCtx", mock.Anything).Return([]broker.PositionItem{}, nil)
+	tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 100).Return(orderStatus(100, "open", 0), nil)
+
+	reconciler := NewReconciler(tb.mockBroker, tb.mockStorage, tb.logger, 10*time.Minute)
+	active := reconciler.ReconcilePositions(tb.mockStorage.GetCurrentPositions())
+
+	require.Len(t, active, 1)
+	stored, found := tb.mockStorage.GetPositionByID("resume-pos")
+	require.True(t, found, "submitted position with a working order must not be deleted as a phantom")
+	assert.Equal(t, models.StateSubmitted, stored.GetCurrentState())
+}
+
+func TestReconcilePositions_RemovesSubmittedPositionWhoseOrderEnded(t *testing.T) {
+	tb := createTestBot(t)
+	defer tb.cancel()
+	position := newSubmittedTestPosition(t, tb, "100")
+	position.LastChecked = time.Now().UTC().Add(-time.Hour)
+	require.NoError(t, tb.mockStorage.UpdatePosition(position))
+
+	tb.mockBroker.On("GetPositionsCtx", mock.Anything).Return([]broker.PositionItem{}, nil)
+	tb.mockBroker.On("GetOrderStatusCtx", mock.Anything, 100).Return(orderStatus(100, "expired", 0), nil)
+
+	reconciler := NewReconciler(tb.mockBroker, tb.mockStorage, tb.logger, 10*time.Minute)
+	active := reconciler.ReconcilePositions(tb.mockStorage.GetCurrentPositions())
+
+	assert.Empty(t, active)
+	_, found := tb.mockStorage.GetPositionByID("resume-pos")
+	assert.False(t, found, "phantom with an ended entry order should be cleaned up")
+}
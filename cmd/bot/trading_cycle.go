@@ -21,13 +21,17 @@ import (
 type TradingCycle struct {
 	bot        *Bot
 	reconciler *Reconciler
+	marketOpen bool // Real-time market status, once the cycle has checked it
 }
 
 // NewTradingCycle creates a new trading cycle handler
 func NewTradingCycle(bot *Bot) *TradingCycle {
+	reconciler := NewReconciler(bot.broker, bot.storage, bot.logger, bot.config.Broker.PhantomThreshold)
+	reconciler.journal = bot.journal
+	reconciler.metrics = bot.metrics
 	return &TradingCycle{
 		bot:        bot,
-		reconciler: NewReconciler(bot.broker, bot.storage, bot.logger, bot.config.Broker.PhantomThreshold),
+		reconciler: reconciler,
 	}
 }
 
@@ -47,11 +51,13 @@ func (tc *TradingCycle) Run() {
 
 	// Check real-time market status
 	isMarketOpen, marketState := tc.checkMarketStatus()
+	tc.marketOpen = isMarketOpen
 	if !tc.shouldRunCycle(isMarketOpen, marketState) {
 		return
 	}
 
 	tc.bot.logger.Println("Starting trading cycle...")
+	tc.bot.journal.SetCorrelationID(generateCorrelationID(tc.bot.logger))
 
 	// Get and reconcile positions
 	positions := tc.bot.storage.GetCurrentPositions()
@@ -66,9 +72,19 @@ func (tc *TradingCycle) Run() {
 		tc.checkAdjustments(positions)
 	}
 
-	// Check for new entries
+	// Keep a resting profit-target order on every open position
+	if tc.bot.config.Strategy.Exit.ProfitTargetOrder && isMarketOpen {
+		tc.ensureProfitTargets(positions)
+	}
+
+	// Check for new entries unless the daily loss limit has halted trading or an operator paused them.
+	// The loss check runs first so a breach still flattens positions while entries are paused.
 	if isMarketOpen {
-		tc.checkEntryConditions(positions)
+		if tc.checkDailyLossLimit(positions) {
+			tc.bot.logger.Println("New entries blocked by daily loss limit")
+		} else if !tc.entriesPaused() {
+			tc.checkEntryConditions(positions)
+		}
 	}
 
 	tc.bot.logger.Println("Trading cycle complete")
@@ -147,6 +163,15 @@ func (tc *TradingCycle) checkExitConditions(positions []models.Position) {
 
 		posCopy := position
 		shouldExit, reason := tc.bot.strategy.CheckExitConditions(&posCopy)
+		if shouldExit && reason == strategy.ExitReasonProfitTarget {
+			// A resting order at the current target will fill on its own
+			if link := posCopy.WorkingOrder(models.OrderRoleProfitTarget); link != nil &&
+				link.LimitPrice >= tc.bot.profitTargetDebit(&posCopy) {
+				tc.bot.logger.Printf("Position %s reached its profit target, leaving resting order %s to fill",
+					shortID(position.ID), link.OrderID)
+				continue
+			}
+		}
 		if shouldExit {
 			tc.bot.logger.Printf("Exit signal for position %s: %s", shortID(position.ID), reason)
 			tc.executeExit(&posCopy, reason)
@@ -186,6 +211,8 @@ func (tc *TradingCycle) checkEntryConditions(positions []models.Position) {
 	if err != nil {
 		tc.bot.logger.Printf("Warning: Could not get option buying power: %v", err)
 		buyingPower = 0
+	} else {
+		tc.bot.metrics.SetBuyingPower(buyingPower)
 	}
 
 	tc.bot.logger.Printf("Available option buying power: $%.2f", buyingPower)
@@ -228,8 +255,8 @@ func (tc *TradingCycle) executeEntry() {
 		return
 	}
 
-	tc.bot.logger.Printf("Found strangle: Put %.0f / Call %.0f, Credit: $%.2f",
-		order.PutStrike, order.CallStrike, order.Credit)
+	tc.bot.logger.Printf("Found strangle: Put %.0f / Call %.0f, Credit: $%.2f, Expiration: %s (entry rule %s)",
+		order.PutStrike, order.CallStrike, order.Credit, order.Expiration, order.EntryRule)
 
 	// Risk check
 	if order.Quantity > tc.bot.config.Risk.MaxContracts {
@@ -249,6 +276,9 @@ func (tc *TradingCycle) executeEntry() {
 		return
 	}
 
+	// Quote the legs before placing, so the walk starts from the price the order goes out at
+	walk := tc.entryPriceWalk(order, expirationTime)
+
 	// Place order
 	placedOrder, err := tc.placeStrangleOrder(order)
 	if err != nil {
@@ -272,9 +302,11 @@ func (tc *TradingCycle) executeEntry() {
 
 	tc.bot.logger.Printf("Position saved: ID=%s, LimitPrice=$%.2f, DTE=%d",
 		position.ID, position.EntryLimitPrice, position.DTE)
+	tc.bot.journal.NoteSpot(position.Symbol, position.EntrySpot)
+	tc.bot.journalOrderPlaced(position, placedOrder.Order.ID, position.EntryLimitPrice, "entry")
 
-	// Start order status polling
-	go tc.bot.orderManager.PollOrderStatus(position.ID, placedOrder.Order.ID, true)
+	// Track the order, re-pricing it when price walking is enabled
+	go tc.bot.orderManager.WalkOrder(position.ID, placedOrder.Order.ID, true, walk)
 }
 
 func (tc *TradingCycle) placeStrangleOrder(order *strategy.StrangleOrder) (*broker.OrderResponse, error) {
@@ -360,10 +392,33 @@ func (tc *TradingCycle) createPosition(order *strategy.StrangleOrder, expiration
 func (tc *TradingCycle) executeExit(position *models.Position, reason strategy.ExitReason) {
 	tc.bot.logger.Printf("Executing exit for position %s: %s", shortID(position.ID), reason)
 
+	tc.bot.exitMu.Lock()
+	defer tc.bot.exitMu.Unlock()
+
+	// The position monitor may have closed this position, or an adjustment claimed it,
+	// since the caller read it
+	if stored, found := tc.bot.storage.GetPositionByID(position.ID); found {
+		if stored.ExitOrderID != "" && stored.ExitOrderID != position.ExitOrderID {
+			tc.bot.logger.Printf("Position %s already has close order %s, skipping", shortID(position.ID), stored.ExitOrderID)
+			return
+		}
+		if stored.HasWorkingAdjustment() {
+			tc.bot.logger.Printf("Position %s has adjustment order %s working, skipping exit",
+				shortID(position.ID), stored.AdjustmentOrderID)
+			return
+		}
+		// Keep the daily loss check's mark, which may be newer than the caller's copy
+		position.DayStartDate, position.DayStartPnL = stored.DayStartDate, stored.DayStartPnL
+	}
+
 	if !tc.isPositionReadyForExit(position) {
 		return
 	}
 
+	if !tc.releaseLinkedOrders(position, "sending a close order") {
+		return
+	}
+
 	tc.logPositionClose(position)
 
 	maxDebit := tc.calculateMaxDebit(position, reason)
@@ -382,6 +437,12 @@ func (tc *TradingCycle) executeExit(position *models.Position, reason strategy.E
 	maxDebit = math.Max(maxDebit, tickSize)
 	maxDebit = util.CeilToTick(maxDebit, tickSize)
 
+	// A walked exit starts at mid rather than the protective debit
+	walk := tc.exitPriceWalk(position, reason, maxDebit)
+	if walk != nil {
+		maxDebit = walk.Start
+	}
+
 	// Place close order
 	closeOrder, err := tc.bot.retryClient.ClosePositionWithRetry(
 		tc.bot.ctx,
@@ -401,15 +462,19 @@ func (tc *TradingCycle) executeExit(position *models.Position, reason strategy.E
 
 	// Update position
 	position.ExitOrderID = fmt.Sprintf("%d", closeOrder.Order.ID)
+	if reason == strategy.ExitReasonStopLoss {
+		position.LinkOrder(models.OrderRoleStopLoss, position.ExitOrderID, maxDebit)
+	}
 	if err := tc.bot.storage.UpdatePosition(position); err != nil {
 		tc.bot.logger.Printf("Failed to update position %s with exit order ID: %v", shortID(position.ID), err)
 	}
 
 	tc.bot.logger.Printf("Close order placed for position %s: order_id=%d, max_debit=$%.2f",
 		shortID(position.ID), closeOrder.Order.ID, maxDebit)
+	tc.bot.journalOrderPlaced(position, closeOrder.Order.ID, maxDebit, fmt.Sprintf("exit (%s)", reason))
 
-	// Start order status polling
-	go tc.bot.orderManager.PollOrderStatus(position.ID, closeOrder.Order.ID, false)
+	// Track the order, re-pricing it when the exit is walked
+	go tc.bot.orderManager.WalkOrder(position.ID, closeOrder.Order.ID, false, walk)
 }
 
 func (tc *TradingCycle) isPositionReadyForExit(position *models.Position) bool {
@@ -484,7 +549,8 @@ func (tc *TradingCycle) calculateMaxDebit(position *models.Position, reason stra
 	netCredit := position.GetNetCredit()
 	absNetCredit := math.Abs(netCredit)
 
-	pt := tc.bot.config.Strategy.Exit.ProfitTarget
+	// Third Down positions exit at their own, smaller profit target
+	pt := tc.bot.strategy.ProfitTargetFor(position)
 	if pt < 0 || pt > 1 {
 		tc.bot.logger.Printf("ERROR: Invalid ProfitTarget %.3f, using default 0.50", pt)
 		pt = 0.50
@@ -516,7 +582,7 @@ func (tc *TradingCycle) calculateMaxDebit(position *models.Position, reason stra
 		}
 		return result
 		
-	case strategy.ExitReasonStopLoss:
+	case strategy.ExitReasonStopLoss, strategy.ExitReasonDailyLoss, strategy.ExitReasonManual:
 		if cvErr == nil && position.Quantity != 0 {
 			return currentVal / (float64(position.Quantity) * 100)
 		}
@@ -532,19 +598,6 @@ func (tc *TradingCycle) calculateMaxDebit(position *models.Position, reason stra
 	}
 }
 
-func (tc *TradingCycle) checkAdjustmentsForPosition(position *models.Position) {
-	// Placeholder for adjustment logic (Phase 2) - Football System
-	// This would check if the position needs to be adjusted based on
-	// market movement and the football system rules:
-	// - First Down: Manage at 21 DTE or when tested
-	// - Second Down: Roll for additional credit 
-	// - Third Down: Roll again if beneficial
-	// - Fourth Down: Close position (punt)
-	if tc.bot.config.Strategy.Adjustments.EnableAdjustmentStub {
-		tc.bot.logger.Printf("Football System adjustment check for position %s not yet implemented", shortID(position.ID))
-	}
-}
-
 // computeEntryLimitPrice calculates the entry limit price using the correct tick size for the symbol
 func (tc *TradingCycle) computeEntryLimitPrice(symbol string, credit float64) float64 {
 	tickSize, err := tc.bot.broker.GetTickSize(symbol)
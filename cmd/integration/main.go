@@ -82,6 +82,11 @@ func main() {
 		MaxDTE:              cfg.Strategy.Exit.MaxDTE,
 		AllocationPct:       cfg.Strategy.AllocationPct,
 		MinIVPct:            cfg.Strategy.Entry.MinIVPct,
+		MinIVR:              cfg.Strategy.Entry.MinIVR,
+		IVLookbackDays:      cfg.Strategy.Entry.IVLookbackDays,
+		MinIVRReadings:      cfg.Strategy.Entry.MinIVRReadings,
+		RiskFreeRate:        cfg.Strategy.Pricing.RiskFreeRate,
+		DividendYield:       cfg.Strategy.Pricing.DividendYield,
 		MinCredit:           cfg.Strategy.Entry.MinCredit,
 		EscalateLossPct:     cfg.Strategy.EscalateLossPct,
 		StopLossPct:         cfg.Strategy.Exit.StopLossPct,
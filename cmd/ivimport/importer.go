@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+)
+
+// maxDecimalIV rejects values that are almost certainly percentages passed without -percent
+const maxDecimalIV = 5.0
+
+// dateLayouts are the date formats accepted in vendor exports
+var dateLayouts = []string{"2006-01-02", "01/02/2006", "2006/01/02", "20060102", time.RFC3339}
+
+// csvColumns maps accepted header names to the field they hold
+var csvColumns = map[string]string{
+	"date":               "date",
+	"day":                "date",
+	"symbol":             "symbol",
+	"ticker":             "symbol",
+	"underlying":         "symbol",
+	"iv":                 "iv",
+	"atm_iv":             "iv",
+	"implied_volatility": "iv",
+	"impvol":             "iv",
+}
+
+// rawReading is one row of an import file before validation
+type rawReading struct {
+	Symbol string `json:"symbol"`
+	Date   string `json:"date"`
+	IV     any    `json:"iv"`
+	Source string `json:"-"` // file:line for error messages
+}
+
+// importOptions control how raw rows are turned into IV readings
+type importOptions struct {
+	DefaultSymbol string         // Used when a row has no symbol
+	Percent       bool           // IV values are percentages (18.5 = 18.5%)
+	Location      *time.Location // Readings are stamped at local midnight, like live readings
+}
+
+// importResult holds validated readings plus what was dropped on the way
+type importResult struct {
+	Readings   []models.IVReading
+	Invalid    []string // One message per rejected row
+	Duplicates int      // Rows replaced by a later row for the same symbol and date
+}
+
+// gap is a run of missing weekdays between two readings
+type gap struct {
+	Symbol string
+	From   time.Time
+	To     time.Time
+	Days   int
+}
+
+// parseCSV reads rows from a CSV export with a header row
+func parseCSV(r io.Reader, name string) ([]rawReading, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to read header: %w", name, err)
+	}
+	index := map[string]int{}
+	for i, col := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(col, "\ufeff")))
+		if field, ok := csvColumns[key]; ok {
+			index[field] = i
+		}
+	}
+	if _, ok := index["date"]; !ok {
+		return nil, fmt.Errorf("%s: missing date column", name)
+	}
+	if _, ok := index["iv"]; !ok {
+		return nil, fmt.Errorf("%s: missing iv column", name)
+	}
+
+	var rows []rawReading
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, line, err)
+		}
+		field := func(f string) string {
+			if i, ok := index[f]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		rows = append(rows, rawReading{
+			Symbol: field("symbol"),
+			Date:   field("date"),
+			IV:     field("iv"),
+			Source: fmt.Sprintf("%s:%d", name, line),
+		})
+	}
+	return rows, nil
+}
+
+// parseJSON reads an array of {"symbol","date","iv"} objects
+func parseJSON(r io.Reader, name string) ([]rawReading, error) {
+	var rows []rawReading
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	for i := range rows {
+		rows[i].Source = fmt.Sprintf("%s[%d]", name, i)
+	}
+	return rows, nil
+}
+
+// buildReadings validates rows and keeps the last row for each symbol and date
+func buildReadings(rows []rawReading, opts importOptions, now time.Time) importResult {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var result importResult
+	byKey := map[string]int{}
+	for _, row := range rows {
+		reading, err := toReading(row, opts, loc)
+		if err != nil {
+			result.Invalid = append(result.Invalid, fmt.Sprintf("%s: %v", row.Source, err))
+			continue
+		}
+		reading.Timestamp = now
+
+		key := reading.Symbol + "|" + reading.Date.Format("2006-01-02")
+		if i, ok := byKey[key]; ok {
+			result.Readings[i] = reading
+			result.Duplicates++
+			continue
+		}
+		byKey[key] = len(result.Readings)
+		result.Readings = append(result.Readings, reading)
+	}
+
+	sort.Slice(result.Readings, func(i, j int) bool {
+		a, b := result.Readings[i], result.Readings[j]
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		return a.Date.Before(b.Date)
+	})
+	return result
+}
+
+// toReading validates a single row
+func toReading(row rawReading, opts importOptions, loc *time.Location) (models.IVReading, error) {
+	symbol := strings.ToUpper(strings.TrimSpace(row.Symbol))
+	if symbol == "" {
+		symbol = strings.ToUpper(opts.DefaultSymbol)
+	}
+	if symbol == "" {
+		return models.IVReading{}, fmt.Errorf("missing symbol")
+	}
+
+	date, err := parseDate(row.Date, loc)
+	if err != nil {
+		return models.IVReading{}, err
+	}
+
+	iv, err := parseIV(row.IV)
+	if err != nil {
+		return models.IVReading{}, err
+	}
+	if opts.Percent {
+		iv /= 100
+	}
+	if iv <= 0 || iv > maxDecimalIV {
+		return models.IVReading{}, fmt.Errorf("iv %.4f out of range (0, %.0f]; use -percent for percentage values", iv, maxDecimalIV)
+	}
+
+	return models.IVReading{Symbol: symbol, Date: date, IV: iv}, nil
+}
+
+// parseDate parses a vendor date and returns local midnight of that day
+func parseDate(s string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			t = t.In(loc)
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// parseIV accepts a JSON number or a numeric string
+func parseIV(v any) (float64, error) {
+	switch iv := v.(type) {
+	case float64:
+		return iv, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(iv), "%"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid iv %q", iv)
+		}
+		return f, nil
+	case nil:
+		return 0, fmt.Errorf("missing iv")
+	default:
+		return 0, fmt.Errorf("invalid iv %v", v)
+	}
+}
+
+// findGaps reports runs of more than minDays missing weekdays between consecutive
+// readings of each symbol. readings must be sorted by symbol and date. Single-day
+// gaps are usually market holidays, so callers typically pass minDays of 1.
+func findGaps(readings []models.IVReading, minDays int) []gap {
+	var gaps []gap
+	for i := 1; i < len(readings); i++ {
+		prev, cur := readings[i-1], readings[i]
+		if prev.Symbol != cur.Symbol {
+			continue
+		}
+		missing := 0
+		var first, last time.Time
+		for d := prev.Date.AddDate(0, 0, 1); d.Before(cur.Date); d = d.AddDate(0, 0, 1) {
+			if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+				continue
+			}
+			if missing == 0 {
+				first = d
+			}
+			last = d
+			missing++
+		}
+		if missing > minDays {
+			gaps = append(gaps, gap{Symbol: cur.Symbol, From: first, To: last, Days: missing})
+		}
+	}
+	return gaps
+}
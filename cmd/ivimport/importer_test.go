@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCSV_HeaderAliases(t *testing.T) {
+	input := "\ufeffTicker,Date,ATM_IV\nspy,2024-01-02,0.14\nSPY,01/03/2024,0.15\n"
+	rows, err := parseCSV(strings.NewReader(input), "spy.csv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Symbol != "spy" || rows[1].Date != "01/03/2024" || rows[1].IV != "0.15" {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+	if rows[1].Source != "spy.csv:3" {
+		t.Errorf("Source = %s, want spy.csv:3", rows[1].Source)
+	}
+
+	if _, err := parseCSV(strings.NewReader("date,close\n2024-01-02,470\n"), "bad.csv"); err == nil {
+		t.Error("expected error for missing iv column")
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	input := `[{"symbol":"SPY","date":"2024-01-02","iv":0.14},{"date":"2024-01-03","iv":"15.5"}]`
+	rows, err := parseJSON(strings.NewReader(input), "spy.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 2 || rows[0].IV != 0.14 || rows[1].IV != "15.5" {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+}
+
+func TestBuildReadings_ValidatesAndDeduplicates(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	rows := []rawReading{
+		{Date: "2024-01-03", IV: "15.0", Source: "a:2"},
+		{Date: "2024-01-02", IV: "14.0", Source: "a:3"},
+		{Date: "2024-01-03", IV: "16.0", Source: "a:4"}, // Replaces a:2
+		{Date: "not-a-date", IV: "14.0", Source: "a:5"},
+		{Date: "2024-01-04", IV: "-1", Source: "a:6"},
+		{Date: "2024-01-05", IV: "abc", Source: "a:7"},
+	}
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	result := buildReadings(rows, importOptions{DefaultSymbol: "spy", Percent: true, Location: loc}, now)
+	if len(result.Readings) != 2 || result.Duplicates != 1 || len(result.Invalid) != 3 {
+		t.Fatalf("got %d readings, %d duplicates, invalid %v", len(result.Readings), result.Duplicates, result.Invalid)
+	}
+	first, second := result.Readings[0], result.Readings[1]
+	if first.Symbol != "SPY" || first.IV != 0.14 || !first.Timestamp.Equal(now) {
+		t.Errorf("unexpected first reading: %+v", first)
+	}
+	if second.IV != 0.16 {
+		t.Errorf("duplicate date should keep the last row, got IV %.2f", second.IV)
+	}
+	if want := time.Date(2024, 1, 2, 0, 0, 0, 0, loc); !first.Date.Equal(want) {
+		t.Errorf("Date = %v, want NY midnight %v", first.Date, want)
+	}
+	if !strings.HasPrefix(result.Invalid[0], "a:5") {
+		t.Errorf("invalid row should carry its source, got %q", result.Invalid[0])
+	}
+
+	// Percentages without -percent are rejected rather than stored as 1400% IV
+	result = buildReadings(rows[:1], importOptions{DefaultSymbol: "SPY", Location: loc}, now)
+	if len(result.Readings) != 0 || len(result.Invalid) != 1 {
+		t.Errorf("expected percentage value to be rejected without -percent, got %+v", result)
+	}
+}
+
+func TestFindGaps(t *testing.T) {
+	rows := []rawReading{
+		{Date: "2024-01-02", IV: "0.14"}, // Tue
+		{Date: "2024-01-03", IV: "0.14"},
+		{Date: "2024-01-05", IV: "0.14"}, // Thu missing: single day, treated as holiday
+		{Date: "2024-01-12", IV: "0.14"}, // Mon-Thu missing
+		{Date: "2024-01-16", IV: "0.14"}, // Weekend plus Monday holiday
+	}
+	result := buildReadings(rows, importOptions{DefaultSymbol: "SPY"}, time.Now())
+
+	gaps := findGaps(result.Readings, 1)
+	if len(gaps) != 1 {
+		t.Fatalf("expected 1 gap, got %+v", gaps)
+	}
+	if gaps[0].Days != 4 || gaps[0].From.Format("2006-01-02") != "2024-01-08" || gaps[0].To.Format("2006-01-02") != "2024-01-11" {
+		t.Errorf("unexpected gap: %+v", gaps[0])
+	}
+
+	if gaps := findGaps(result.Readings, 0); len(gaps) != 3 {
+		t.Errorf("expected every missing weekday run with minDays 0, got %d", len(gaps))
+	}
+}
@@ -0,0 +1,161 @@
+// ivimport - Backfill historical IV readings from vendor CSV or JSON exports
+// IV Rank needs a year of daily readings; this loads that history into storage
+// so a new deployment does not have to collect it one day at a time.
+//
+// Usage:
+//
+//	ivimport [-config config.yaml | -storage positions.json] [-symbol SPY] [-percent] [-dry-run] FILE...
+//
+// CSV files need a header with date and iv columns (symbol is optional).
+// JSON files hold an array of {"symbol": "SPY", "date": "2024-01-02", "iv": 0.14}.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/config"
+	"github.com/eddiefleurent/scranton_strangler/internal/storage"
+)
+
+func main() {
+	var (
+		configPath  = flag.String("config", "config.yaml", "Path to configuration file (used for storage.path and storage.backend)")
+		storagePath = flag.String("storage", "", "Path to storage file (overrides config)")
+		symbol      = flag.String("symbol", "SPY", "Symbol for rows without a symbol column")
+		format      = flag.String("format", "", "Input format: csv or json (default: from file extension)")
+		percent     = flag.Bool("percent", false, "IV values are percentages (18.5 = 18.5%)")
+		gapDays     = flag.Int("gap-days", 1, "Report gaps longer than this many missing weekdays")
+		dryRun      = flag.Bool("dry-run", false, "Validate and report without writing to storage")
+	)
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] FILE...\n", os.Args[0])
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	path, backend := *storagePath, ""
+	if path == "" {
+		cfg, err := config.Load(*configPath)
+		if err != nil {
+			log.Fatalf("Failed to load config (use -storage to skip): %v", err)
+		}
+		path, backend = cfg.Storage.Path, cfg.Storage.Backend
+	}
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		log.Printf("Warning: failed to load America/New_York, using UTC: %v", err)
+		loc = time.UTC
+	}
+
+	var rows []rawReading
+	for _, name := range flag.Args() {
+		fileRows, err := readFile(name, *format)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", name, err)
+		}
+		fmt.Printf("Read %d rows from %s\n", len(fileRows), name)
+		rows = append(rows, fileRows...)
+	}
+
+	result := buildReadings(rows, importOptions{DefaultSymbol: *symbol, Percent: *percent, Location: loc}, time.Now().UTC())
+
+	fmt.Printf("\n=== Validation ===\n")
+	fmt.Printf("Valid readings:  %d\n", len(result.Readings))
+	fmt.Printf("Duplicate dates: %d (last row kept)\n", result.Duplicates)
+	fmt.Printf("Invalid rows:    %d\n", len(result.Invalid))
+	for _, msg := range result.Invalid {
+		fmt.Printf("  - %s\n", msg)
+	}
+
+	printCoverage(result)
+
+	// Gaps are reported but do not fail the import; holidays and vendor outages are expected
+	if gaps := findGaps(result.Readings, *gapDays); len(gaps) > 0 {
+		fmt.Printf("\n=== Gaps (more than %d missing weekdays) ===\n", *gapDays)
+		for _, g := range gaps {
+			fmt.Printf("  %s: %s to %s (%d weekdays)\n", g.Symbol, g.From.Format("2006-01-02"), g.To.Format("2006-01-02"), g.Days)
+		}
+	}
+
+	if *dryRun {
+		fmt.Printf("\nDry run: nothing written to %s\n", path)
+		return
+	}
+	if len(result.Readings) == 0 {
+		log.Fatalf("No valid readings to import")
+	}
+
+	store, err := storage.NewStorage(backend, path)
+	if err != nil {
+		log.Fatalf("Failed to open storage %s: %v", path, err)
+	}
+
+	added, replaced := 0, 0
+	for i := range result.Readings {
+		reading := &result.Readings[i]
+		existing, err := store.GetIVReadings(reading.Symbol, reading.Date, reading.Date)
+		if err != nil {
+			log.Fatalf("Failed to check existing readings: %v", err)
+		}
+		if err := store.StoreIVReading(reading); err != nil {
+			log.Fatalf("Failed to store %s %s: %v", reading.Symbol, reading.Date.Format("2006-01-02"), err)
+		}
+		if len(existing) > 0 {
+			replaced++
+		} else {
+			added++
+		}
+	}
+
+	fmt.Printf("\n=== Import ===\n")
+	fmt.Printf("Stored %d readings in %s (%d new, %d replaced)\n", len(result.Readings), path, added, replaced)
+}
+
+// readFile parses one import file in the given or detected format
+func readFile(name, format string) ([]rawReading, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
+	}
+
+	f, err := os.Open(name) // #nosec G304 -- user-provided import file
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	switch format {
+	case "csv":
+		return parseCSV(f, name)
+	case "json":
+		return parseJSON(f, name)
+	default:
+		return nil, fmt.Errorf("unknown format %q (use -format csv or -format json)", format)
+	}
+}
+
+// printCoverage prints the date range and reading count per symbol
+func printCoverage(result importResult) {
+	if len(result.Readings) == 0 {
+		return
+	}
+	fmt.Printf("\n=== Coverage ===\n")
+	start := 0
+	for i := 1; i <= len(result.Readings); i++ {
+		if i < len(result.Readings) && result.Readings[i].Symbol == result.Readings[start].Symbol {
+			continue
+		}
+		first, last := result.Readings[start], result.Readings[i-1]
+		fmt.Printf("  %s: %d readings, %s to %s\n", first.Symbol, i-start,
+			first.Date.Format("2006-01-02"), last.Date.Format("2006-01-02"))
+		start = i
+	}
+}
@@ -0,0 +1,87 @@
+// journal - Replay position timelines from the bot's event journal
+// Lists the positions the journal knows about, or prints one position's state
+// transitions, order events and reconciliation decisions in order.
+//
+// Usage:
+//
+//	journal [-config config.yaml | -file data/journal.jsonl] [-position ID] [-json]
+//
+// -position accepts a full position ID or the shortened prefix printed in log lines.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/config"
+	"github.com/eddiefleurent/scranton_strangler/internal/journal"
+)
+
+func main() {
+	var (
+		configPath = flag.String("config", "config.yaml", "Path to configuration file (used for storage.journal_path)")
+		filePath   = flag.String("file", "", "Path to journal file (overrides config)")
+		positionID = flag.String("position", "", "Position ID or ID prefix to replay")
+		jsonOutput = flag.Bool("json", false, "Output entries as JSON Lines")
+	)
+	flag.Parse()
+
+	path := *filePath
+	if path == "" {
+		cfg, err := config.Load(*configPath)
+		if err != nil {
+			log.Fatalf("Failed to load config (use -file to skip): %v", err)
+		}
+		path = strings.TrimSpace(cfg.Storage.JournalPath)
+		if path == "" {
+			log.Fatalf("storage.journal_path is not set in %s", *configPath)
+		}
+	}
+
+	entries, err := journal.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Failed to read journal %s: %v", path, err)
+	}
+
+	if *positionID == "" {
+		printPositions(journal.Positions(entries))
+		return
+	}
+
+	id, err := journal.MatchPosition(entries, *positionID)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	timeline := journal.Timeline(entries, id)
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		for _, e := range timeline {
+			if err := enc.Encode(e); err != nil {
+				log.Fatalf("Failed to encode entry: %v", err)
+			}
+		}
+		return
+	}
+
+	fmt.Printf("=== Position %s (%d events) ===\n", id, len(timeline))
+	for _, e := range timeline {
+		fmt.Println(e.String())
+	}
+}
+
+func printPositions(summaries []journal.PositionSummary) {
+	if len(summaries) == 0 {
+		fmt.Println("Journal is empty")
+		return
+	}
+	fmt.Printf("%-36s  %-6s  %-12s  %6s  %-16s  %-16s\n", "POSITION", "SYMBOL", "STATE", "EVENTS", "FIRST", "LAST")
+	for _, s := range summaries {
+		fmt.Printf("%-36s  %-6s  %-12s  %6d  %-16s  %-16s\n", s.PositionID, s.Symbol, s.State, s.Events,
+			s.First.UTC().Format("2006-01-02 15:04"), s.Last.UTC().Format("2006-01-02 15:04"))
+	}
+}
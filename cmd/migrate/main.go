@@ -0,0 +1,143 @@
+// migrate - Copy a positions.json storage file into a storage backend
+// Loads a JSONStorage file, writes its positions, history, daily P&L, IV readings and
+// trading halt into the backend built by storage.NewStorage, then reads it back to check
+// row counts and that statistics recomputed from history match what was written.
+//
+// Usage:
+//
+//	migrate [-config config.yaml | -from positions.json] -to TARGET [-backend sqlite] [-dry-run] [-force]
+//
+// The target backend is taken from -backend, or else from the TARGET extension, so
+// "-to data/strangler.db" writes a SQLite database. Point storage.path (and
+// storage.backend) at the target once it verifies.
+//
+// Statistics are always recomputed from history for the target, so a source file whose
+// stored totals have drifted is reported and corrected rather than copied as is.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/config"
+	"github.com/eddiefleurent/scranton_strangler/internal/storage"
+)
+
+func main() {
+	var (
+		configPath = flag.String("config", "config.yaml", "Path to configuration file (used for storage.path)")
+		fromPath   = flag.String("from", "", "Source JSON storage file (overrides config)")
+		toPath     = flag.String("to", "", "Target storage location")
+		backend    = flag.String("backend", "", "Target backend: json or sqlite (default: from the -to extension)")
+		dryRun     = flag.Bool("dry-run", false, "Report counts and statistics without writing the target")
+		force      = flag.Bool("force", false, "Overwrite a target that already holds data")
+	)
+	flag.Parse()
+
+	source := *fromPath
+	if source == "" {
+		cfg, err := config.Load(*configPath)
+		if err != nil {
+			log.Fatalf("Failed to load config (use -from to skip): %v", err)
+		}
+		source = cfg.Storage.Path
+	}
+	if *toPath == "" && !*dryRun {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] -to TARGET\n", os.Args[0])
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	data, err := storage.ReadDataFile(source)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", source, err)
+	}
+
+	fmt.Printf("=== Source: %s ===\n", source)
+	printCounts(data.Counts())
+
+	recomputed := storage.RecomputeStatistics(data.History)
+	fmt.Printf("\n=== Statistics recomputed from history ===\n")
+	fmt.Printf("Trades:     %d (%d won, %d lost, %d even)\n",
+		recomputed.TotalTrades, recomputed.WinningTrades, recomputed.LosingTrades, recomputed.BreakEvenTrades)
+	fmt.Printf("Total P&L:  $%.2f (fees $%.2f)\n", recomputed.TotalPnL, recomputed.TotalFees)
+	if diffs := storage.StatisticsDiff(recomputed, data.Statistics); len(diffs) > 0 {
+		fmt.Printf("Stored statistics differ from history; the target gets the recomputed values:\n")
+		for _, diff := range diffs {
+			fmt.Printf("  - %s\n", diff)
+		}
+	} else {
+		fmt.Printf("Stored statistics match history\n")
+	}
+	data.Statistics = recomputed
+
+	if *dryRun {
+		fmt.Printf("\nDry run: nothing written\n")
+		return
+	}
+
+	if same, err := samePath(source, *toPath); err != nil {
+		log.Fatalf("Failed to resolve paths: %v", err)
+	} else if same {
+		log.Fatalf("Target %s is the source file", *toPath)
+	}
+
+	opened, err := storage.NewStorage(*backend, *toPath)
+	if err != nil {
+		log.Fatalf("Failed to open target %s: %v", *toPath, err)
+	}
+	if closer, ok := opened.(io.Closer); ok {
+		defer func() { _ = closer.Close() }()
+	}
+	target, ok := opened.(storage.Importer)
+	if !ok {
+		log.Fatalf("Target backend %T does not support importing", opened)
+	}
+	if existing := target.ExportData().Counts(); existing != (storage.RowCounts{}) && !*force {
+		log.Fatalf("Target %s already holds data (%+v); use -force to overwrite it", *toPath, existing)
+	}
+
+	if err := target.ImportData(data); err != nil {
+		log.Fatalf("Failed to write target: %v", err)
+	}
+
+	written := target.ExportData()
+	fmt.Printf("\n=== Target: %s (%T) ===\n", *toPath, opened)
+	printCounts(written.Counts())
+
+	if problems := storage.VerifyImport(data, written); len(problems) > 0 {
+		fmt.Printf("\nVerification FAILED:\n")
+		for _, problem := range problems {
+			fmt.Printf("  - %s\n", problem)
+		}
+		os.Exit(1)
+	}
+	fmt.Printf("\nVerified: row counts, trading halt, entry pause and statistics match\n")
+}
+
+func printCounts(c storage.RowCounts) {
+	fmt.Printf("Current positions: %d\n", c.CurrentPositions)
+	fmt.Printf("History:           %d\n", c.History)
+	fmt.Printf("IV readings:       %d\n", c.IVReadings)
+	fmt.Printf("Daily P&L days:    %d\n", c.DailyPnLDays)
+	fmt.Printf("Adjustments:       %d\n", c.Adjustments)
+	fmt.Printf("Trading halt:      %t\n", c.TradingHalt)
+	fmt.Printf("Entry pause:       %t\n", c.EntryPause)
+}
+
+// samePath reports whether two paths name the same file
+func samePath(a, b string) (bool, error) {
+	absA, err := filepath.Abs(a)
+	if err != nil {
+		return false, err
+	}
+	absB, err := filepath.Abs(b)
+	if err != nil {
+		return false, err
+	}
+	return absA == absB, nil
+}
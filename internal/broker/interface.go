@@ -24,6 +24,9 @@ type Broker interface {
 
 	// Market data
 	GetQuote(symbol string) (*QuoteItem, error)
+	// GetQuotes fetches quotes for several equity or option (OSI) symbols in one request
+	GetQuotes(symbols []string) ([]QuoteItem, error)
+	GetQuotesCtx(ctx context.Context, symbols []string) ([]QuoteItem, error)
 	GetExpirations(symbol string) ([]string, error)
 	GetExpirationsCtx(ctx context.Context, symbol string) ([]string, error)
 	GetOptionChain(symbol, expiration string, withGreeks bool) ([]Option, error)
@@ -51,6 +54,11 @@ type Broker interface {
 	CancelOrder(orderID int) (*OrderResponse, error)
 	CancelOrderCtx(ctx context.Context, orderID int) (*OrderResponse, error)
 
+	// Order modification: re-price a working order in place at a multiple of tickSize; an empty
+	// duration keeps the current one
+	ModifyOrder(orderID int, price, tickSize float64, duration string) (*OrderResponse, error)
+	ModifyOrderCtx(ctx context.Context, orderID int, price, tickSize float64, duration string) (*OrderResponse, error)
+
 	// Order retrieval
 	GetOrders() (*OrdersResponse, error)
 	GetOrdersCtx(ctx context.Context) (*OrdersResponse, error)
@@ -64,6 +72,10 @@ type Broker interface {
 		maxPrice float64, duration string, tag string) (*OrderResponse, error)
 	PlaceSellToCloseOrder(optionSymbol string, quantity int,
 		maxPrice float64, duration string, tag string) (*OrderResponse, error)
+	PlaceMultiLegOrder(legs []OrderLeg, priceType NetPriceType, price float64,
+		duration string, tag string) (*OrderResponse, error)
+	PlaceMultiLegOrderCtx(ctx context.Context, legs []OrderLeg, priceType NetPriceType, price float64,
+		duration string, tag string) (*OrderResponse, error)
 	PlaceBuyToCloseMarketOrder(optionSymbol string, quantity int,
 		duration string, tag string) (*OrderResponse, error)
 	PlaceBuyToCloseMarketOrderCtx(ctx context.Context, optionSymbol string, quantity int,
@@ -99,6 +111,7 @@ type TradierClientOption func(*TradierClientConfig)
 // TradierClientConfig holds configuration options for TradierClient
 type TradierClientConfig struct {
 	httpClient *http.Client
+	observer   RequestObserver
 }
 
 // WithHTTPClient sets a custom HTTP client for the TradierClient
@@ -119,6 +132,13 @@ func WithTransport(transport http.RoundTripper) TradierClientOption {
 	}
 }
 
+// WithRequestObserver reports every API request's endpoint, status and latency to observer
+func WithRequestObserver(observer RequestObserver) TradierClientOption {
+	return func(config *TradierClientConfig) {
+		config.observer = observer
+	}
+}
+
 // NewTradierClient creates a new Tradier broker client
 // profitTarget should be a ratio between 0.0 and 1.0 (e.g., 0.5 for 50% profit target)
 func NewTradierClient(apiKey, accountID string, sandbox bool,
@@ -146,6 +166,7 @@ func NewTradierClient(apiKey, accountID string, sandbox bool,
 	} else {
 		tradierAPI = NewTradierAPI(apiKey, accountID, sandbox)
 	}
+	tradierAPI.observer = config.observer
 
 	return &TradierClient{
 		TradierAPI:   tradierAPI,
@@ -288,6 +309,16 @@ func (t *TradierClient) CancelOrderCtx(ctx context.Context, orderID int) (*Order
 	return t.TradierAPI.CancelOrderCtx(ctx, orderID)
 }
 
+// ModifyOrder re-prices a working order
+func (t *TradierClient) ModifyOrder(orderID int, price, tickSize float64, duration string) (*OrderResponse, error) {
+	return t.TradierAPI.ModifyOrder(orderID, price, tickSize, duration)
+}
+
+// ModifyOrderCtx re-prices a working order with context
+func (t *TradierClient) ModifyOrderCtx(ctx context.Context, orderID int, price, tickSize float64, duration string) (*OrderResponse, error) {
+	return t.TradierAPI.ModifyOrderCtx(ctx, orderID, price, tickSize, duration)
+}
+
 // GetOrders retrieves all orders for the account
 func (t *TradierClient) GetOrders() (*OrdersResponse, error) {
 	return t.TradierAPI.GetOrders()
@@ -310,6 +341,18 @@ func (t *TradierClient) PlaceSellToCloseOrder(optionSymbol string, quantity int,
 	return t.TradierAPI.PlaceSellToCloseOrder(optionSymbol, quantity, maxPrice, duration, tag)
 }
 
+// PlaceMultiLegOrder places a multi-leg order at a single net limit price
+func (t *TradierClient) PlaceMultiLegOrder(legs []OrderLeg, priceType NetPriceType, price float64,
+	duration string, tag string) (*OrderResponse, error) {
+	return t.TradierAPI.PlaceMultiLegOrder(legs, priceType, price, duration, tag)
+}
+
+// PlaceMultiLegOrderCtx places a multi-leg order at a single net limit price with context support
+func (t *TradierClient) PlaceMultiLegOrderCtx(ctx context.Context, legs []OrderLeg, priceType NetPriceType, price float64,
+	duration string, tag string) (*OrderResponse, error) {
+	return t.TradierAPI.PlaceMultiLegOrderCtx(ctx, legs, priceType, price, duration, tag)
+}
+
 // PlaceBuyToCloseMarketOrder places a buy-to-close market order for a specific option
 func (t *TradierClient) PlaceBuyToCloseMarketOrder(optionSymbol string, quantity int,
 	duration string, tag string) (*OrderResponse, error) {
@@ -407,6 +450,39 @@ const (
 	DurationGTC DurationType = "gtc"
 )
 
+// OrderSide represents the side of a single order leg
+type OrderSide string
+
+const (
+	// SideBuyToOpen opens a long option leg
+	SideBuyToOpen OrderSide = "buy_to_open"
+	// SideBuyToClose closes a short option leg
+	SideBuyToClose OrderSide = "buy_to_close"
+	// SideSellToOpen opens a short option leg
+	SideSellToOpen OrderSide = "sell_to_open"
+	// SideSellToClose closes a long option leg
+	SideSellToClose OrderSide = "sell_to_close"
+)
+
+// NetPriceType is the pricing type of a multi-leg order's net limit
+type NetPriceType string
+
+const (
+	// NetCredit is a net credit limit: the minimum credit accepted for the whole order
+	NetCredit NetPriceType = "credit"
+	// NetDebit is a net debit limit: the maximum debit paid for the whole order
+	NetDebit NetPriceType = "debit"
+	// NetEven fills the order only at a net price of zero
+	NetEven NetPriceType = "even"
+)
+
+// OrderLeg is one option leg of a multi-leg order
+type OrderLeg struct {
+	OptionSymbol string
+	Side         OrderSide
+	Quantity     int
+}
+
 // Use OptionTypePut/OptionTypeCall everywhere to avoid duplication.
 
 // AbsDaysBetween calculates the absolute number of days between two dates
@@ -431,6 +507,11 @@ func (c *CircuitBreakerBroker) Underlying() Broker {
 	return c.broker
 }
 
+// State returns the circuit breaker's state: "closed", "half-open" or "open"
+func (c *CircuitBreakerBroker) State() string {
+	return stateName(c.breaker.State())
+}
+
 // exec is a generic helper for circuit breaker wrapper methods
 func execCircuitBreaker[T any](
 	breaker *gobreaker.CircuitBreaker,
@@ -559,6 +640,18 @@ func (c *CircuitBreakerBroker) GetQuote(symbol string) (*QuoteItem, error) {
 	return execCircuitBreaker(c.breaker, c.broker, func(b Broker) (*QuoteItem, error) { return b.GetQuote(symbol) })
 }
 
+// GetQuotes wraps the underlying broker call with circuit breaker
+func (c *CircuitBreakerBroker) GetQuotes(symbols []string) ([]QuoteItem, error) {
+	return execCircuitBreaker(c.breaker, c.broker, func(b Broker) ([]QuoteItem, error) { return b.GetQuotes(symbols) })
+}
+
+// GetQuotesCtx wraps the underlying broker call with circuit breaker and context support
+func (c *CircuitBreakerBroker) GetQuotesCtx(ctx context.Context, symbols []string) ([]QuoteItem, error) {
+	return execCircuitBreaker(c.breaker, c.broker, func(b Broker) ([]QuoteItem, error) {
+		return b.GetQuotesCtx(ctx, symbols)
+	})
+}
+
 // GetExpirations wraps the underlying broker call with circuit breaker
 func (c *CircuitBreakerBroker) GetExpirations(symbol string) ([]string, error) {
 	return execCircuitBreaker(c.breaker, c.broker, func(b Broker) ([]string, error) { return b.GetExpirations(symbol) })
@@ -629,6 +722,20 @@ func (c *CircuitBreakerBroker) CancelOrderCtx(ctx context.Context, orderID int)
 	})
 }
 
+// ModifyOrder wraps the underlying broker call with circuit breaker
+func (c *CircuitBreakerBroker) ModifyOrder(orderID int, price, tickSize float64, duration string) (*OrderResponse, error) {
+	return execCircuitBreaker(c.breaker, c.broker, func(b Broker) (*OrderResponse, error) {
+		return b.ModifyOrder(orderID, price, tickSize, duration)
+	})
+}
+
+// ModifyOrderCtx wraps the underlying broker call with circuit breaker
+func (c *CircuitBreakerBroker) ModifyOrderCtx(ctx context.Context, orderID int, price, tickSize float64, duration string) (*OrderResponse, error) {
+	return execCircuitBreaker(c.breaker, c.broker, func(b Broker) (*OrderResponse, error) {
+		return b.ModifyOrderCtx(ctx, orderID, price, tickSize, duration)
+	})
+}
+
 // GetOrders wraps the underlying broker call with circuit breaker
 func (c *CircuitBreakerBroker) GetOrders() (*OrdersResponse, error) {
 	return execCircuitBreaker(c.breaker, c.broker, func(b Broker) (*OrdersResponse, error) {
@@ -675,6 +782,22 @@ func (c *CircuitBreakerBroker) PlaceSellToCloseOrder(optionSymbol string, quanti
 	})
 }
 
+// PlaceMultiLegOrder wraps the underlying broker call with circuit breaker
+func (c *CircuitBreakerBroker) PlaceMultiLegOrder(legs []OrderLeg, priceType NetPriceType, price float64,
+	duration string, tag string) (*OrderResponse, error) {
+	return execCircuitBreaker(c.breaker, c.broker, func(b Broker) (*OrderResponse, error) {
+		return b.PlaceMultiLegOrder(legs, priceType, price, duration, tag)
+	})
+}
+
+// PlaceMultiLegOrderCtx wraps the underlying broker call with circuit breaker and context support
+func (c *CircuitBreakerBroker) PlaceMultiLegOrderCtx(ctx context.Context, legs []OrderLeg, priceType NetPriceType, price float64,
+	duration string, tag string) (*OrderResponse, error) {
+	return execCircuitBreaker(c.breaker, c.broker, func(b Broker) (*OrderResponse, error) {
+		return b.PlaceMultiLegOrderCtx(ctx, legs, priceType, price, duration, tag)
+	})
+}
+
 // PlaceBuyToCloseMarketOrder wraps the underlying broker call with circuit breaker
 func (c *CircuitBreakerBroker) PlaceBuyToCloseMarketOrder(optionSymbol string, quantity int,
 	duration string, tag string) (*OrderResponse, error) {
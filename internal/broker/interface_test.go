@@ -768,6 +768,22 @@ func (m *MockBroker) GetQuote(symbol string) (*QuoteItem, error) {
 	return &QuoteItem{Symbol: symbol, Last: 100.0}, nil
 }
 
+func (m *MockBroker) GetQuotes(symbols []string) ([]QuoteItem, error) {
+	return m.GetQuotesCtx(context.Background(), symbols)
+}
+
+func (m *MockBroker) GetQuotesCtx(ctx context.Context, symbols []string) ([]QuoteItem, error) {
+	m.callCount++
+	if m.shouldFail && m.callCount > m.failAfter {
+		return nil, errors.New("mock broker error")
+	}
+	quotes := make([]QuoteItem, 0, len(symbols))
+	for _, s := range symbols {
+		quotes = append(quotes, QuoteItem{Symbol: s, Last: 100.0})
+	}
+	return quotes, nil
+}
+
 func (m *MockBroker) GetExpirations(_ string) ([]string, error) {
 	m.callCount++
 	if m.shouldFail && m.callCount > m.failAfter {
@@ -853,6 +869,21 @@ func (m *MockBroker) CancelOrderCtx(_ context.Context, orderID int) (*OrderRespo
 	return m.CancelOrder(orderID)
 }
 
+func (m *MockBroker) ModifyOrder(orderID int, price, tickSize float64, duration string) (*OrderResponse, error) {
+	m.callCount++
+	if m.shouldFail && m.callCount > m.failAfter {
+		return nil, errors.New("mock broker error")
+	}
+	resp := &OrderResponse{}
+	resp.Order.ID = orderID
+	resp.Order.Status = "ok"
+	return resp, nil
+}
+
+func (m *MockBroker) ModifyOrderCtx(_ context.Context, orderID int, price, tickSize float64, duration string) (*OrderResponse, error) {
+	return m.ModifyOrder(orderID, price, tickSize, duration)
+}
+
 func (m *MockBroker) GetOrders() (*OrdersResponse, error) {
 	m.callCount++
 	if m.shouldFail && m.callCount > m.failAfter {
@@ -909,6 +940,22 @@ func (m *MockBroker) PlaceSellToCloseOrder(_ string, _ int,
 	return resp, nil
 }
 
+func (m *MockBroker) PlaceMultiLegOrder(_ []OrderLeg, _ NetPriceType,
+	_ float64, _ string, _ string) (*OrderResponse, error) {
+	m.callCount++
+	if m.shouldFail && m.callCount > m.failAfter {
+		return nil, errors.New("mock broker error")
+	}
+	resp := &OrderResponse{}
+	resp.Order.ID = 126
+	return resp, nil
+}
+
+func (m *MockBroker) PlaceMultiLegOrderCtx(_ context.Context, legs []OrderLeg, priceType NetPriceType,
+	price float64, duration string, tag string) (*OrderResponse, error) {
+	return m.PlaceMultiLegOrder(legs, priceType, price, duration, tag)
+}
+
 func (m *MockBroker) PlaceBuyToCloseMarketOrder(_ string, _ int, _ string, _ string) (*OrderResponse, error) {
 	m.callCount++
 	if m.shouldFail && m.callCount > m.failAfter {
@@ -1086,6 +1133,9 @@ func TestCircuitBreakerBroker_FailureScenarios(t *testing.T) {
 	if cb.breaker.State() != gobreaker.StateOpen {
 		t.Errorf("Circuit breaker should be open, but state is %s", cb.breaker.State())
 	}
+	if got := cb.State(); got != "open" {
+		t.Errorf("State() = %q, want open", got)
+	}
 }
 
 func TestCircuitBreakerBroker_RecoveryBehavior(t *testing.T) {
@@ -1181,8 +1231,12 @@ func TestCircuitBreakerBroker_AllMethods(t *testing.T) {
 		{"GetAccountBalance", func() error { _, err := cb.GetAccountBalance(); return err }},
 		{"GetPositions", func() error { _, err := cb.GetPositionsCtx(context.Background()); return err }},
 		{"GetQuote", func() error { _, err := cb.GetQuote("SPY"); return err }},
+		{"GetQuotes", func() error { _, err := cb.GetQuotes([]string{"SPY"}); return err }},
+		{"GetQuotesCtx", func() error { _, err := cb.GetQuotesCtx(context.Background(), []string{"SPY"}); return err }},
 		{"CancelOrder", func() error { _, err := cb.CancelOrder(123); return err }},
 		{"CancelOrderCtx", func() error { _, err := cb.CancelOrderCtx(context.Background(), 123); return err }},
+		{"ModifyOrder", func() error { _, err := cb.ModifyOrder(123, 1.25, 0.01, ""); return err }},
+		{"ModifyOrderCtx", func() error { _, err := cb.ModifyOrderCtx(context.Background(), 123, 1.25, 0.01, ""); return err }},
 		{"GetOrders", func() error { _, err := cb.GetOrders(); return err }},
 		{"GetOrdersCtx", func() error { _, err := cb.GetOrdersCtx(context.Background()); return err }},
 		{"GetExpirations", func() error { _, err := cb.GetExpirations("SPY"); return err }},
@@ -1210,6 +1264,13 @@ func TestCircuitBreakerBroker_AllMethods(t *testing.T) {
 			_, err := cb.PlaceSellToCloseOrder("SPY241220C00420000", 1, 5.0, "day", "test-tag")
 			return err
 		}},
+		{"PlaceMultiLegOrder", func() error {
+			_, err := cb.PlaceMultiLegOrder([]OrderLeg{
+				{OptionSymbol: "SPY241220C00420000", Side: SideBuyToClose, Quantity: 1},
+				{OptionSymbol: "SPY241220C00415000", Side: SideSellToOpen, Quantity: 1},
+			}, NetCredit, 0.5, "day", "test-tag")
+			return err
+		}},
 	}
 
 	for _, tt := range tests {
@@ -16,6 +16,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/util"
 )
 
 // Market clock state constants
@@ -55,9 +57,15 @@ type TradierAPI struct {
 	accountID  string
 	rateLimits RateLimits
 	sandbox    bool
-	timeout    time.Duration // configurable timeout for HTTP requests
+	timeout    time.Duration   // configurable timeout for HTTP requests
+	observer   RequestObserver // optional; told the outcome of every request
 }
 
+// RequestObserver is told the outcome of every API request: the endpoint path with account
+// and order IDs replaced by placeholders, the HTTP status code or "error" when no response
+// arrived, and how long the request took
+type RequestObserver func(endpoint, status string, elapsed time.Duration)
+
 // RateLimits defines API rate limits for different endpoint categories.
 type RateLimits struct {
 	MarketData int // requests per minute
@@ -466,6 +474,20 @@ type Order struct {
 	ID                int     `json:"id"`
 	Price             float64 `json:"price"`
 	Quantity          float64 `json:"quantity"`
+	// Legs holds per-leg fills of a multileg order; empty for single-leg orders
+	Legs singleOrArray[OrderLegStatus] `json:"leg,omitempty"`
+}
+
+// OrderLegStatus is the fill state of one leg of a multileg order.
+type OrderLegStatus struct {
+	ID                int     `json:"id"`
+	OptionSymbol      string  `json:"option_symbol"`
+	Side              string  `json:"side"`
+	Status            string  `json:"status"`
+	Quantity          float64 `json:"quantity"`
+	ExecQuantity      float64 `json:"exec_quantity"`
+	AvgFillPrice      float64 `json:"avg_fill_price"`
+	RemainingQuantity float64 `json:"remaining_quantity"`
 }
 
 // OrderResponse represents the order response from the Tradier API.
@@ -528,6 +550,29 @@ func (t *TradierAPI) GetQuote(symbol string) (*QuoteItem, error) {
 	return &first, nil
 }
 
+// GetQuotes retrieves quotes for several symbols in one request.
+func (t *TradierAPI) GetQuotes(symbols []string) ([]QuoteItem, error) {
+	return t.GetQuotesCtx(context.Background(), symbols)
+}
+
+// GetQuotesCtx retrieves quotes for several equity or option symbols in one request with
+// context support. Symbols without a quote are simply absent from the result.
+func (t *TradierAPI) GetQuotesCtx(ctx context.Context, symbols []string) ([]QuoteItem, error) {
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("no symbols requested")
+	}
+	params := url.Values{}
+	params.Set("symbols", strings.Join(symbols, ","))
+	params.Set("greeks", "false")
+	endpoint := t.baseURL + "/markets/quotes?" + params.Encode()
+
+	var response QuotesResponse
+	if err := t.makeRequestCtx(ctx, "GET", endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Quotes.Quote, nil
+}
+
 // GetExpirations retrieves available expiration dates for options on a symbol.
 func (t *TradierAPI) GetExpirations(symbol string) ([]string, error) {
 	return t.GetExpirationsCtx(context.Background(), symbol)
@@ -871,15 +916,11 @@ func (t *TradierAPI) placeStrangleOrderInternalCtx(
 	// Edge cases and precision notes:
 	// - Strikes ending in .995 may round unexpectedly (e.g., 394.995 → 395.000)
 	// - The eps constant (1e-9) handles floating point precision issues
-	// - Consider exposing a helper function formatStrikeForOCC() for consistency
+	// - BuildOptionSymbol exposes the same encoding to callers outside this package
 	//
 	// Example: $123.4567 → 123457 (rounded to nearest thousandth)
-	const eps = 1e-9
-	putStrikeInt := int(math.Round(putStrike*1000 + eps))
-	callStrikeInt := int(math.Round(callStrike*1000 + eps))
-
-	putSymbol := fmt.Sprintf("%s%sP%08d", symbol, expFormatted, putStrikeInt)
-	callSymbol := fmt.Sprintf("%s%sC%08d", symbol, expFormatted, callStrikeInt)
+	putSymbol := formatOSISymbol(symbol, expFormatted, 'P', putStrike)
+	callSymbol := formatOSISymbol(symbol, expFormatted, 'C', callStrike)
 
 	params := url.Values{}
 	params.Add("class", "multileg")
@@ -1013,6 +1054,50 @@ func (t *TradierAPI) CancelOrderCtx(ctx context.Context, orderID int) (*OrderRes
 	return &response, nil
 }
 
+// ModifyOrder changes the limit price, and optionally the duration, of a working order in place
+func (t *TradierAPI) ModifyOrder(orderID int, price, tickSize float64, duration string) (*OrderResponse, error) {
+	return t.ModifyOrderCtx(context.Background(), orderID, price, tickSize, duration)
+}
+
+// ModifyOrderCtx changes the limit price, and optionally the duration, of a working order in place
+// with context. The order keeps its ID and queue position is only lost if the price changes.
+// The price must be a multiple of tickSize, the order symbol's increment from GetTickSize.
+// An empty duration leaves the order's duration unchanged.
+func (t *TradierAPI) ModifyOrderCtx(ctx context.Context, orderID int, price, tickSize float64, duration string) (*OrderResponse, error) {
+	if orderID <= 0 {
+		return nil, fmt.Errorf("invalid order ID %d", orderID)
+	}
+	if price <= 0 {
+		return nil, fmt.Errorf("invalid price for limit order: %.4f, price must be positive", price)
+	}
+	if tickSize <= 0 {
+		return nil, fmt.Errorf("invalid tick size %.4f", tickSize)
+	}
+	if rounded := util.RoundToTick(price, tickSize); math.Abs(rounded-price) > 1e-9 {
+		return nil, fmt.Errorf("price %.4f is not a multiple of the %.2f tick (nearest %.2f)", price, tickSize, rounded)
+	}
+
+	params := url.Values{}
+	params.Add("price", fmt.Sprintf("%.2f", price))
+	if duration != "" {
+		nd, err := normalizeDuration(duration)
+		if err != nil {
+			return nil, err
+		}
+		params.Add("duration", nd)
+	}
+
+	endpoint := fmt.Sprintf("%s/accounts/%s/orders/%d", t.baseURL, t.accountID, orderID)
+	var response OrderResponse
+	if err := t.makeRequestCtx(ctx, "PUT", endpoint, params, &response); err != nil {
+		return nil, err
+	}
+	if response.Order.ID == 0 {
+		response.Order.ID = orderID
+	}
+	return &response, nil
+}
+
 // GetOrders retrieves all orders for the account from the current trading day
 func (t *TradierAPI) GetOrders() (*OrdersResponse, error) {
 	endpoint := fmt.Sprintf("%s/accounts/%s/orders", t.baseURL, t.accountID)
@@ -1120,6 +1205,86 @@ func (t *TradierAPI) PlaceSellToCloseOrder(optionSymbol string, quantity int, ma
 	return &response, nil
 }
 
+// PlaceMultiLegOrder places an order for an arbitrary set of option legs on one underlying,
+// filled together at a single net price. See PlaceMultiLegOrderCtx.
+func (t *TradierAPI) PlaceMultiLegOrder(legs []OrderLeg, priceType NetPriceType, price float64, duration string, tag ...string) (*OrderResponse, error) {
+	return t.PlaceMultiLegOrderCtx(context.Background(), legs, priceType, price, duration, tag...)
+}
+
+// PlaceMultiLegOrderCtx places an order for an arbitrary set of option legs on one underlying
+// using Tradier's multileg class, so the legs fill atomically. price is the per-share net
+// credit or debit limit for the whole order and must be zero for an even order.
+func (t *TradierAPI) PlaceMultiLegOrderCtx(ctx context.Context, legs []OrderLeg, priceType NetPriceType, price float64, duration string, tag ...string) (*OrderResponse, error) {
+	if len(legs) < 2 {
+		return nil, fmt.Errorf("multileg order requires at least 2 legs, got %d", len(legs))
+	}
+
+	// Validate net price against the price type
+	switch priceType {
+	case NetCredit, NetDebit:
+		if price <= 0 {
+			return nil, fmt.Errorf("invalid %s price: %.2f (must be > 0)", priceType, price)
+		}
+	case NetEven:
+		if price != 0 {
+			return nil, fmt.Errorf("invalid even price: %.2f (must be 0)", price)
+		}
+	default:
+		return nil, fmt.Errorf("invalid multileg price type '%s': must be one of 'credit', 'debit', or 'even'", priceType)
+	}
+
+	nd, err := normalizeDuration(duration)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every leg must be valid and on the same underlying
+	var symbol string
+	for i, leg := range legs {
+		switch leg.Side {
+		case SideBuyToOpen, SideBuyToClose, SideSellToOpen, SideSellToClose:
+		default:
+			return nil, fmt.Errorf("leg %d: invalid side '%s'", i, leg.Side)
+		}
+		if leg.Quantity <= 0 {
+			return nil, fmt.Errorf("leg %d: invalid quantity %d (must be > 0)", i, leg.Quantity)
+		}
+		underlying := ExtractUnderlyingFromOSI(leg.OptionSymbol)
+		if underlying == "" {
+			return nil, fmt.Errorf("leg %d: failed to extract underlying symbol from option symbol: %s", i, leg.OptionSymbol)
+		}
+		if symbol == "" {
+			symbol = underlying
+		} else if underlying != symbol {
+			return nil, fmt.Errorf("leg %d: underlying %s does not match %s", i, underlying, symbol)
+		}
+	}
+
+	params := url.Values{}
+	params.Add("class", "multileg")
+	params.Add("symbol", symbol)
+	params.Add("duration", nd)
+	params.Add("type", string(priceType))
+	if priceType != NetEven {
+		params.Add("price", fmt.Sprintf("%.2f", price))
+	}
+	if len(tag) > 0 && tag[0] != "" {
+		params.Add("tag", tag[0])
+	}
+	for i, leg := range legs {
+		params.Add(fmt.Sprintf("option_symbol[%d]", i), leg.OptionSymbol)
+		params.Add(fmt.Sprintf("side[%d]", i), string(leg.Side))
+		params.Add(fmt.Sprintf("quantity[%d]", i), fmt.Sprintf("%d", leg.Quantity))
+	}
+
+	endpoint := fmt.Sprintf("%s/accounts/%s/orders", t.baseURL, t.accountID)
+	var response OrderResponse
+	if err := t.makeRequestCtx(ctx, "POST", endpoint, params, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
 // PlaceBuyToCloseMarketOrder places a buy-to-close market order for an option position.
 func (t *TradierAPI) PlaceBuyToCloseMarketOrder(optionSymbol string, quantity int, duration string, tag ...string) (*OrderResponse, error) {
 	return t.PlaceBuyToCloseMarketOrderCtx(context.Background(), optionSymbol, quantity, duration, tag...)
@@ -1227,7 +1392,7 @@ func (t *TradierAPI) makeRequestCtx(ctx context.Context, method, endpoint string
 	var req *http.Request
 	var err error
 
-	if method == "POST" && params != nil {
+	if (method == "POST" || method == "PUT") && params != nil {
 		req, err = http.NewRequestWithContext(ctx, method, endpoint, strings.NewReader(params.Encode()))
 		if err != nil {
 			return err
@@ -1244,7 +1409,15 @@ func (t *TradierAPI) makeRequestCtx(ctx context.Context, method, endpoint string
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("User-Agent", "scranton-strangler/1.0 (+tradier)")
 
+	start := time.Now()
 	resp, err := t.client.Do(req)
+	if t.observer != nil {
+		status := "error"
+		if err == nil {
+			status = strconv.Itoa(resp.StatusCode)
+		}
+		t.observer(t.endpointLabel(endpoint), status, time.Since(start))
+	}
 	if err != nil {
 		return err
 	}
@@ -1289,6 +1462,27 @@ func (t *TradierAPI) makeRequestCtx(ctx context.Context, method, endpoint string
 	return nil
 }
 
+// endpointLabel reduces a request URL to its path with the account ID and numeric IDs
+// replaced, so requests group by endpoint
+func (t *TradierAPI) endpointLabel(endpoint string) string {
+	path := strings.TrimPrefix(endpoint, t.baseURL)
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if seg == "" {
+			continue
+		}
+		if seg == t.accountID {
+			segments[i] = ":account"
+		} else if _, err := strconv.Atoi(seg); err == nil {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
 // ============ Helper Functions ============
 
 // FindStrangleStrikes finds put and call strikes closest to target delta
@@ -1401,6 +1595,22 @@ func CheckStranglePosition(positions []PositionItem, symbol string) (hasStrangle
 	return
 }
 
+// BuildOptionSymbol builds an OSI-formatted option symbol
+// e.g., ("SPY", 2024-12-20, put, 450) -> "SPY241220P00450000"
+func BuildOptionSymbol(symbol string, expiration time.Time, optionType OptionType, strike float64) string {
+	typeChar := byte('C')
+	if optionType == OptionTypePut {
+		typeChar = 'P'
+	}
+	return formatOSISymbol(symbol, expiration.Format("060102"), typeChar, strike)
+}
+
+// formatOSISymbol encodes the strike to the nearest 1/1000th dollar as an 8-digit OSI suffix
+func formatOSISymbol(symbol, expYYMMDD string, typeChar byte, strike float64) string {
+	const eps = 1e-9
+	return fmt.Sprintf("%s%s%c%08d", symbol, expYYMMDD, typeChar, int(math.Round(strike*1000+eps)))
+}
+
 // ExtractUnderlyingFromOSI extracts the underlying symbol from an OSI-formatted option symbol
 // e.g., "SPY241220P00450000" -> "SPY"
 func ExtractUnderlyingFromOSI(s string) string {
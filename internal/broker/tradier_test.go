@@ -207,6 +207,46 @@ func TestMakeRequestCtx_Non2xxReturnsAPIError(t *testing.T) {
 	}
 }
 
+func TestMakeRequestCtx_ReportsToObserver(t *testing.T) {
+	api, srv := newTestAPIWithServer(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/orders/987") {
+			http.Error(w, "gone", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	})
+	defer srv.Close()
+
+	type call struct{ endpoint, status string }
+	var calls []call
+	api.observer = func(endpoint, status string, elapsed time.Duration) {
+		if elapsed < 0 {
+			t.Fatalf("elapsed = %v, want >= 0", elapsed)
+		}
+		calls = append(calls, call{endpoint, status})
+	}
+
+	var out map[string]any
+	_ = api.makeRequest("GET", api.baseURL+"/markets/quotes?symbols=SPY", nil, &out)
+	_ = api.makeRequest("GET", api.baseURL+"/accounts/ACC123/orders/987", nil, &out)
+	srv.Close()
+	_ = api.makeRequest("GET", api.baseURL+"/accounts/ACC123/balances", nil, &out)
+
+	want := []call{
+		{"/markets/quotes", "200"},
+		{"/accounts/:account/orders/:id", "404"},
+		{"/accounts/:account/balances", "error"},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("observed %d requests, want %d: %+v", len(calls), len(want), calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("request %d observed as %+v, want %+v", i, calls[i], want[i])
+		}
+	}
+}
+
 func TestGetQuote_SingleAndArrayAndEmpty(t *testing.T) {
 	// JSON bodies for single and array responses
 	single := `{"quotes":{"quote":{"symbol":"AAPL","description":"Apple","exch":"NMS","type":"stock","askexch":"Q","bidexch":"Q","trade_date":0,"low":0,"average_volume":0,"last_volume":0,"change_percentage":0,"open":0,"high":0,"volume":0,"close":0,"prevclose":0,"bid":10,"bidsize":1,"change":0,"ask":12,"asksize":1,"last":11}}}`
@@ -267,6 +307,76 @@ func TestGetExpirations(t *testing.T) {
 	}
 }
 
+func TestGetQuotes_Batched(t *testing.T) {
+	api, srv := newTestAPIWithServer(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/markets/quotes") {
+			t.Fatalf("path = %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("symbols"); got != "SPY251017P00400000,SPY251017C00460000" {
+			t.Fatalf("symbols = %q", got)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"quotes":{"quote":[` +
+			`{"symbol":"SPY251017P00400000","bid":1.10,"ask":1.20},` +
+			`{"symbol":"SPY251017C00460000","bid":0.90,"ask":1.00}]}}`))
+	})
+	defer srv.Close()
+
+	quotes, err := api.GetQuotes([]string{"SPY251017P00400000", "SPY251017C00460000"})
+	if err != nil {
+		t.Fatalf("GetQuotes error: %v", err)
+	}
+	if len(quotes) != 2 || quotes[1].Symbol != "SPY251017C00460000" || quotes[1].Ask != 1.00 {
+		t.Fatalf("quotes = %#v", quotes)
+	}
+
+	if _, err := api.GetQuotes(nil); err == nil {
+		t.Fatal("expected error for empty symbol list")
+	}
+}
+
+func TestModifyOrder(t *testing.T) {
+	api, srv := newTestAPIWithServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || !strings.HasSuffix(r.URL.Path, "/orders/701") {
+			t.Fatalf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm: %v", err)
+		}
+		if got := r.PostForm.Get("price"); got != "1.90" {
+			t.Fatalf("price = %q", got)
+		}
+		if got := r.PostForm.Get("duration"); got != "gtc" {
+			t.Fatalf("duration = %q", got)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"order":{"id":701,"status":"ok"}}`))
+	})
+	defer srv.Close()
+
+	resp, err := api.ModifyOrderCtx(context.Background(), 701, 1.90, 0.01, "GTC")
+	if err != nil {
+		t.Fatalf("ModifyOrderCtx error: %v", err)
+	}
+	if resp.Order.ID != 701 || resp.Order.Status != "ok" {
+		t.Fatalf("resp = %#v", resp.Order)
+	}
+
+	// Off-tick and non-positive prices are rejected before any request is sent
+	if _, err := api.ModifyOrder(701, 1.905, 0.01, ""); err == nil || !strings.Contains(err.Error(), "tick") {
+		t.Fatalf("expected tick error, got %v", err)
+	}
+	if _, err := api.ModifyOrder(701, 1.92, 0.05, ""); err == nil || !strings.Contains(err.Error(), "0.05 tick") {
+		t.Fatalf("expected error for a price off the symbol's 0.05 tick, got %v", err)
+	}
+	if _, err := api.ModifyOrder(701, 1.90, 0, ""); err == nil {
+		t.Fatal("expected error for zero tick size")
+	}
+	if _, err := api.ModifyOrder(701, 0, 0.01, ""); err == nil {
+		t.Fatal("expected error for zero price")
+	}
+}
+
 func TestGetExpirationsCtx(t *testing.T) {
 	api, srv := newTestAPIWithServer(func(w http.ResponseWriter, r *http.Request) {
 		if !strings.Contains(r.URL.Path, "/markets/options/expirations") {
@@ -556,6 +666,31 @@ func TestGetOrderStatus_and_GetOrderStatusCtx(t *testing.T) {
 	}
 }
 
+func TestGetOrderStatus_DecodesLegFills(t *testing.T) {
+	api, srv := newTestAPIWithServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"order":{"id":789,"class":"multileg","status":"partially_filled",
+			"quantity":5,"exec_quantity":2,"remaining_quantity":3,"leg":[
+			{"id":1,"option_symbol":"SPY250117P00400000","side":"sell_to_open","status":"partially_filled",
+			 "quantity":5,"exec_quantity":2,"avg_fill_price":1.30,"remaining_quantity":3},
+			{"id":2,"option_symbol":"SPY250117C00460000","side":"sell_to_open","status":"partially_filled",
+			 "quantity":5,"exec_quantity":2,"avg_fill_price":1.20,"remaining_quantity":3}]}}`))
+	})
+	defer srv.Close()
+
+	resp, err := api.GetOrderStatus(789)
+	if err != nil {
+		t.Fatalf("GetOrderStatus error: %v", err)
+	}
+	if len(resp.Order.Legs) != 2 {
+		t.Fatalf("expected 2 legs, got %d", len(resp.Order.Legs))
+	}
+	leg := resp.Order.Legs[1]
+	if leg.OptionSymbol != "SPY250117C00460000" || leg.ExecQuantity != 2 || leg.AvgFillPrice != 1.20 {
+		t.Errorf("unexpected call leg %+v", leg)
+	}
+}
+
 func TestPlaceBuyToCloseOrder_ValidatesInputsAndBuildsForm(t *testing.T) {
 	api, srv := newTestAPIWithServer(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -644,6 +779,81 @@ func TestPlaceSellToCloseOrder_ValidatesInputsAndBuildsForm(t *testing.T) {
 	}
 }
 
+func TestPlaceMultiLegOrder_ValidatesInputsAndBuildsForm(t *testing.T) {
+	api, srv := newTestAPIWithServer(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			t.Fatalf("failed to parse body: %v", err)
+		}
+		for key, expect := range map[string]string{
+			"class":            "multileg",
+			"symbol":           "SPY",
+			"type":             "credit",
+			"price":            "0.85",
+			"duration":         "day",
+			"tag":              "roll-tag",
+			"option_symbol[0]": "SPY250117C00480000",
+			"side[0]":          "buy_to_close",
+			"quantity[0]":      "2",
+			"option_symbol[1]": "SPY250117C00470000",
+			"side[1]":          "sell_to_open",
+			"quantity[1]":      "2",
+		} {
+			if got := form.Get(key); got != expect {
+				t.Fatalf("%s = %q, want %q (body: %s)", key, got, expect, body)
+			}
+		}
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(`{"order":{"id":9004,"status":"ok"}}`))
+	})
+	defer srv.Close()
+
+	roll := []OrderLeg{
+		{OptionSymbol: "SPY250117C00480000", Side: SideBuyToClose, Quantity: 2},
+		{OptionSymbol: "SPY250117C00470000", Side: SideSellToOpen, Quantity: 2},
+	}
+	resp, err := api.PlaceMultiLegOrder(roll, NetCredit, 0.85, "day", "roll-tag")
+	if err != nil || resp.Order.ID != 9004 {
+		t.Fatalf("PlaceMultiLegOrder got (%+v,%v)", resp, err)
+	}
+
+	if _, err := api.PlaceMultiLegOrder(roll[:1], NetCredit, 0.85, "day"); err == nil {
+		t.Fatalf("expected error: single leg")
+	}
+	if _, err := api.PlaceMultiLegOrder(roll, NetDebit, 0, "day"); err == nil {
+		t.Fatalf("expected error: non-positive debit")
+	}
+	if _, err := api.PlaceMultiLegOrder(roll, NetEven, 0.10, "day"); err == nil {
+		t.Fatalf("expected error: priced even order")
+	}
+	if _, err := api.PlaceMultiLegOrder(roll, NetCredit, 0.85, "week"); err == nil {
+		t.Fatalf("expected error: invalid duration")
+	}
+	badSide := []OrderLeg{roll[0], {OptionSymbol: roll[1].OptionSymbol, Side: "sell", Quantity: 2}}
+	if _, err := api.PlaceMultiLegOrder(badSide, NetCredit, 0.85, "day"); err == nil {
+		t.Fatalf("expected error: invalid side")
+	}
+	badQty := []OrderLeg{roll[0], {OptionSymbol: roll[1].OptionSymbol, Side: SideSellToOpen}}
+	if _, err := api.PlaceMultiLegOrder(badQty, NetCredit, 0.85, "day"); err == nil {
+		t.Fatalf("expected error: non-positive quantity")
+	}
+	mixed := []OrderLeg{roll[0], {OptionSymbol: "QQQ250117C00470000", Side: SideSellToOpen, Quantity: 2}}
+	if _, err := api.PlaceMultiLegOrder(mixed, NetCredit, 0.85, "day"); err == nil {
+		t.Fatalf("expected error: mixed underlyings")
+	}
+}
+
+func TestBuildOptionSymbol(t *testing.T) {
+	exp := time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC)
+	if got := BuildOptionSymbol("SPY", exp, OptionTypePut, 450); got != "SPY250117P00450000" {
+		t.Fatalf("put symbol = %s", got)
+	}
+	if got := BuildOptionSymbol("SPY", exp, OptionTypeCall, 472.5); got != "SPY250117C00472500" {
+		t.Fatalf("call symbol = %s", got)
+	}
+}
+
 func TestPlaceStrangleOTOCO_ReturnsUnsupported(t *testing.T) {
 	// The function should return ErrOTOCOUnsupported without touching network.
 	api := NewTradierAPI("k", "acc", false)
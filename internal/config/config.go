@@ -23,6 +23,29 @@ const (
 	defaultRiskMaxPositionLoss = 3.0
 	// defaultMaxDTE represents the default maximum days to expiration before forced exit (21 days)
 	defaultMaxDTE = 21
+	// defaultThirdDownProfitTarget is used when strategy.exit.third_down_profit_target is unset
+	// Fraction of net credit (e.g., 0.25 = close a repaired straddle at 25% profit)
+	defaultThirdDownProfitTarget = 0.25
+	// defaultIVLookbackDays is used when strategy.entry.iv_lookback_days is unset
+	// Trading days of IV history used for IV Rank and IV Percentile (252 = one year)
+	defaultIVLookbackDays = 252
+	// defaultMinIVRReadings is used when strategy.entry.min_ivr_readings is unset
+	// About three months of daily readings, so IV Rank is not judged against a single regime
+	defaultMinIVRReadings = 60
+	// defaultEventWindowHours is used when strategy.events.window_hours is unset
+	// Entries are blocked this many hours before and after a listed event
+	defaultEventWindowHours = 48
+	// defaultEventMinImpact is used when strategy.events.min_impact is unset
+	defaultEventMinImpact = "high"
+	// defaultStopLossCheckInterval is used when schedule.stop_loss_check_interval is unset
+	defaultStopLossCheckInterval = 15 * time.Second
+	// defaultPriceWalkStepTicks is used when execution.step_ticks is unset
+	defaultPriceWalkStepTicks = 1
+	// defaultPriceWalkStepInterval is used when execution.step_interval is unset
+	defaultPriceWalkStepInterval = 20 * time.Second
+	// defaultPriceWalkMaxConcessionTicks is used when execution.max_concession_ticks is unset
+	// Ticks given up from mid before the walk stops and the last price is left working
+	defaultPriceWalkMaxConcessionTicks = 5
 )
 
 // Config represents the complete application configuration.
@@ -34,6 +57,8 @@ type Config struct {
 	Risk        RiskConfig        `yaml:"risk"`
 	Storage     StorageConfig     `yaml:"storage"`
 	Dashboard   DashboardConfig   `yaml:"dashboard"`
+	Execution   ExecutionConfig   `yaml:"execution"`
+	Fees        FeesConfig        `yaml:"fees"`
 }
 
 // EnvironmentConfig defines the environment settings.
@@ -62,6 +87,8 @@ type StrategyConfig struct {
 	Entry                   EntryConfig      `yaml:"entry"`
 	Exit                    ExitConfig       `yaml:"exit"`
 	Adjustments             AdjustmentConfig `yaml:"adjustments"`
+	Events                  EventConfig      `yaml:"events"`
+	Pricing                 PricingConfig    `yaml:"pricing"`
 	AllocationPct           float64          `yaml:"allocation_pct"`
 	EscalateLossPct         float64          `yaml:"escalate_loss_pct"`
 	MaxNewPositionsPerCycle int              `yaml:"max_new_positions_per_cycle"`
@@ -70,26 +97,57 @@ type StrategyConfig struct {
 // EntryConfig defines entry criteria for opening new positions.
 type EntryConfig struct {
 	MinIVPct        float64 `yaml:"min_iv_pct"`         // Minimum SPY ATM IV percentage to enter
+	MinIVR          float64 `yaml:"min_ivr"`            // Minimum IV Rank (0-100) to enter; 0 uses min_iv_pct only
+	IVLookbackDays  int     `yaml:"iv_lookback_days"`   // Trading days of IV history for IV Rank/Percentile
+	MinIVRReadings  int     `yaml:"min_ivr_readings"`   // Stored IV readings needed before IV Rank gates entries
 	DTERange        []int   `yaml:"dte_range"`
 	TargetDTE       int     `yaml:"target_dte"`
 	Delta           float64 `yaml:"delta"` // Delta in percentage points (e.g., 16 = 0.16 fractional)
 	MinCredit       float64 `yaml:"min_credit"`
 	MinVolume       int64   `yaml:"min_volume"`         // Minimum daily volume for liquidity filtering
 	MinOpenInterest int64   `yaml:"min_open_interest"`  // Minimum open interest for liquidity filtering
+	IVRRules        []IVRRule `yaml:"ivr_rules"`        // Optional IV regime table; first match overrides target_dte, dte_range and delta
+}
+
+// IVRRule maps an IV Rank band to the entry DTE and delta used in that regime.
+type IVRRule struct {
+	Name      string  `yaml:"name"`       // Label reported with the chosen rule, e.g. "low_iv"
+	MinIVR    float64 `yaml:"min_ivr"`    // Inclusive lower bound (0-100)
+	MaxIVR    float64 `yaml:"max_ivr"`    // Exclusive upper bound (0-100); 100 includes an IVR of 100
+	TargetDTE int     `yaml:"target_dte"`
+	DTERange  []int   `yaml:"dte_range"`
+	Delta     float64 `yaml:"delta"` // Percentage points, like strategy.entry.delta
 }
 
 // ExitConfig defines exit criteria for closing positions.
 type ExitConfig struct {
-	ProfitTarget float64 `yaml:"profit_target"` // Fraction (e.g., 0.25 = 25%)
-	MaxDTE       int     `yaml:"max_dte"`
-	StopLossPct  float64 `yaml:"stop_loss_pct"`
+	ProfitTarget          float64 `yaml:"profit_target"`            // Fraction (e.g., 0.25 = 25%)
+	ThirdDownProfitTarget float64 `yaml:"third_down_profit_target"` // Fraction used once a position is in Third Down (e.g., 0.25)
+	MaxDTE                int     `yaml:"max_dte"`
+	StopLossPct           float64 `yaml:"stop_loss_pct"`
+	// ProfitTargetOrder places a resting GTC buy-to-close at the profit target once an entry fills
+	ProfitTargetOrder bool `yaml:"profit_target_order"`
 }
 
 // AdjustmentConfig defines parameters for position adjustments.
 type AdjustmentConfig struct {
 	Enabled             bool    `yaml:"enabled"`
-	SecondDownThreshold float64 `yaml:"second_down_threshold"` // Percent of position credit (e.g., 1.5 = 1.5% of credit received)
-	EnableAdjustmentStub bool   `yaml:"enable_adjustment_stub"` // Feature flag to enable adjustment stub logging
+	SecondDownThreshold float64 `yaml:"second_down_threshold"` // Points between spot and a short strike that trigger Second Down (e.g., 10)
+	EnableAdjustmentStub bool   `yaml:"enable_adjustment_stub"` // Deprecated: no-op, kept so existing configs still load
+}
+
+// EventConfig defines the economic event filter for new entries.
+type EventConfig struct {
+	CalendarFile string  `yaml:"calendar_file"` // YAML/JSON event calendar; empty disables the filter
+	WindowHours  float64 `yaml:"window_hours"`  // Hours before and after an event during which entries are blocked
+	MinImpact    string  `yaml:"min_impact"`    // Lowest impact that blocks entries: low | medium | high
+}
+
+// PricingConfig defines the Black-Scholes inputs used when the option chain lacks greeks.
+// Both are annualised decimals; unset means 0.
+type PricingConfig struct {
+	RiskFreeRate  float64 `yaml:"risk_free_rate"` // e.g., 0.045 = 4.5%
+	DividendYield float64 `yaml:"dividend_yield"` // e.g., 0.013 = 1.3% for SPY
 }
 
 // RiskConfig defines risk management parameters.
@@ -98,6 +156,8 @@ type RiskConfig struct {
 	MaxPositions    int     `yaml:"max_positions"`     // Maximum number of concurrent positions
 	MaxDailyLoss    float64 `yaml:"max_daily_loss"`    // Percent of account equity (e.g., 5.0 = 5% of account value)
 	MaxPositionLoss float64 `yaml:"max_position_loss"` // Percent of account equity (e.g., 3.0 = 3% of account value)
+	// FlattenOnDailyLoss closes all open positions when max_daily_loss is breached
+	FlattenOnDailyLoss bool `yaml:"flatten_on_daily_loss"`
 }
 
 // ScheduleConfig defines trading schedule and market hours.
@@ -107,18 +167,43 @@ type ScheduleConfig struct {
 	TradingStart        string `yaml:"trading_start"` // "HH:MM"
 	TradingEnd          string `yaml:"trading_end"`   // "HH:MM"
 	AfterHoursCheck     bool   `yaml:"after_hours_check"`
+	// StopLossCheckInterval is how often the position monitor re-prices open positions for stop-loss exits
+	StopLossCheckInterval string `yaml:"stop_loss_check_interval"`
 }
 
 // StorageConfig defines storage settings for position data.
 type StorageConfig struct {
 	Path string `yaml:"path"`
+	// Backend is "json" or "sqlite"; empty picks SQLite for .db, .sqlite and .sqlite3 paths and JSON otherwise
+	Backend string `yaml:"backend"`
+	// JournalPath is an append-only JSON Lines log of state transitions and order events; empty disables it
+	JournalPath string `yaml:"journal_path"`
+}
+
+// ExecutionConfig controls how limit orders are worked toward a fill.
+type ExecutionConfig struct {
+	// PriceWalk starts entry and non-emergency exit limits at mid and steps them toward the natural price
+	PriceWalk          bool   `yaml:"price_walk"`
+	StepTicks          int    `yaml:"step_ticks"`           // Ticks conceded per step
+	StepInterval       string `yaml:"step_interval"`        // Time an order rests before each step, e.g. "20s"
+	MaxConcessionTicks int    `yaml:"max_concession_ticks"` // Total ticks conceded from mid; the natural price is never crossed
+}
+
+// FeesConfig is the broker's commission and fee schedule, charged on every filled order.
+type FeesConfig struct {
+	PerContract float64 `yaml:"per_contract"`  // Commission per option contract, each leg counted
+	PerLeg      float64 `yaml:"per_leg"`       // Regulatory and exchange fees per leg of an order
+	MinPerOrder float64 `yaml:"min_per_order"` // Least an order is charged
 }
 
 // DashboardConfig defines web dashboard settings.
 type DashboardConfig struct {
 	Enabled   bool   `yaml:"enabled"`    // Enable web dashboard
 	Port      int    `yaml:"port"`       // HTTP server port
-	AuthToken string `yaml:"auth_token"` // Optional authentication token
+	AuthToken string `yaml:"auth_token"` // Optional authentication token; required for control actions
+	// AuditLogPath is a JSON Lines file recording control actions (close, pause, resume,
+	// reconcile). Actions are always logged; the file is optional.
+	AuditLogPath string `yaml:"audit_log_path"`
 }
 
 // Load reads and parses the configuration file from the specified path.
@@ -225,6 +310,35 @@ func (c *Config) Validate() error {
 	if c.Strategy.Entry.MinIVPct <= 0 || c.Strategy.Entry.MinIVPct > 100 {
 		return fmt.Errorf("strategy.entry.min_iv_pct must be between 0 and 100")
 	}
+	if c.Strategy.Entry.MinIVR < 0 || c.Strategy.Entry.MinIVR > 100 {
+		return fmt.Errorf("strategy.entry.min_ivr must be between 0 and 100")
+	}
+	// Unset (0) is defaulted by Normalize
+	if c.Strategy.Entry.IVLookbackDays < 0 {
+		return fmt.Errorf("strategy.entry.iv_lookback_days must be >= 0")
+	}
+	// Unset (0) is defaulted by Normalize; more readings than the lookback holds never arrive
+	lookback := c.Strategy.Entry.IVLookbackDays
+	if lookback == 0 {
+		lookback = defaultIVLookbackDays
+	}
+	if c.Strategy.Entry.MinIVRReadings < 0 || c.Strategy.Entry.MinIVRReadings > lookback {
+		return fmt.Errorf("strategy.entry.min_ivr_readings must be between 0 and iv_lookback_days (%d)", lookback)
+	}
+	if c.Strategy.Events.WindowHours < 0 {
+		return fmt.Errorf("strategy.events.window_hours must be >= 0")
+	}
+	switch strings.ToLower(strings.TrimSpace(c.Strategy.Events.MinImpact)) {
+	case "", "low", "medium", "high":
+	default:
+		return fmt.Errorf("strategy.events.min_impact must be one of: low, medium, high")
+	}
+	if c.Strategy.Pricing.RiskFreeRate < 0 || c.Strategy.Pricing.RiskFreeRate >= 1 {
+		return fmt.Errorf("strategy.pricing.risk_free_rate must be in [0,1)")
+	}
+	if c.Strategy.Pricing.DividendYield < 0 || c.Strategy.Pricing.DividendYield >= 1 {
+		return fmt.Errorf("strategy.pricing.dividend_yield must be in [0,1)")
+	}
 	if c.Strategy.Entry.Delta <= 0 || c.Strategy.Entry.Delta > 50 {
 		return fmt.Errorf("strategy.entry.delta must be between 0 and 50")
 	}
@@ -254,27 +368,35 @@ func (c *Config) Validate() error {
 	if c.Strategy.Entry.MinOpenInterest < 0 {
 		return fmt.Errorf("strategy.entry.min_open_interest must be >= 0")
 	}
+	for i, rule := range c.Strategy.Entry.IVRRules {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("strategy.entry.ivr_rules[%d]: %w", i, err)
+		}
+	}
 
 	// Exit configuration validation
 	if c.Strategy.Exit.ProfitTarget <= 0 || c.Strategy.Exit.ProfitTarget >= 1 {
 		return fmt.Errorf("strategy.exit.profit_target must be in (0,1)")
 	}
+	// third_down_profit_target may be left unset (0) and is defaulted by Normalize
+	if c.Strategy.Exit.ThirdDownProfitTarget < 0 || c.Strategy.Exit.ThirdDownProfitTarget >= 1 {
+		return fmt.Errorf("strategy.exit.third_down_profit_target must be in [0,1)")
+	}
 	if c.Strategy.Exit.StopLossPct <= 0 {
 		return fmt.Errorf("strategy.exit.stop_loss_pct must be > 0")
 	}
 	if c.Strategy.Exit.MaxDTE <= 0 {
 		return fmt.Errorf("strategy.exit.max_dte must be > 0")
 	}
+	if c.Strategy.Exit.ProfitTargetOrder && c.Broker.UseOTOCO {
+		return fmt.Errorf("strategy.exit.profit_target_order cannot be combined with broker.use_otoco, which presets its own exit")
+	}
 
-	// Adjustment thresholds must be sensible relative to stop loss
+	// Adjustment thresholds are measured in underlying points from a short strike
 	if c.Strategy.Adjustments.Enabled {
 		if c.Strategy.Adjustments.SecondDownThreshold <= 0 {
 			return fmt.Errorf("strategy.adjustments.second_down_threshold must be > 0 when adjustments.enabled is true")
 		}
-		if c.Strategy.Adjustments.SecondDownThreshold >= c.Strategy.Exit.StopLossPct {
-			return fmt.Errorf("strategy.adjustments.second_down_threshold (%.2f) must be < strategy.exit.stop_loss_pct (%.2f)",
-				c.Strategy.Adjustments.SecondDownThreshold, c.Strategy.Exit.StopLossPct)
-		}
 	}
 
 	// Validate loss percentage constraints
@@ -293,6 +415,9 @@ func (c *Config) Validate() error {
 	if c.Risk.MaxDailyLoss <= 0 {
 		return fmt.Errorf("risk.max_daily_loss must be > 0")
 	}
+	if c.Risk.MaxDailyLoss > 100 {
+		return fmt.Errorf("risk.max_daily_loss must be <= 100 (percent of account equity), got %.2f", c.Risk.MaxDailyLoss)
+	}
 	if c.Risk.MaxPositionLoss <= 0 {
 		return fmt.Errorf("risk.max_position_loss must be > 0")
 	}
@@ -307,6 +432,13 @@ func (c *Config) Validate() error {
 	} else if duration <= 0 {
 		return fmt.Errorf("schedule.market_check_interval must be > 0")
 	}
+	if v := strings.TrimSpace(c.Schedule.StopLossCheckInterval); v != "" {
+		if duration, err := time.ParseDuration(v); err != nil {
+			return fmt.Errorf("schedule.stop_loss_check_interval invalid: %w", err)
+		} else if duration < time.Second {
+			return fmt.Errorf("schedule.stop_loss_check_interval must be at least 1s")
+		}
+	}
 	loc, err := c.resolveLocation()
 	if err != nil {
 		return fmt.Errorf("timezone resolution failed: %w", err)
@@ -321,6 +453,31 @@ func (c *Config) Validate() error {
 	if strings.TrimSpace(c.Storage.Path) == "" {
 		return fmt.Errorf("storage.path is required")
 	}
+	switch strings.ToLower(strings.TrimSpace(c.Storage.Backend)) {
+	case "", "json", "sqlite":
+	default:
+		return fmt.Errorf("storage.backend must be json or sqlite, got %q", c.Storage.Backend)
+	}
+
+	// Execution validation; zero values are defaulted by Normalize
+	if c.Execution.StepTicks < 0 {
+		return fmt.Errorf("execution.step_ticks must be >= 0")
+	}
+	if c.Execution.MaxConcessionTicks < 0 {
+		return fmt.Errorf("execution.max_concession_ticks must be >= 0")
+	}
+	if v := strings.TrimSpace(c.Execution.StepInterval); v != "" {
+		if duration, err := time.ParseDuration(v); err != nil {
+			return fmt.Errorf("execution.step_interval invalid: %w", err)
+		} else if duration < time.Second {
+			return fmt.Errorf("execution.step_interval must be at least 1s")
+		}
+	}
+
+	// Fee schedule validation
+	if c.Fees.PerContract < 0 || c.Fees.PerLeg < 0 || c.Fees.MinPerOrder < 0 {
+		return fmt.Errorf("fees.per_contract, fees.per_leg and fees.min_per_order must be >= 0")
+	}
 
 	// Dashboard validation
 	if c.Dashboard.Enabled {
@@ -349,6 +506,24 @@ func (c *Config) GetCheckInterval() time.Duration {
 	return d
 }
 
+// GetStopLossCheckInterval returns how often the position monitor checks stop-loss thresholds.
+func (c *Config) GetStopLossCheckInterval() time.Duration {
+	d, err := time.ParseDuration(strings.TrimSpace(c.Schedule.StopLossCheckInterval))
+	if err != nil || d <= 0 {
+		return defaultStopLossCheckInterval
+	}
+	return d
+}
+
+// GetPriceWalkStepInterval returns how long a walked order rests at each price.
+func (c *Config) GetPriceWalkStepInterval() time.Duration {
+	d, err := time.ParseDuration(strings.TrimSpace(c.Execution.StepInterval))
+	if err != nil || d <= 0 {
+		return defaultPriceWalkStepInterval
+	}
+	return d
+}
+
 // IsWithinTradingHours checks if the given time falls within configured trading hours.
 func (c *Config) IsWithinTradingHours(now time.Time) (bool, error) {
 	loc, err := c.resolveLocation()
@@ -389,6 +564,9 @@ func (c *Config) Normalize() {
 	if strings.TrimSpace(c.Schedule.MarketCheckInterval) == "" {
 		c.Schedule.MarketCheckInterval = "15m"
 	}
+	if strings.TrimSpace(c.Schedule.StopLossCheckInterval) == "" {
+		c.Schedule.StopLossCheckInterval = defaultStopLossCheckInterval.String()
+	}
 	if strings.TrimSpace(c.Environment.Mode) == "" {
 		c.Environment.Mode = "paper"
 	}
@@ -404,6 +582,23 @@ func (c *Config) Normalize() {
 	if c.Risk.MaxPositionLoss == 0 {
 		c.Risk.MaxPositionLoss = defaultRiskMaxPositionLoss
 	}
+	if c.Strategy.Exit.ThirdDownProfitTarget == 0 {
+		c.Strategy.Exit.ThirdDownProfitTarget = defaultThirdDownProfitTarget
+	}
+	if c.Strategy.Entry.IVLookbackDays == 0 {
+		c.Strategy.Entry.IVLookbackDays = defaultIVLookbackDays
+	}
+	if c.Strategy.Entry.MinIVRReadings == 0 {
+		c.Strategy.Entry.MinIVRReadings = defaultMinIVRReadings
+	}
+	if c.Strategy.Events.WindowHours == 0 {
+		c.Strategy.Events.WindowHours = defaultEventWindowHours
+	}
+	c.Strategy.Events.MinImpact = strings.ToLower(strings.TrimSpace(c.Strategy.Events.MinImpact))
+	c.Storage.Backend = strings.ToLower(strings.TrimSpace(c.Storage.Backend))
+	if c.Strategy.Events.MinImpact == "" {
+		c.Strategy.Events.MinImpact = defaultEventMinImpact
+	}
 	if c.Strategy.Exit.StopLossPct == 0 {
 		// StopLossPct uses credit units and is not constrained by MaxPositionLoss (equity units)
 		c.Strategy.Exit.StopLossPct = defaultStopLossPct
@@ -417,6 +612,15 @@ func (c *Config) Normalize() {
 	if c.Broker.PhantomThreshold == 0 {
 		c.Broker.PhantomThreshold = 10 * time.Minute // Default to 10 minutes
 	}
+	if c.Execution.StepTicks == 0 {
+		c.Execution.StepTicks = defaultPriceWalkStepTicks
+	}
+	if strings.TrimSpace(c.Execution.StepInterval) == "" {
+		c.Execution.StepInterval = defaultPriceWalkStepInterval.String()
+	}
+	if c.Execution.MaxConcessionTicks == 0 {
+		c.Execution.MaxConcessionTicks = defaultPriceWalkMaxConcessionTicks
+	}
 }
 
 // GetMaxDTE returns the configured MaxDTE value, falling back to defaultMaxDTE if unset
@@ -426,3 +630,20 @@ func (c *Config) GetMaxDTE() int {
 	}
 	return c.Strategy.Exit.MaxDTE
 }
+
+// validate checks a single IV Rank rule
+func (r IVRRule) validate() error {
+	if r.MinIVR < 0 || r.MaxIVR > 100 || r.MinIVR >= r.MaxIVR {
+		return fmt.Errorf("min_ivr and max_ivr must satisfy 0 <= min_ivr < max_ivr <= 100")
+	}
+	if len(r.DTERange) != 2 || r.DTERange[0] <= 0 || r.DTERange[0] > r.DTERange[1] {
+		return fmt.Errorf("dte_range must be [min,max] with positive values and min <= max")
+	}
+	if r.TargetDTE < r.DTERange[0] || r.TargetDTE > r.DTERange[1] {
+		return fmt.Errorf("target_dte (%d) must be within dte_range [%d,%d]", r.TargetDTE, r.DTERange[0], r.DTERange[1])
+	}
+	if r.Delta <= 0 || r.Delta > 50 {
+		return fmt.Errorf("delta must be between 0 and 50")
+	}
+	return nil
+}
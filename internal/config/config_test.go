@@ -61,7 +61,7 @@ func TestValidate_LossPercentageConstraints(t *testing.T) {
 		},
 		Risk: RiskConfig{
 			MaxContracts:    1,
-			MaxDailyLoss:    500,
+			MaxDailyLoss:    2.0,
 			MaxPositionLoss: 2.0,
 		},
 		Schedule: ScheduleConfig{
@@ -129,6 +129,19 @@ func TestValidate_LossPercentageConstraints(t *testing.T) {
 		}
 	})
 
+	t.Run("max_daily_loss above 100 percent - invalid", func(t *testing.T) {
+		config := *baseConfig
+		config.Risk.MaxDailyLoss = 500
+
+		err := config.Validate()
+		if err == nil {
+			t.Fatal("Expected error when max_daily_loss exceeds 100")
+		}
+		if !strings.Contains(err.Error(), "risk.max_daily_loss must be <= 100") {
+			t.Errorf("Expected max_daily_loss bound error, got: %v", err)
+		}
+	})
+
 	t.Run("stop_loss_pct equal to max_position_loss - valid", func(t *testing.T) {
 		config := *baseConfig
 		config.Strategy.EscalateLossPct = 1.5
@@ -192,7 +205,7 @@ strategy:
   escalate_loss_pct: 2.0
   entry: { min_ivr: 30, target_dte: 45, dte_range: [40,50], delta: 16, min_credit: 2.0 }
   exit: { profit_target: 0.5, max_dte: 21, stop_loss_pct: 2.5 }
-risk: { max_contracts: 1, max_daily_loss: 500, max_position_loss: 2.0 }
+risk: { max_contracts: 1, max_daily_loss: 2.0, max_position_loss: 2.0 }
 schedule: { market_check_interval: "15m", trading_start: "09:45", trading_end: "15:45", after_hours_check: false }
 storage: { path: "positions.json" }
 extra_unknown_key: true
@@ -356,7 +369,7 @@ func TestValidate_StoragePath(t *testing.T) {
 		},
 		Risk: RiskConfig{
 			MaxContracts:    1,
-			MaxDailyLoss:    500,
+			MaxDailyLoss:    2.0,
 			MaxPositionLoss: 3.0,
 		},
 		Schedule: ScheduleConfig{
@@ -488,3 +501,276 @@ func TestNormalizeExitConfig_StopLossDefault(t *testing.T) {
 		}
 	})
 }
+
+func TestThirdDownProfitTarget(t *testing.T) {
+	t.Run("defaulted when unset", func(t *testing.T) {
+		config := &Config{}
+		config.Normalize()
+		if config.Strategy.Exit.ThirdDownProfitTarget != 0.25 {
+			t.Errorf("Expected ThirdDownProfitTarget to default to 0.25, got %.2f", config.Strategy.Exit.ThirdDownProfitTarget)
+		}
+	})
+
+	t.Run("explicit value remains unchanged", func(t *testing.T) {
+		config := &Config{Strategy: StrategyConfig{Exit: ExitConfig{ThirdDownProfitTarget: 0.30}}}
+		config.Normalize()
+		if config.Strategy.Exit.ThirdDownProfitTarget != 0.30 {
+			t.Errorf("Expected ThirdDownProfitTarget to remain 0.30, got %.2f", config.Strategy.Exit.ThirdDownProfitTarget)
+		}
+	})
+
+	t.Run("out of range is invalid", func(t *testing.T) {
+		config, err := Load(filepath.Join("..", "..", "config.yaml.example"))
+		if err != nil {
+			t.Fatalf("Failed to load example config: %v", err)
+		}
+		config.Strategy.Exit.ThirdDownProfitTarget = 1.5
+		err = config.Validate()
+		if err == nil || !strings.Contains(err.Error(), "third_down_profit_target") {
+			t.Errorf("Expected third_down_profit_target error, got: %v", err)
+		}
+	})
+}
+
+func TestIVRankEntryConfig(t *testing.T) {
+	t.Run("lookback defaulted when unset", func(t *testing.T) {
+		config := &Config{}
+		config.Normalize()
+		if config.Strategy.Entry.IVLookbackDays != 252 {
+			t.Errorf("Expected IVLookbackDays to default to 252, got %d", config.Strategy.Entry.IVLookbackDays)
+		}
+	})
+
+	t.Run("example config loads with min_ivr disabled", func(t *testing.T) {
+		config, err := Load(filepath.Join("..", "..", "config.yaml.example"))
+		if err != nil {
+			t.Fatalf("Failed to load example config: %v", err)
+		}
+		if config.Strategy.Entry.MinIVR != 0 || config.Strategy.Entry.IVLookbackDays != 252 {
+			t.Errorf("Expected min_ivr 0 and lookback 252, got %.1f and %d",
+				config.Strategy.Entry.MinIVR, config.Strategy.Entry.IVLookbackDays)
+		}
+	})
+
+	t.Run("min_ivr_readings defaulted when unset", func(t *testing.T) {
+		config := &Config{}
+		config.Normalize()
+		if config.Strategy.Entry.MinIVRReadings != 60 {
+			t.Errorf("Expected MinIVRReadings to default to 60, got %d", config.Strategy.Entry.MinIVRReadings)
+		}
+	})
+
+	t.Run("min_ivr_readings beyond the lookback is invalid", func(t *testing.T) {
+		config, err := Load(filepath.Join("..", "..", "config.yaml.example"))
+		if err != nil {
+			t.Fatalf("Failed to load example config: %v", err)
+		}
+		config.Strategy.Entry.IVLookbackDays = 40
+		config.Strategy.Entry.MinIVRReadings = 60
+		err = config.Validate()
+		if err == nil || !strings.Contains(err.Error(), "min_ivr_readings") {
+			t.Errorf("Expected min_ivr_readings error, got: %v", err)
+		}
+
+		config.Strategy.Entry.MinIVRReadings = -1
+		err = config.Validate()
+		if err == nil || !strings.Contains(err.Error(), "min_ivr_readings") {
+			t.Errorf("Expected min_ivr_readings error, got: %v", err)
+		}
+	})
+
+	t.Run("out of range min_ivr is invalid", func(t *testing.T) {
+		config, err := Load(filepath.Join("..", "..", "config.yaml.example"))
+		if err != nil {
+			t.Fatalf("Failed to load example config: %v", err)
+		}
+		config.Strategy.Entry.MinIVR = 150
+		err = config.Validate()
+		if err == nil || !strings.Contains(err.Error(), "min_ivr") {
+			t.Errorf("Expected min_ivr error, got: %v", err)
+		}
+	})
+}
+
+func TestEventConfig(t *testing.T) {
+	t.Run("defaults when unset", func(t *testing.T) {
+		config := &Config{}
+		config.Normalize()
+		if config.Strategy.Events.WindowHours != 48 || config.Strategy.Events.MinImpact != "high" {
+			t.Errorf("Expected 48h window and high impact, got %.0f and %q",
+				config.Strategy.Events.WindowHours, config.Strategy.Events.MinImpact)
+		}
+	})
+
+	t.Run("invalid min_impact", func(t *testing.T) {
+		config, err := Load(filepath.Join("..", "..", "config.yaml.example"))
+		if err != nil {
+			t.Fatalf("Failed to load example config: %v", err)
+		}
+		config.Strategy.Events.MinImpact = "extreme"
+		err = config.Validate()
+		if err == nil || !strings.Contains(err.Error(), "min_impact") {
+			t.Errorf("Expected min_impact error, got: %v", err)
+		}
+	})
+}
+
+func TestIVRRulesConfig(t *testing.T) {
+	valid := IVRRule{Name: "low_iv", MinIVR: 0, MaxIVR: 30, TargetDTE: 60, DTERange: []int{55, 65}, Delta: 16}
+
+	tests := []struct {
+		name    string
+		modify  func(r *IVRRule)
+		wantErr string
+	}{
+		{"valid rule", func(r *IVRRule) {}, ""},
+		{"inverted band", func(r *IVRRule) { r.MinIVR = 40 }, "min_ivr"},
+		{"band above 100", func(r *IVRRule) { r.MaxIVR = 120 }, "min_ivr"},
+		{"target outside range", func(r *IVRRule) { r.TargetDTE = 45 }, "target_dte"},
+		{"missing range", func(r *IVRRule) { r.DTERange = nil }, "dte_range"},
+		{"delta too large", func(r *IVRRule) { r.Delta = 60 }, "delta"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := Load(filepath.Join("..", "..", "config.yaml.example"))
+			if err != nil {
+				t.Fatalf("Failed to load example config: %v", err)
+			}
+			rule := valid
+			rule.DTERange = append([]int(nil), valid.DTERange...)
+			tt.modify(&rule)
+			config.Strategy.Entry.IVRRules = []IVRRule{rule}
+
+			err = config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected valid rule, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "ivr_rules[0]") || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected ivr_rules[0] %s error, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPricingConfig(t *testing.T) {
+	config, err := Load(filepath.Join("..", "..", "config.yaml.example"))
+	if err != nil {
+		t.Fatalf("Failed to load example config: %v", err)
+	}
+	if config.Strategy.Pricing.RiskFreeRate != 0.045 || config.Strategy.Pricing.DividendYield != 0.013 {
+		t.Errorf("Expected example rate 0.045 and yield 0.013, got %.3f and %.3f",
+			config.Strategy.Pricing.RiskFreeRate, config.Strategy.Pricing.DividendYield)
+	}
+
+	// Percentages instead of decimals are rejected
+	config.Strategy.Pricing.RiskFreeRate = 4.5
+	err = config.Validate()
+	if err == nil || !strings.Contains(err.Error(), "risk_free_rate") {
+		t.Errorf("Expected risk_free_rate error, got: %v", err)
+	}
+}
+
+func TestStopLossCheckInterval(t *testing.T) {
+	config, err := Load(filepath.Join("..", "..", "config.yaml.example"))
+	if err != nil {
+		t.Fatalf("Failed to load example config: %v", err)
+	}
+	if got := config.GetStopLossCheckInterval(); got != 15*time.Second {
+		t.Errorf("Expected 15s stop-loss check interval, got %v", got)
+	}
+
+	// Unset falls back to the default
+	config.Schedule.StopLossCheckInterval = ""
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected empty interval to validate, got: %v", err)
+	}
+	if got := config.GetStopLossCheckInterval(); got != defaultStopLossCheckInterval {
+		t.Errorf("Expected default interval, got %v", got)
+	}
+
+	config.Schedule.StopLossCheckInterval = "500ms"
+	err = config.Validate()
+	if err == nil || !strings.Contains(err.Error(), "stop_loss_check_interval") {
+		t.Errorf("Expected stop_loss_check_interval error, got: %v", err)
+	}
+}
+
+func TestProfitTargetOrderConfig(t *testing.T) {
+	config, err := Load(filepath.Join("..", "..", "config.yaml.example"))
+	if err != nil {
+		t.Fatalf("Failed to load example config: %v", err)
+	}
+	if !config.Strategy.Exit.ProfitTargetOrder {
+		t.Error("Expected example config to enable profit_target_order")
+	}
+
+	// OTOCO already attaches a profit target, so a second resting order is rejected
+	config.Broker.UseOTOCO = true
+	err = config.Validate()
+	if err == nil || !strings.Contains(err.Error(), "profit_target_order") {
+		t.Errorf("Expected profit_target_order error, got: %v", err)
+	}
+}
+
+func TestExecutionConfig(t *testing.T) {
+	config, err := Load(filepath.Join("..", "..", "config.yaml.example"))
+	if err != nil {
+		t.Fatalf("Failed to load example config: %v", err)
+	}
+	if config.Execution.StepTicks != 1 || config.Execution.MaxConcessionTicks != 5 {
+		t.Errorf("Unexpected execution ticks: step=%d max=%d",
+			config.Execution.StepTicks, config.Execution.MaxConcessionTicks)
+	}
+	if got := config.GetPriceWalkStepInterval(); got != 20*time.Second {
+		t.Errorf("Expected 20s step interval, got %v", got)
+	}
+
+	config.Execution = ExecutionConfig{}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected unset execution config to validate, got: %v", err)
+	}
+	config.Normalize()
+	if config.Execution.StepTicks != defaultPriceWalkStepTicks ||
+		config.Execution.MaxConcessionTicks != defaultPriceWalkMaxConcessionTicks ||
+		config.GetPriceWalkStepInterval() != defaultPriceWalkStepInterval {
+		t.Errorf("Expected execution defaults, got %+v", config.Execution)
+	}
+
+	config.Execution.StepInterval = "250ms"
+	err = config.Validate()
+	if err == nil || !strings.Contains(err.Error(), "step_interval") {
+		t.Errorf("Expected step_interval error, got: %v", err)
+	}
+
+	config.Execution.StepInterval = "20s"
+	config.Execution.MaxConcessionTicks = -1
+	err = config.Validate()
+	if err == nil || !strings.Contains(err.Error(), "max_concession_ticks") {
+		t.Errorf("Expected max_concession_ticks error, got: %v", err)
+	}
+}
+
+func TestFeesConfig(t *testing.T) {
+	config, err := Load(filepath.Join("..", "..", "config.yaml.example"))
+	if err != nil {
+		t.Fatalf("Failed to load example config: %v", err)
+	}
+	if config.Fees.PerContract != 0.35 {
+		t.Errorf("Expected $0.35 per contract, got %.2f", config.Fees.PerContract)
+	}
+
+	config.Fees = FeesConfig{}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected a fee-free schedule to validate, got: %v", err)
+	}
+
+	config.Fees.MinPerOrder = -1
+	err = config.Validate()
+	if err == nil || !strings.Contains(err.Error(), "fees.") {
+		t.Errorf("Expected fees error, got: %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AuditStatus is the stage of a control action an audit record describes
+type AuditStatus string
+
+const (
+	// AuditRequested is written when a confirmed command is about to be handed to the bot
+	AuditRequested AuditStatus = "requested"
+	// AuditQueued is written when the command is on the bot's queue
+	AuditQueued AuditStatus = "queued"
+	// AuditApplied is written when the bot carried out the command
+	AuditApplied AuditStatus = "applied"
+	// AuditFailed is written when the bot could not carry out the command
+	AuditFailed AuditStatus = "failed"
+	// AuditRejected is written when the dashboard refused the request
+	AuditRejected AuditStatus = "rejected"
+)
+
+// AuditRecord is one line of the control audit log
+type AuditRecord struct {
+	Time        time.Time   `json:"time"`
+	Action      CommandKind `json:"action"`
+	Status      AuditStatus `json:"status"`
+	PositionID  string      `json:"position_id,omitempty"`
+	Reason      string      `json:"reason,omitempty"`
+	RequestedBy string      `json:"requested_by,omitempty"`
+	RequestID   string      `json:"request_id,omitempty"`
+	Message     string      `json:"message,omitempty"`
+}
+
+// AuditLog appends control actions to a JSON Lines file. A nil *AuditLog discards records.
+type AuditLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenAuditLog opens the audit log at path for appending, creating it if needed
+func OpenAuditLog(path string) (*AuditLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) // #nosec G304 - path comes from config
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &AuditLog{file: f}, nil
+}
+
+// Write appends rec and syncs it, so the record outlives a crash straight after the action
+func (a *AuditLog) Write(rec AuditRecord) error {
+	if a == nil {
+		return nil
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return a.file.Sync()
+}
+
+// Close closes the audit log file
+func (a *AuditLog) Close() error {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.file.Close()
+}
+
+// recordAudit logs a control action and appends it to the audit log
+func (s *Server) recordAudit(cmd Command, status AuditStatus, message string) {
+	rec := AuditRecord{
+		Time:        time.Now().UTC(),
+		Action:      cmd.Kind,
+		Status:      status,
+		PositionID:  cmd.PositionID,
+		Reason:      cmd.Reason,
+		RequestedBy: cmd.RequestedBy,
+		RequestID:   cmd.RequestID,
+		Message:     message,
+	}
+
+	entry := s.logger.WithFields(logrus.Fields{
+		"action":       rec.Action,
+		"status":       rec.Status,
+		"position_id":  rec.PositionID,
+		"reason":       rec.Reason,
+		"requested_by": rec.RequestedBy,
+		"req_id":       rec.RequestID,
+	})
+	switch status {
+	case AuditFailed, AuditRejected:
+		entry.Warnf("Dashboard control %s: %s", status, message)
+	default:
+		entry.Infof("Dashboard control %s: %s", status, message)
+	}
+
+	if err := s.auditLog.Write(rec); err != nil {
+		s.logger.WithError(err).Error("Failed to write dashboard audit record")
+	}
+}
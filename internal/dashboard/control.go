@@ -0,0 +1,240 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// CommandKind names an operator action the dashboard can ask the bot to carry out
+type CommandKind string
+
+const (
+	// CommandClosePosition closes one position with a manual exit reason
+	CommandClosePosition CommandKind = "close_position"
+	// CommandPauseEntries stops the bot opening new positions until entries are resumed
+	CommandPauseEntries CommandKind = "pause_entries"
+	// CommandResumeEntries lifts an entry pause
+	CommandResumeEntries CommandKind = "resume_entries"
+	// CommandReconcile reconciles stored positions with the broker now rather than next cycle
+	CommandReconcile CommandKind = "reconcile"
+)
+
+const (
+	// commandAckTimeout is how long a request waits for the bot before answering that the
+	// command is queued; the bot applies commands between trading cycles
+	commandAckTimeout = 10 * time.Second
+	// commandResultWait bounds how long a queued command's outcome is awaited for the audit log
+	commandResultWait = 10 * time.Minute
+	// maxReasonLength bounds the operator's free-text reason
+	maxReasonLength = 200
+)
+
+// Command is an operator action sent from the dashboard to the bot's command channel.
+// The bot reports the outcome with Respond.
+type Command struct {
+	Kind        CommandKind
+	PositionID  string // Set for CommandClosePosition
+	Reason      string // Operator's reason, if given
+	RequestedBy string // Client address the request came from
+	RequestID   string
+	Result      chan<- CommandResult // Buffered; nil when nobody waits for the outcome
+}
+
+// CommandResult is the bot's outcome for a command
+type CommandResult struct {
+	Message string
+	Err     error
+}
+
+// Respond reports the command's outcome without blocking the bot
+func (c Command) Respond(message string, err error) {
+	if c.Result == nil {
+		return
+	}
+	select {
+	case c.Result <- CommandResult{Message: message, Err: err}:
+	default:
+	}
+}
+
+// controlResponse is the body returned for a control request
+type controlResponse struct {
+	OK      bool   `json:"ok"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// controlsEnabled reports whether control actions are served; they need both a bot to
+// apply them and an auth token to protect them
+func (s *Server) controlsEnabled() bool {
+	return s.commands != nil && s.authToken != ""
+}
+
+func (s *Server) handleClosePosition(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	cmd := s.newCommand(w, r, CommandClosePosition)
+	cmd.PositionID = id
+	if !s.confirmCommand(w, r, cmd) {
+		return
+	}
+	if _, found := s.storage.GetPositionByID(id); !found {
+		s.rejectCommand(w, r, cmd, http.StatusNotFound, fmt.Sprintf("position %s not found", id))
+		return
+	}
+	s.sendCommand(w, r, cmd)
+}
+
+func (s *Server) handlePauseEntries(w http.ResponseWriter, r *http.Request) {
+	cmd := s.newCommand(w, r, CommandPauseEntries)
+	if !s.confirmCommand(w, r, cmd) {
+		return
+	}
+	s.sendCommand(w, r, cmd)
+}
+
+func (s *Server) handleResumeEntries(w http.ResponseWriter, r *http.Request) {
+	cmd := s.newCommand(w, r, CommandResumeEntries)
+	if !s.confirmCommand(w, r, cmd) {
+		return
+	}
+	s.sendCommand(w, r, cmd)
+}
+
+func (s *Server) handleReconcile(w http.ResponseWriter, r *http.Request) {
+	cmd := s.newCommand(w, r, CommandReconcile)
+	if !s.confirmCommand(w, r, cmd) {
+		return
+	}
+	s.sendCommand(w, r, cmd)
+}
+
+// newCommand builds a command from the request. The reason comes from the form, or from
+// the HX-Prompt header when the dashboard asked for it in a prompt.
+func (s *Server) newCommand(w http.ResponseWriter, r *http.Request, kind CommandKind) Command {
+	r.Body = http.MaxBytesReader(w, r.Body, 4096)
+	_ = r.ParseForm()
+
+	reason := strings.TrimSpace(r.PostFormValue("reason"))
+	if reason == "" {
+		reason = strings.TrimSpace(r.Header.Get("HX-Prompt"))
+	}
+	if len(reason) > maxReasonLength {
+		reason = reason[:maxReasonLength]
+	}
+	return Command{
+		Kind:        kind,
+		Reason:      reason,
+		RequestedBy: r.RemoteAddr,
+		RequestID:   middleware.GetReqID(r.Context()),
+	}
+}
+
+// confirmCommand rejects requests that do not name the action in a confirm field, so a
+// stray or replayed POST cannot trade
+func (s *Server) confirmCommand(w http.ResponseWriter, r *http.Request, cmd Command) bool {
+	if r.PostFormValue("confirm") == string(cmd.Kind) {
+		return true
+	}
+	s.rejectCommand(w, r, cmd, http.StatusBadRequest,
+		fmt.Sprintf("confirmation required: send confirm=%s", cmd.Kind))
+	return false
+}
+
+// sendCommand hands cmd to the bot and answers with its outcome, or with 202 when the bot
+// is busy with a trading cycle and will apply the command afterwards
+func (s *Server) sendCommand(w http.ResponseWriter, r *http.Request, cmd Command) {
+	result := make(chan CommandResult, 1)
+	cmd.Result = result
+
+	s.recordAudit(cmd, AuditRequested, "")
+	select {
+	case s.commands <- cmd:
+		s.recordAudit(cmd, AuditQueued, "")
+	default:
+		s.rejectCommand(w, r, cmd, http.StatusServiceUnavailable, "bot command queue is full, try again shortly")
+		return
+	}
+
+	timer := time.NewTimer(commandAckTimeout)
+	defer timer.Stop()
+	select {
+	case res := <-result:
+		s.finishCommand(w, r, cmd, res)
+	case <-timer.C:
+		// The bot is busy with a trading cycle; awaitCommand records the outcome
+		s.writeControlResponse(w, r, http.StatusAccepted, controlResponse{
+			OK:      true,
+			Status:  string(AuditQueued),
+			Message: "Queued: the bot will apply this after its current trading cycle",
+		})
+		go s.awaitCommand(cmd, result)
+	}
+}
+
+// awaitCommand records the outcome of a command that outlived its request
+func (s *Server) awaitCommand(cmd Command, result <-chan CommandResult) {
+	timer := time.NewTimer(commandResultWait)
+	defer timer.Stop()
+	select {
+	case res := <-result:
+		if res.Err != nil {
+			s.recordAudit(cmd, AuditFailed, res.Err.Error())
+		} else {
+			s.recordAudit(cmd, AuditApplied, res.Message)
+		}
+	case <-timer.C:
+		s.recordAudit(cmd, AuditFailed, fmt.Sprintf("no outcome from the bot after %s", commandResultWait))
+	}
+}
+
+func (s *Server) finishCommand(w http.ResponseWriter, r *http.Request, cmd Command, res CommandResult) {
+	if res.Err != nil {
+		s.recordAudit(cmd, AuditFailed, res.Err.Error())
+		s.writeControlResponse(w, r, http.StatusConflict, controlResponse{
+			Status:  string(AuditFailed),
+			Message: res.Err.Error(),
+		})
+		return
+	}
+	s.recordAudit(cmd, AuditApplied, res.Message)
+	s.writeControlResponse(w, r, http.StatusOK, controlResponse{
+		OK:      true,
+		Status:  string(AuditApplied),
+		Message: res.Message,
+	})
+}
+
+func (s *Server) rejectCommand(w http.ResponseWriter, r *http.Request, cmd Command, status int, message string) {
+	s.recordAudit(cmd, AuditRejected, message)
+	s.writeControlResponse(w, r, status, controlResponse{
+		Status:  string(AuditRejected),
+		Message: message,
+	})
+}
+
+// writeControlResponse answers htmx requests with an HTML fragment and API clients with JSON.
+// Applied commands tell the page to refresh the panels they change.
+func (s *Server) writeControlResponse(w http.ResponseWriter, r *http.Request, status int, resp controlResponse) {
+	if resp.OK {
+		w.Header().Set("HX-Trigger", "controlApplied")
+	}
+	if r.Header.Get("HX-Request") != "true" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			s.logger.WithError(err).Error("Failed to encode control response")
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := s.templates.ExecuteTemplate(w, "control-result", resp); err != nil {
+		s.logger.WithError(err).Error("Failed to execute control result template")
+	}
+}
@@ -0,0 +1,171 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "secret-token"
+
+func newControlTestServer(t *testing.T, authToken string) (*Server, chan Command, string) {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLog, err := OpenAuditLog(auditPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = auditLog.Close() })
+
+	commands := make(chan Command, 1)
+	s := NewServer(Config{
+		Port:      9847,
+		AuthToken: authToken,
+		Commands:  commands,
+		AuditLog:  auditLog,
+	}, storage.NewMockStorage(), nil, logger)
+	return s, commands, auditPath
+}
+
+func postControl(s *Server, path string, form url.Values, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if token != "" {
+		req.Header.Set("X-Auth-Token", token)
+	}
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func readAudit(t *testing.T, path string) []AuditRecord {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var records []AuditRecord
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec AuditRecord
+		require.NoError(t, json.Unmarshal([]byte(line), &rec))
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestControl_AppliedCommandIsAudited(t *testing.T) {
+	s, commands, auditPath := newControlTestServer(t, testToken)
+	go func() {
+		cmd := <-commands
+		cmd.Respond("New entries paused", nil)
+	}()
+
+	rec := postControl(s, "/api/control/entries/pause",
+		url.Values{"confirm": {string(CommandPauseEntries)}, "reason": {"CPI release"}}, testToken)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp controlResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.True(t, resp.OK)
+	assert.Equal(t, "New entries paused", resp.Message)
+
+	records := readAudit(t, auditPath)
+	require.Len(t, records, 3)
+	assert.Equal(t, AuditRequested, records[0].Status)
+	assert.Equal(t, AuditQueued, records[1].Status)
+	assert.Equal(t, AuditApplied, records[2].Status)
+	assert.Equal(t, CommandPauseEntries, records[2].Action)
+	assert.Equal(t, "CPI release", records[2].Reason)
+}
+
+func TestControl_FullQueueIsAuditedAsRejected(t *testing.T) {
+	s, commands, auditPath := newControlTestServer(t, testToken)
+	commands <- Command{Kind: CommandReconcile}
+
+	rec := postControl(s, "/api/control/entries/pause", url.Values{"confirm": {string(CommandPauseEntries)}}, testToken)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Len(t, commands, 1, "the rejected command must not reach the bot")
+
+	records := readAudit(t, auditPath)
+	require.Len(t, records, 2)
+	assert.Equal(t, AuditRequested, records[0].Status)
+	assert.Equal(t, AuditRejected, records[1].Status)
+	assert.Equal(t, CommandPauseEntries, records[1].Action)
+}
+
+func TestControl_UnconfirmedRequestIsRejected(t *testing.T) {
+	s, commands, auditPath := newControlTestServer(t, testToken)
+
+	rec := postControl(s, "/api/control/reconcile", url.Values{"confirm": {"yes"}}, testToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Empty(t, commands, "unconfirmed command must not reach the bot")
+
+	records := readAudit(t, auditPath)
+	require.Len(t, records, 1)
+	assert.Equal(t, AuditRejected, records[0].Status)
+}
+
+func TestControl_FailedCommandReturnsConflict(t *testing.T) {
+	s, commands, _ := newControlTestServer(t, testToken)
+	go func() {
+		cmd := <-commands
+		cmd.Respond("", assert.AnError)
+	}()
+
+	rec := postControl(s, "/api/control/entries/resume", url.Values{"confirm": {string(CommandResumeEntries)}}, testToken)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestControl_CloseUnknownPosition(t *testing.T) {
+	s, commands, _ := newControlTestServer(t, testToken)
+
+	rec := postControl(s, "/api/control/positions/missing/close",
+		url.Values{"confirm": {string(CommandClosePosition)}}, testToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, commands)
+}
+
+func TestControl_RequiresAuthentication(t *testing.T) {
+	s, commands, _ := newControlTestServer(t, testToken)
+
+	rec := postControl(s, "/api/control/entries/pause", url.Values{"confirm": {string(CommandPauseEntries)}}, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Empty(t, commands)
+}
+
+func TestControl_DisabledWithoutAuthToken(t *testing.T) {
+	s, commands, _ := newControlTestServer(t, "")
+
+	rec := postControl(s, "/api/control/entries/pause", url.Values{"confirm": {string(CommandPauseEntries)}}, "")
+	assert.NotEqual(t, http.StatusOK, rec.Code)
+	assert.Empty(t, commands)
+}
+
+func TestControl_PositionDetailOffersClose(t *testing.T) {
+	s, _, _ := newControlTestServer(t, testToken)
+	position := models.NewPosition("detail-pos", "SPY", 400, 460, time.Now().AddDate(0, 0, 30), 1)
+	require.NoError(t, s.storage.AddPosition(position))
+
+	req := httptest.NewRequest(http.MethodGet, "/partials/position/detail-pos", nil)
+	req.Header.Set("X-Auth-Token", testToken)
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "/api/control/positions/detail-pos/close")
+}
@@ -0,0 +1,319 @@
+package dashboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/journal"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+)
+
+// EventType names a Server-Sent Event pushed to dashboard clients
+type EventType string
+
+const (
+	// EventPositions carries open positions with their latest P&L, as views and rendered HTML
+	EventPositions EventType = "positions"
+	// EventState carries a position state transition
+	EventState EventType = "state"
+	// EventOrder carries an order placement, fill, cancel or failure
+	EventOrder EventType = "order"
+	// EventHeartbeat carries the bot's liveness report
+	EventHeartbeat EventType = "heartbeat"
+)
+
+const (
+	eventsPath = "/events"
+	// subscriberBuffer is how many events a slow client may fall behind before events are
+	// dropped for it; positions events supersede each other, so nothing essential is lost
+	subscriberBuffer = 16
+	// refreshDelay coalesces a burst of order and state events into one positions render
+	refreshDelay = 250 * time.Millisecond
+	// keepAliveInterval keeps idle streams open through proxies
+	keepAliveInterval = 20 * time.Second
+)
+
+// Heartbeat is the bot's liveness report, published after every trading cycle and on a timer
+type Heartbeat struct {
+	Time          time.Time `json:"time"`
+	LastCycle     time.Time `json:"last_cycle,omitempty"`
+	MarketOpen    bool      `json:"market_open"`
+	EntriesPaused bool      `json:"entries_paused"`
+}
+
+// PositionsUpdate is the payload of an EventPositions event
+type PositionsUpdate struct {
+	Time      time.Time      `json:"time"`
+	Positions []PositionView `json:"positions"`
+	HTML      string         `json:"html"` // The positions-content partial, so pages swap it in without a request
+}
+
+// StateChange is the payload of an EventState event
+type StateChange struct {
+	Time       time.Time            `json:"time"`
+	PositionID string               `json:"position_id"`
+	Symbol     string               `json:"symbol,omitempty"`
+	From       models.PositionState `json:"from"`
+	To         models.PositionState `json:"to"`
+	Condition  string               `json:"condition,omitempty"`
+}
+
+// OrderEvent is the payload of an EventOrder event
+type OrderEvent struct {
+	Time       time.Time    `json:"time"`
+	Kind       journal.Kind `json:"kind"`
+	PositionID string       `json:"position_id,omitempty"`
+	Symbol     string       `json:"symbol,omitempty"`
+	OrderID    string       `json:"order_id,omitempty"`
+	Quantity   int          `json:"quantity,omitempty"`
+	Price      float64      `json:"price,omitempty"`
+	Detail     string       `json:"detail,omitempty"`
+}
+
+// event is an encoded Server-Sent Event
+type event struct {
+	typ  EventType
+	data []byte
+}
+
+// eventHub fans events out to every connected client. The latest snapshot events are kept
+// and replayed to new clients, so opening a tab does not wait for the next update.
+type eventHub struct {
+	mu          sync.Mutex
+	subscribers map[chan event]struct{}
+	latest      map[EventType]event
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{
+		subscribers: make(map[chan event]struct{}),
+		latest:      make(map[EventType]event),
+	}
+}
+
+// subscribe registers a client, returning its event channel primed with the latest
+// snapshots and a function that unregisters it
+func (h *eventHub) subscribe() (<-chan event, func()) {
+	ch := make(chan event, subscriberBuffer)
+	h.mu.Lock()
+	for _, typ := range []EventType{EventHeartbeat, EventPositions} {
+		if ev, ok := h.latest[typ]; ok {
+			ch <- ev
+		}
+	}
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subscribers, ch)
+		h.mu.Unlock()
+	}
+}
+
+// publish sends an event to every client without blocking; keep makes it the snapshot
+// replayed to clients that connect later
+func (h *eventHub) publish(typ EventType, payload any, keep bool) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	ev := event{typ: typ, data: data}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if keep {
+		h.latest[typ] = ev
+	}
+	for ch := range h.subscribers {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+	return nil
+}
+
+// PublishPnL records the latest unrealized P&L (gross of fees, in dollars) for positions
+// the bot has just priced, and pushes refreshed positions to clients
+func (s *Server) PublishPnL(pnl map[string]float64) {
+	if s == nil || len(pnl) == 0 {
+		return
+	}
+	s.pnlMu.Lock()
+	for id, value := range pnl {
+		s.livePnL[id] = value
+	}
+	s.pnlMu.Unlock()
+	s.requestRefresh()
+}
+
+// PublishHeartbeat pushes the bot's liveness report to clients, along with positions
+// re-read from storage
+func (s *Server) PublishHeartbeat(hb Heartbeat) {
+	if s == nil {
+		return
+	}
+	if hb.Time.IsZero() {
+		hb.Time = time.Now().UTC()
+	}
+	if err := s.events.publish(EventHeartbeat, hb, true); err != nil {
+		s.logger.WithError(err).Warn("Failed to publish heartbeat")
+	}
+	s.requestRefresh()
+}
+
+// ObserveJournal pushes state transitions and order events to clients and refreshes their
+// positions. Register it with journal.AddListener; it only queues work, so it is safe to
+// call while storage is locked.
+func (s *Server) ObserveJournal(e journal.Entry) {
+	if s == nil {
+		return
+	}
+	var err error
+	switch e.Kind {
+	case journal.KindTransition:
+		err = s.events.publish(EventState, StateChange{
+			Time:       e.Time,
+			PositionID: e.PositionID,
+			Symbol:     e.Symbol,
+			From:       e.From,
+			To:         e.To,
+			Condition:  e.Condition,
+		}, false)
+	case journal.KindOrderPlaced, journal.KindOrderFilled, journal.KindOrderPartialFill,
+		journal.KindOrderCanceled, journal.KindOrderFailed:
+		err = s.events.publish(EventOrder, OrderEvent{
+			Time:       e.Time,
+			Kind:       e.Kind,
+			PositionID: e.PositionID,
+			Symbol:     e.Symbol,
+			OrderID:    e.OrderID,
+			Quantity:   e.Quantity,
+			Price:      e.Price,
+			Detail:     e.Detail,
+		}, false)
+	}
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to publish journal event")
+	}
+	s.requestRefresh()
+}
+
+// requestRefresh asks the refresher to re-render positions; requests made while one is
+// pending are merged
+func (s *Server) requestRefresh() {
+	select {
+	case s.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// runRefresher renders positions once per burst of updates and publishes them, so the
+// work is shared by every client instead of repeated per tab
+func (s *Server) runRefresher() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.refresh:
+		}
+
+		// Let the storage update that follows a transition or fill land first
+		select {
+		case <-s.done:
+			return
+		case <-time.After(refreshDelay):
+		}
+		s.publishPositions()
+	}
+}
+
+// publishPositions renders the current positions and publishes them as the latest snapshot
+func (s *Server) publishPositions() {
+	views := s.convertPositionsToViews(s.currentPositions())
+
+	var html bytes.Buffer
+	if err := s.templates.ExecuteTemplate(&html, "positions-content", views); err != nil {
+		s.logger.WithError(err).Error("Failed to render positions for event stream")
+		return
+	}
+	update := PositionsUpdate{Time: time.Now().UTC(), Positions: views, HTML: html.String()}
+	if err := s.events.publish(EventPositions, update, true); err != nil {
+		s.logger.WithError(err).Warn("Failed to publish positions")
+	}
+}
+
+// currentPositions returns stored positions with the latest P&L the bot published.
+// P&L for positions no longer stored is forgotten.
+func (s *Server) currentPositions() []models.Position {
+	positions := s.storage.GetCurrentPositions()
+
+	s.pnlMu.Lock()
+	defer s.pnlMu.Unlock()
+	stored := make(map[string]bool, len(positions))
+	for i := range positions {
+		stored[positions[i].ID] = true
+		if pnl, ok := s.livePnL[positions[i].ID]; ok {
+			positions[i].CurrentPnL = pnl
+		}
+	}
+	for id := range s.livePnL {
+		if !stored[id] {
+			delete(s.livePnL, id)
+		}
+	}
+	return positions
+}
+
+// handleEvents streams events to one client until it disconnects
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	// The server's write timeout would otherwise end the stream
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		s.logger.WithError(err).Debug("Event stream cannot clear its write deadline; clients will reconnect")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	events, unsubscribe := s.events.subscribe()
+	defer unsubscribe()
+
+	// Ask browsers to reconnect after 5s if the stream drops
+	if _, err := fmt.Fprint(w, "retry: 5000\n\n"); err != nil {
+		return
+	}
+	if err := rc.Flush(); err != nil {
+		s.logger.WithError(err).Error("Event stream does not support flushing")
+		return
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		case ev := <-events:
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.typ, ev.data); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
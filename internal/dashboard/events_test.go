@@ -0,0 +1,163 @@
+package dashboard
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/journal"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEventsTestServer(t *testing.T) *Server {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	s := NewServer(Config{Port: 9847}, storage.NewMockStorage(), nil, logger)
+	t.Cleanup(func() { _ = s.Shutdown(context.Background()) })
+	return s
+}
+
+// readEvent returns the next event on an SSE stream, skipping comments and retry hints
+func readEvent(t *testing.T, r *bufio.Reader) (EventType, string) {
+	t.Helper()
+	var typ EventType
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			typ = EventType(strings.TrimPrefix(line, "event: "))
+		case strings.HasPrefix(line, "data: ") && typ != "":
+			return typ, strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestEventHub_ReplaysLatestSnapshots(t *testing.T) {
+	hub := newEventHub()
+	require.NoError(t, hub.publish(EventHeartbeat, Heartbeat{MarketOpen: true}, true))
+	require.NoError(t, hub.publish(EventState, StateChange{PositionID: "p1"}, false))
+
+	events, unsubscribe := hub.subscribe()
+	defer unsubscribe()
+
+	ev := <-events
+	assert.Equal(t, EventHeartbeat, ev.typ)
+	assert.Empty(t, events, "transient events are not replayed")
+
+	require.NoError(t, hub.publish(EventOrder, OrderEvent{OrderID: "42"}, false))
+	ev = <-events
+	assert.Equal(t, EventOrder, ev.typ)
+	assert.Contains(t, string(ev.data), `"order_id":"42"`)
+}
+
+func TestEventHub_SlowClientDoesNotBlockPublish(t *testing.T) {
+	hub := newEventHub()
+	_, unsubscribe := hub.subscribe()
+	defer unsubscribe()
+
+	for i := 0; i < subscriberBuffer*2; i++ {
+		require.NoError(t, hub.publish(EventOrder, OrderEvent{}, false))
+	}
+}
+
+func TestPublishPnL_OverlaysStoredPositions(t *testing.T) {
+	s := newEventsTestServer(t)
+	position := models.NewPosition("live-pos", "SPY", 400, 460, time.Now().AddDate(0, 0, 30), 1)
+	require.NoError(t, s.storage.AddPosition(position))
+
+	s.PublishPnL(map[string]float64{"live-pos": -120, "gone-pos": 50})
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/positions", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var views []PositionView
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &views))
+	require.Len(t, views, 1)
+	assert.InDelta(t, -120.0, views[0].CurrentPnL, 0.001)
+
+	// P&L for positions no longer stored is dropped
+	s.pnlMu.Lock()
+	defer s.pnlMu.Unlock()
+	assert.NotContains(t, s.livePnL, "gone-pos")
+}
+
+func TestHandleEvents_StreamsUpdates(t *testing.T) {
+	s := newEventsTestServer(t)
+	position := models.NewPosition("stream-pos", "SPY", 400, 460, time.Now().AddDate(0, 0, 30), 1)
+	require.NoError(t, s.storage.AddPosition(position))
+	s.PublishHeartbeat(Heartbeat{MarketOpen: true})
+
+	ts := httptest.NewServer(s.router)
+	defer ts.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+eventsPath, nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	stream := bufio.NewReader(resp.Body)
+
+	// New clients get the latest heartbeat straight away
+	typ, data := readEvent(t, stream)
+	require.Equal(t, EventHeartbeat, typ)
+	var hb Heartbeat
+	require.NoError(t, json.Unmarshal([]byte(data), &hb))
+	assert.True(t, hb.MarketOpen)
+
+	// The heartbeat's positions refresh may land before or after the state event
+	s.ObserveJournal(journal.Entry{
+		Kind:       journal.KindTransition,
+		PositionID: "stream-pos",
+		From:       models.StateOpen,
+		To:         models.StateFirstDown,
+	})
+	seen := map[EventType]string{}
+	for len(seen) < 2 {
+		typ, data := readEvent(t, stream)
+		seen[typ] = data
+	}
+	assert.Contains(t, seen[EventState], `"to":"first_down"`)
+	var update PositionsUpdate
+	require.NoError(t, json.Unmarshal([]byte(seen[EventPositions]), &update))
+	require.Len(t, update.Positions, 1)
+	assert.Contains(t, update.HTML, "stream-pos")
+}
+
+func TestShutdown_EndsEventStreams(t *testing.T) {
+	s := newEventsTestServer(t)
+	ts := httptest.NewServer(s.router)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + eventsPath)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	require.NoError(t, s.Shutdown(context.Background()))
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.ReadAll(resp.Body)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("event stream stayed open after shutdown")
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"net/url"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/eddiefleurent/scranton_strangler/internal/broker"
@@ -40,16 +41,44 @@ type Server struct {
 	allocationThreshold float64
 	profitTarget        float64
 	stopLossPct         float64
+	commands            chan<- Command // Control actions for the bot; nil disables controls
+	auditLog            *AuditLog
+	metrics             http.Handler // Serves /metrics; nil disables
 	// Shared template set for all templates
 	templates *template.Template
+
+	// Live updates pushed by the bot and streamed to clients
+	events   *eventHub
+	refresh  chan struct{}
+	done     chan struct{}
+	doneOnce sync.Once
+	pnlMu    sync.Mutex
+	livePnL  map[string]float64 // Latest unrealized P&L by position ID
+
+	// Account balance is shared by every page and client for balanceCacheTTL
+	balanceMu sync.Mutex
+	balance   float64
+	balanceAt time.Time
 }
 
+const (
+	// balanceCacheTTL is how long a fetched account balance is reused
+	balanceCacheTTL = 30 * time.Second
+	metricsPath     = "/metrics"
+)
+
 type Config struct {
 	Port                int
 	AuthToken           string
 	AllocationThreshold float64 // Allocation threshold percentage (0-100)
 	ProfitTarget        float64 // Strategy profit target (0-1, e.g., 0.5 for 50%)
 	StopLossPct         float64 // Strategy stop loss percentage (e.g., 2.5 for 250%)
+	// Commands receives control actions (close, pause, resume, reconcile) for the bot to apply.
+	// Controls are only served when this is set and AuthToken is configured.
+	Commands chan<- Command
+	AuditLog *AuditLog // Control actions are appended here as well as logged; may be nil
+	// Metrics serves Prometheus metrics at /metrics, behind AuthToken when it is set; may be nil
+	Metrics http.Handler
 }
 
 type DashboardData struct {
@@ -58,6 +87,7 @@ type DashboardData struct {
 	LastUpdate     time.Time
 	AccountBalance float64
 	MarketStatus   string
+	Controls       bool // Whether control buttons are shown
 }
 
 type PositionView struct {
@@ -71,7 +101,8 @@ type PositionView struct {
 	CallStrike       float64
 	PutStrike        float64
 	CreditReceived   float64
-	CurrentPnL       float64
+	CurrentPnL       float64 // Net of Fees
+	Fees             float64
 	PnLPercent       float64
 	ProfitTarget     float64
 	StopLoss         float64
@@ -85,12 +116,15 @@ type Statistics struct {
 	LosingTrades        int
 	WinRate             float64
 	TotalPnL            float64
+	TotalFees           float64 // Commissions and fees on closed trades, already deducted from TotalPnL
 	AveragePnL          float64
 	CurrentOpen         int
 	TotalAllocated      float64
 	AllocationPct       float64
 	AllocationThreshold float64
 	IsAllocationHigh    bool
+	TradingHalt         *models.TradingHalt // Set while today's daily loss halt is in effect
+	EntryPause          *models.EntryPause  // Set while an operator has paused new entries
 }
 
 func NewServer(cfg Config, storage storage.Interface, broker broker.Broker, logger *logrus.Logger) *Server {
@@ -104,6 +138,13 @@ func NewServer(cfg Config, storage storage.Interface, broker broker.Broker, logg
 		allocationThreshold: cfg.AllocationThreshold,
 		profitTarget:        cfg.ProfitTarget,
 		stopLossPct:         cfg.StopLossPct,
+		commands:            cfg.Commands,
+		auditLog:            cfg.AuditLog,
+		metrics:             cfg.Metrics,
+		events:              newEventHub(),
+		refresh:             make(chan struct{}, 1),
+		done:                make(chan struct{}),
+		livePnL:             make(map[string]float64),
 	}
 
 	// Pre-parse templates with shared FuncMap
@@ -112,6 +153,7 @@ func NewServer(cfg Config, storage storage.Interface, broker broker.Broker, logg
 	}
 
 	s.setupRoutes()
+	go s.runRefresher()
 	return s
 }
 
@@ -139,7 +181,7 @@ func (s *Server) setupRoutes() {
 	s.router.Use(middleware.RealIP)
 	s.router.Use(s.requestLoggerMiddleware)
 	s.router.Use(middleware.Recoverer)
-	s.router.Use(middleware.Timeout(60 * time.Second))
+	s.router.Use(s.timeoutMiddleware)
 	s.router.Use(middleware.Compress(5))
 
 	// Create a filesystem rooted at the "static" directory for proper embedded filesystem serving
@@ -169,8 +211,22 @@ func (s *Server) setupRoutes() {
 			r.Get("/partials/history", s.handleHistoryPartial)
 			r.Get("/partials/recent-history", s.handleRecentHistoryPartial)
 			r.Get("/partials/position/{id}", s.handlePositionDetailPartial)
+			r.Get(eventsPath, s.handleEvents)
+			if s.metrics != nil {
+				r.Method(http.MethodGet, metricsPath, s.metrics)
+			}
+
+			if s.controlsEnabled() {
+				r.Post("/api/control/positions/{id}/close", s.handleClosePosition)
+				r.Post("/api/control/entries/pause", s.handlePauseEntries)
+				r.Post("/api/control/entries/resume", s.handleResumeEntries)
+				r.Post("/api/control/reconcile", s.handleReconcile)
+			}
 		})
 	} else {
+		if s.commands != nil {
+			s.logger.Warn("Dashboard controls disabled: set dashboard.auth_token to enable them")
+		}
 		s.router.Get("/", s.handleDashboard)
 		s.router.Get("/history", s.handleFullHistory)
 		s.router.Get("/api/positions", s.handleGetPositions)
@@ -182,6 +238,10 @@ func (s *Server) setupRoutes() {
 		s.router.Get("/partials/history", s.handleHistoryPartial)
 		s.router.Get("/partials/recent-history", s.handleRecentHistoryPartial)
 		s.router.Get("/partials/position/{id}", s.handlePositionDetailPartial)
+		s.router.Get(eventsPath, s.handleEvents)
+		if s.metrics != nil {
+			s.router.Method(http.MethodGet, metricsPath, s.metrics)
+		}
 	}
 
 	// Health endpoint is always public
@@ -189,6 +249,19 @@ func (s *Server) setupRoutes() {
 
 }
 
+// timeoutMiddleware bounds request handling, except for event streams, which stay open
+// for as long as the client listens
+func (s *Server) timeoutMiddleware(next http.Handler) http.Handler {
+	withTimeout := middleware.Timeout(60 * time.Second)(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == eventsPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+		withTimeout.ServeHTTP(w, r)
+	})
+}
+
 func (s *Server) requestLoggerMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Clone the request for logging, redacting sensitive tokens
@@ -208,6 +281,7 @@ func (s *Server) requestLoggerMiddleware(next http.Handler) http.Handler {
 		// Also log non-polling endpoints at debug level
 		isPollingEndpoint := strings.HasPrefix(r.URL.Path, "/partials/") ||
 							 strings.HasPrefix(r.URL.Path, "/static/") ||
+							 r.URL.Path == metricsPath ||
 							 r.URL.Path == "/favicon.ico"
 
 		if shouldLog {
@@ -347,6 +421,8 @@ func (s *Server) Start() error {
 }
 
 func (s *Server) Shutdown(ctx context.Context) error {
+	// End event streams and the refresher; open streams would otherwise hold up shutdown
+	s.doneOnce.Do(func() { close(s.done) })
 	if s.server != nil {
 		return s.server.Shutdown(ctx)
 	}
@@ -369,7 +445,7 @@ func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleGetPositions(w http.ResponseWriter, r *http.Request) {
-	positions := s.storage.GetCurrentPositions()
+	positions := s.currentPositions()
 
 	views := s.convertPositionsToViews(positions)
 	
@@ -396,7 +472,7 @@ func (s *Server) handleGetStats(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleGetPosition(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	
-	position, found := s.storage.GetPositionByID(id)
+	position, found := s.positionByID(id)
 	if !found {
 		s.logger.WithField("position_id", id).Warn("Position not found")
 		http.Error(w, "Not Found", http.StatusNotFound)
@@ -424,7 +500,7 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handlePositionsPartial(w http.ResponseWriter, r *http.Request) {
-	positions := s.storage.GetCurrentPositions()
+	positions := s.currentPositions()
 	views := s.convertPositionsToViews(positions)
 	
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -451,17 +527,20 @@ func (s *Server) handleStatsPartial(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handlePositionDetailPartial(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	position, found := s.storage.GetPositionByID(id)
+	position, found := s.positionByID(id)
 	if !found {
 		s.logger.WithField("position_id", id).Warn("Position not found")
 		http.Error(w, "Not Found", http.StatusNotFound)
 		return
 	}
 
-	view := s.convertPositionToView(&position)
-	
+	data := struct {
+		PositionView
+		Controls bool
+	}{s.convertPositionToView(&position), s.controlsEnabled()}
+
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	if err := s.templates.ExecuteTemplate(w, "position-detail.html", view); err != nil {
+	if err := s.templates.ExecuteTemplate(w, "position-detail.html", data); err != nil {
 		s.logger.WithError(err).Error("Failed to execute position detail template")
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
@@ -528,7 +607,7 @@ func (s *Server) handleFullHistory(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) getDashboardData(ctx context.Context) (*DashboardData, error) {
-	positions := s.storage.GetCurrentPositions()
+	positions := s.currentPositions()
 
 	stats, err := s.calculateStatisticsCtx(ctx)
 	if err != nil {
@@ -539,7 +618,7 @@ func (s *Server) getDashboardData(ctx context.Context) (*DashboardData, error) {
 	balanceCtx, balanceCancel := context.WithTimeout(ctx, 10*time.Second)
 	defer balanceCancel()
 
-	accountBalance, err := s.broker.GetAccountBalanceCtx(balanceCtx)
+	accountBalance, err := s.accountBalance(balanceCtx)
 	if err != nil {
 		// Check if error is due to context cancellation/timeout
 		if balanceCtx.Err() != nil {
@@ -561,6 +640,7 @@ func (s *Server) getDashboardData(ctx context.Context) (*DashboardData, error) {
 		LastUpdate:     time.Now(),
 		AccountBalance: accountBalance,
 		MarketStatus:   marketStatus,
+		Controls:       s.controlsEnabled(),
 	}, nil
 }
 
@@ -605,7 +685,11 @@ func (s *Server) convertPositionToView(pos *models.Position) PositionView {
 		}
 	}
 	
+	// Closed positions were recorded net of fees; open ones still owe the fees paid so far
 	currentPnL := pos.CurrentPnL
+	if pos.State != models.StateClosed {
+		currentPnL -= pos.Fees
+	}
 	pnlPercent := 0.0
 	if pos.CreditReceived > 0 {
 		pnlPercent = (currentPnL / pos.CreditReceived) * 100
@@ -635,6 +719,7 @@ func (s *Server) convertPositionToView(pos *models.Position) PositionView {
 		PutStrike:        pos.PutStrike,
 		CreditReceived:   pos.CreditReceived,
 		CurrentPnL:       currentPnL,
+		Fees:             pos.Fees,
 		PnLPercent:       pnlPercent,
 		ProfitTarget:     profitTarget,
 		StopLoss:         stopLoss,
@@ -644,7 +729,7 @@ func (s *Server) convertPositionToView(pos *models.Position) PositionView {
 }
 
 func (s *Server) calculateStatisticsCtx(ctx context.Context) (*Statistics, error) {
-	positions := s.storage.GetCurrentPositions()
+	positions := s.currentPositions()
 	historicalPositions := s.storage.GetHistory()
 
 	stats := &Statistics{}
@@ -668,6 +753,7 @@ func (s *Server) calculateStatisticsCtx(ctx context.Context) (*Statistics, error
 			stats.LosingTrades++
 		}
 		stats.TotalPnL += pos.CurrentPnL
+		stats.TotalFees += pos.Fees
 	}
 
 	if stats.TotalTrades > 0 {
@@ -682,7 +768,7 @@ func (s *Server) calculateStatisticsCtx(ctx context.Context) (*Statistics, error
 	// Always assign TotalAllocated regardless of account balance call result
 	stats.TotalAllocated = totalAllocated
 
-	accountBalance, err := s.broker.GetAccountBalanceCtx(balanceCtx)
+	accountBalance, err := s.accountBalance(balanceCtx)
 	if err == nil && accountBalance > 0 {
 		stats.AllocationPct = (totalAllocated / accountBalance) * 100
 	} else {
@@ -697,9 +783,53 @@ func (s *Server) calculateStatisticsCtx(ctx context.Context) (*Statistics, error
 	stats.AllocationThreshold = s.allocationThreshold
 	stats.IsAllocationHigh = stats.AllocationPct > s.allocationThreshold
 
+	if halt := s.storage.GetTradingHalt(); halt.ActiveOn(tradingDay()) {
+		stats.TradingHalt = halt
+	}
+	stats.EntryPause = s.storage.GetEntryPause()
+
 	return stats, nil
 }
 
+// accountBalance returns the account balance, fetching it from the broker at most once
+// per balanceCacheTTL however many pages and clients ask
+func (s *Server) accountBalance(ctx context.Context) (float64, error) {
+	s.balanceMu.Lock()
+	defer s.balanceMu.Unlock()
+	if !s.balanceAt.IsZero() && time.Since(s.balanceAt) < balanceCacheTTL {
+		return s.balance, nil
+	}
+	balance, err := s.broker.GetAccountBalanceCtx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	s.balance, s.balanceAt = balance, time.Now()
+	return balance, nil
+}
+
+// positionByID returns a stored position with the latest P&L the bot published
+func (s *Server) positionByID(id string) (models.Position, bool) {
+	position, found := s.storage.GetPositionByID(id)
+	if !found {
+		return position, false
+	}
+	s.pnlMu.Lock()
+	defer s.pnlMu.Unlock()
+	if pnl, ok := s.livePnL[id]; ok {
+		position.CurrentPnL = pnl
+	}
+	return position, true
+}
+
+// tradingDay returns today's New York trading day as YYYY-MM-DD
+func tradingDay() string {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		loc = time.FixedZone("EST", -5*60*60)
+	}
+	return time.Now().In(loc).Format("2006-01-02")
+}
+
 func isMarketOpen() bool {
 	now := time.Now()
 	loc, err := time.LoadLocation("America/New_York")
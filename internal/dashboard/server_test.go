@@ -0,0 +1,39 @@
+package dashboard
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_ServedBehindAuthToken(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "strangler_open_positions 2\n")
+	})
+	s := NewServer(Config{Port: 9847, AuthToken: testToken, Metrics: metrics}, storage.NewMockStorage(), nil, logger)
+	defer func() { _ = s.Shutdown(t.Context()) }()
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics?token="+testToken, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "strangler_open_positions 2")
+}
+
+func TestMetrics_NotServedWithoutHandler(t *testing.T) {
+	s := newEventsTestServer(t)
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -19,6 +19,15 @@ document.addEventListener('DOMContentLoaded', function() {
         }
     });
     
+    // Control actions explain refusals in their response body; show it in place
+    document.addEventListener('htmx:beforeSwap', function(event) {
+        const path = event.detail.requestConfig?.path || '';
+        if (path.startsWith('/api/control/') && event.detail.xhr.status >= 400) {
+            event.detail.shouldSwap = true;
+            event.detail.isError = false;
+        }
+    });
+    
     document.addEventListener('htmx:responseError', function(event) {
         console.error('HTMX response error:', event.detail);
         showError('Failed to update data. Check network connection.');
@@ -29,6 +38,8 @@ document.addEventListener('DOMContentLoaded', function() {
         showError('Request timed out. Retrying...');
     });
     
+    connectEvents();
+    
     let errorTimeout;
     
     function showError(message) {
@@ -74,4 +85,67 @@ document.addEventListener('click', function(e) {
         e.preventDefault();
         hidePositionDetail();
     }
-});
\ No newline at end of file
+});
+// A heartbeat older than this marks the bot as stale
+const HEARTBEAT_STALE_MS = 60000;
+let lastHeartbeat = 0;
+
+// connectEvents subscribes to the server's event stream. The bot pushes positions with
+// live P&L, state changes, order events and heartbeats; the browser reconnects on its own.
+function connectEvents() {
+    if (!window.EventSource || !document.getElementById('positions-container')) {
+        return;
+    }
+    const source = new EventSource('/events');
+    
+    source.addEventListener('positions', function(e) {
+        const update = JSON.parse(e.data);
+        const container = document.getElementById('positions-container');
+        if (container) {
+            container.innerHTML = update.html;
+            htmx.process(container);
+        }
+        setLastUpdate(update.time);
+    });
+    
+    // State changes and orders move P&L and history; let those sections refresh themselves
+    ['state', 'order'].forEach(function(type) {
+        source.addEventListener(type, function() {
+            htmx.trigger(document.body, 'positionsChanged');
+        });
+    });
+    
+    source.addEventListener('heartbeat', function(e) {
+        const hb = JSON.parse(e.data);
+        lastHeartbeat = Date.parse(hb.time) || Date.now();
+        const cycle = hb.last_cycle ? new Date(hb.last_cycle).toLocaleTimeString() : 'none yet';
+        setBotStatus('alive', hb.entries_paused ? 'Bot: running (entries paused)' : 'Bot: running',
+                     'Last trading cycle: ' + cycle);
+    });
+    
+    source.onerror = function() {
+        setBotStatus('stale', 'Bot: disconnected', 'Reconnecting to the event stream');
+    };
+    
+    setInterval(function() {
+        if (lastHeartbeat && Date.now() - lastHeartbeat > HEARTBEAT_STALE_MS) {
+            setBotStatus('stale', 'Bot: no heartbeat', 'Last heartbeat ' + new Date(lastHeartbeat).toLocaleTimeString());
+        }
+    }, 15000);
+}
+
+function setBotStatus(state, text, title) {
+    const el = document.getElementById('bot-status');
+    if (el) {
+        el.className = 'bot-status ' + state;
+        el.textContent = text;
+        el.title = title;
+    }
+}
+
+function setLastUpdate(time) {
+    const el = document.getElementById('last-update');
+    if (el && time) {
+        el.textContent = 'Updated: ' + new Date(time).toLocaleTimeString([], { hour12: false });
+    }
+}
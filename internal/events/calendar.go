@@ -0,0 +1,242 @@
+// Package events provides the economic event calendar used to stand aside from new
+// entries around market-moving releases such as FOMC, CPI and NFP.
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Impact ranks how much an event is expected to move the market
+type Impact string
+
+const (
+	// ImpactLow marks minor releases
+	ImpactLow Impact = "low"
+	// ImpactMedium marks releases that occasionally move the market
+	ImpactMedium Impact = "medium"
+	// ImpactHigh marks releases such as FOMC, CPI and NFP
+	ImpactHigh Impact = "high"
+)
+
+// rank orders impacts so a minimum impact can be compared
+func (i Impact) rank() int {
+	switch i {
+	case ImpactLow:
+		return 1
+	case ImpactMedium:
+		return 2
+	case ImpactHigh:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Valid reports whether the impact is one of the known levels
+func (i Impact) Valid() bool {
+	return i.rank() > 0
+}
+
+// AtLeast reports whether i is as or more significant than min
+func (i Impact) AtLeast(min Impact) bool {
+	return i.rank() >= min.rank()
+}
+
+// Event is a single scheduled economic release.
+// An event without a release time is treated as spanning its whole day.
+type Event struct {
+	Name   string
+	Type   string // e.g. FOMC, CPI, NFP
+	Time   time.Time
+	AllDay bool
+	Impact Impact
+}
+
+// Start returns the earliest moment the event affects the market
+func (e Event) Start() time.Time {
+	return e.Time
+}
+
+// End returns the latest moment the event affects the market
+func (e Event) End() time.Time {
+	if e.AllDay {
+		return e.Time.AddDate(0, 0, 1)
+	}
+	return e.Time
+}
+
+// String formats the event for log messages, e.g. "FOMC rate decision (FOMC) 2025-01-29 14:00 EST"
+func (e Event) String() string {
+	label := e.Name
+	if e.Type != "" && !strings.EqualFold(e.Type, e.Name) {
+		label = fmt.Sprintf("%s (%s)", e.Name, e.Type)
+	}
+	if e.AllDay {
+		return fmt.Sprintf("%s %s", label, e.Time.Format("2006-01-02"))
+	}
+	return fmt.Sprintf("%s %s", label, e.Time.Format("2006-01-02 15:04 MST"))
+}
+
+// Calendar supplies scheduled events. Implementations may be static files or live feeds.
+type Calendar interface {
+	// EventsBetween returns events that overlap [start, end], ordered by time
+	EventsBetween(start, end time.Time) ([]Event, error)
+}
+
+// NextEventWithin returns the first event of at least minImpact whose time falls within
+// window of now, on either side. It returns nil when no such event exists.
+func NextEventWithin(c Calendar, now time.Time, window time.Duration, minImpact Impact) (*Event, error) {
+	if c == nil {
+		return nil, nil
+	}
+	evts, err := c.EventsBetween(now.Add(-window), now.Add(window))
+	if err != nil {
+		return nil, err
+	}
+	for i := range evts {
+		if evts[i].Impact.AtLeast(minImpact) {
+			return &evts[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// StaticCalendar is an in-memory calendar, typically loaded from a file
+type StaticCalendar struct {
+	events []Event
+}
+
+// Ensure StaticCalendar implements Calendar
+var _ Calendar = (*StaticCalendar)(nil)
+
+// NewStaticCalendar creates a calendar from the given events
+func NewStaticCalendar(evts []Event) *StaticCalendar {
+	sorted := make([]Event, len(evts))
+	copy(sorted, evts)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+	return &StaticCalendar{events: sorted}
+}
+
+// EventsBetween returns events that overlap [start, end], ordered by time
+func (c *StaticCalendar) EventsBetween(start, end time.Time) ([]Event, error) {
+	var out []Event
+	for _, e := range c.events {
+		if e.End().Before(start) || e.Start().After(end) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// Len returns the number of events in the calendar
+func (c *StaticCalendar) Len() int {
+	return len(c.events)
+}
+
+// fileEvent is the on-disk form of an Event
+type fileEvent struct {
+	Name   string `yaml:"name" json:"name"`
+	Type   string `yaml:"type" json:"type"`
+	Date   string `yaml:"date" json:"date"`     // YYYY-MM-DD
+	Time   string `yaml:"time" json:"time"`     // Optional HH:MM in the calendar timezone
+	Impact string `yaml:"impact" json:"impact"` // low | medium | high (default: high)
+}
+
+// calendarFile is the on-disk calendar layout
+type calendarFile struct {
+	Timezone string      `yaml:"timezone" json:"timezone"` // Default: America/New_York
+	Events   []fileEvent `yaml:"events" json:"events"`
+}
+
+// LoadFile reads a YAML or JSON calendar file, for example:
+//
+//	timezone: America/New_York
+//	events:
+//	  - name: FOMC rate decision
+//	    type: FOMC
+//	    date: "2025-01-29"
+//	    time: "14:00"
+//	    impact: high
+func LoadFile(path string) (*StaticCalendar, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path comes from the bot configuration
+	if err != nil {
+		return nil, fmt.Errorf("reading event calendar %q: %w", path, err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes calendar data in JSON (an object with the same fields) or YAML
+func Parse(data []byte) (*StaticCalendar, error) {
+	var file calendarFile
+	var err error
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		err = json.Unmarshal(trimmed, &file)
+	} else {
+		err = yaml.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing event calendar: %w", err)
+	}
+
+	tz := file.Timezone
+	if tz == "" {
+		tz = "America/New_York"
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event calendar timezone %q: %w", tz, err)
+	}
+
+	evts := make([]Event, 0, len(file.Events))
+	for i, fe := range file.Events {
+		e, err := fe.toEvent(loc)
+		if err != nil {
+			return nil, fmt.Errorf("event %d (%s): %w", i, fe.Name, err)
+		}
+		evts = append(evts, e)
+	}
+	return NewStaticCalendar(evts), nil
+}
+
+// toEvent validates a file entry and converts it to an Event
+func (fe fileEvent) toEvent(loc *time.Location) (Event, error) {
+	if strings.TrimSpace(fe.Name) == "" && strings.TrimSpace(fe.Type) == "" {
+		return Event{}, fmt.Errorf("name or type is required")
+	}
+	e := Event{Name: strings.TrimSpace(fe.Name), Type: strings.ToUpper(strings.TrimSpace(fe.Type))}
+	if e.Name == "" {
+		e.Name = e.Type
+	}
+
+	e.Impact = Impact(strings.ToLower(strings.TrimSpace(fe.Impact)))
+	if e.Impact == "" {
+		e.Impact = ImpactHigh
+	}
+	if !e.Impact.Valid() {
+		return Event{}, fmt.Errorf("invalid impact %q: must be low, medium or high", fe.Impact)
+	}
+
+	if strings.TrimSpace(fe.Time) == "" {
+		d, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(fe.Date), loc)
+		if err != nil {
+			return Event{}, fmt.Errorf("invalid date %q: %w", fe.Date, err)
+		}
+		e.Time, e.AllDay = d, true
+		return e, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04", strings.TrimSpace(fe.Date)+" "+strings.TrimSpace(fe.Time), loc)
+	if err != nil {
+		return Event{}, fmt.Errorf("invalid date/time %q %q: %w", fe.Date, fe.Time, err)
+	}
+	e.Time = t
+	return e, nil
+}
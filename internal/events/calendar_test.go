@@ -0,0 +1,138 @@
+package events
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCalendarYAML = `
+timezone: America/New_York
+events:
+  - name: FOMC rate decision
+    type: fomc
+    date: "2025-01-29"
+    time: "14:00"
+  - name: Retail sales
+    type: RETAIL
+    date: "2025-01-16"
+    time: "08:30"
+    impact: medium
+  - type: CPI
+    date: "2025-01-15"
+`
+
+func TestParse_YAML(t *testing.T) {
+	cal, err := Parse([]byte(testCalendarYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cal.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", cal.Len())
+	}
+
+	all, _ := cal.EventsBetween(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+	if all[0].Type != "CPI" || !all[0].AllDay || all[0].Name != "CPI" || all[0].Impact != ImpactHigh {
+		t.Errorf("unexpected first event (sorted by time): %+v", all[0])
+	}
+	fomc := all[2]
+	if fomc.Type != "FOMC" || fomc.Time.UTC().Hour() != 19 {
+		t.Errorf("FOMC should be 14:00 ET (19:00 UTC), got %+v", fomc)
+	}
+	if got := fomc.String(); got != "FOMC rate decision (FOMC) 2025-01-29 14:00 EST" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestParse_JSON(t *testing.T) {
+	data := `{"events": [{"name": "Nonfarm payrolls", "type": "NFP", "date": "2025-02-07", "time": "08:30"}]}`
+	cal, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cal.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", cal.Len())
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"bad date":     "events:\n  - type: CPI\n    date: 2025-13-01\n",
+		"bad time":     "events:\n  - type: CPI\n    date: \"2025-01-15\"\n    time: \"8.30\"\n",
+		"bad impact":   "events:\n  - type: CPI\n    date: \"2025-01-15\"\n    impact: extreme\n",
+		"missing name": "events:\n  - date: \"2025-01-15\"\n",
+		"bad timezone": "timezone: Mars/Olympus\nevents: []\n",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(data)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestNextEventWithin(t *testing.T) {
+	cal, err := Parse([]byte(testCalendarYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ny, _ := time.LoadLocation("America/New_York")
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		window    time.Duration
+		minImpact Impact
+		want      string
+	}{
+		{"day before FOMC", time.Date(2025, 1, 28, 14, 0, 0, 0, ny), 48 * time.Hour, ImpactHigh, "FOMC"},
+		{"day after FOMC", time.Date(2025, 1, 30, 10, 0, 0, 0, ny), 24 * time.Hour, ImpactHigh, "FOMC"},
+		{"outside window", time.Date(2025, 1, 24, 10, 0, 0, 0, ny), 48 * time.Hour, ImpactHigh, ""},
+		{"all-day CPI covers its whole day", time.Date(2025, 1, 15, 23, 0, 0, 0, ny), time.Hour, ImpactHigh, "CPI"},
+		{"medium event ignored at high minimum", time.Date(2025, 1, 17, 10, 0, 0, 0, ny), 26 * time.Hour, ImpactHigh, ""},
+		{"medium event blocks at medium minimum", time.Date(2025, 1, 17, 10, 0, 0, 0, ny), 26 * time.Hour, ImpactMedium, "RETAIL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := NextEventWithin(cal, tt.now, tt.window, tt.minImpact)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := ""
+			if event != nil {
+				got = event.Type
+			}
+			if got != tt.want {
+				t.Errorf("NextEventWithin() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if event, err := NextEventWithin(nil, time.Now(), time.Hour, ImpactHigh); event != nil || err != nil {
+		t.Errorf("nil calendar should report no event, got %v, %v", event, err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.yaml")
+	if err := os.WriteFile(path, []byte(testCalendarYAML), 0o600); err != nil {
+		t.Fatalf("failed to write calendar: %v", err)
+	}
+	if _, err := LoadFile(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err == nil || !errors.Is(err, os.ErrNotExist) || !strings.Contains(err.Error(), "missing.yaml") {
+		t.Errorf("expected not-exist error naming the file, got %v", err)
+	}
+
+	// The example calendar shipped with the repo must stay loadable
+	if _, err := LoadFile(filepath.Join("..", "..", "data", "events.example.yaml")); err != nil {
+		t.Errorf("example calendar failed to load: %v", err)
+	}
+}
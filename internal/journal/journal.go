@@ -0,0 +1,191 @@
+// Package journal keeps an append-only JSON Lines record of position state transitions,
+// order events and reconciliation decisions, so a position's history can be replayed
+// after the fact.
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+)
+
+// Kind identifies what a journal entry records
+type Kind string
+
+const (
+	// KindTransition records a position state transition
+	KindTransition Kind = "transition"
+	// KindOrderPlaced records an entry, exit or adjustment order sent to the broker
+	KindOrderPlaced Kind = "order_placed"
+	// KindOrderFilled records an order that filled completely
+	KindOrderFilled Kind = "order_filled"
+	// KindOrderPartialFill records an order given up on after filling part of its quantity
+	KindOrderPartialFill Kind = "order_partial_fill"
+	// KindOrderCanceled records a cancel the broker confirmed
+	KindOrderCanceled Kind = "order_canceled"
+	// KindOrderFailed records an order that was rejected, expired or timed out unfilled
+	KindOrderFailed Kind = "order_failed"
+	// KindReconcile records a reconciliation decision about a position
+	KindReconcile Kind = "reconcile"
+)
+
+// Entry is one line of the journal. Fields that do not apply to an entry's Kind are omitted.
+type Entry struct {
+	Time          time.Time            `json:"time"`
+	Kind          Kind                 `json:"kind"`
+	PositionID    string               `json:"position_id,omitempty"`
+	Symbol        string               `json:"symbol,omitempty"`
+	From          models.PositionState `json:"from,omitempty"`
+	To            models.PositionState `json:"to,omitempty"`
+	Condition     string               `json:"condition,omitempty"`
+	OrderID       string               `json:"order_id,omitempty"`
+	Spot          float64              `json:"spot,omitempty"`
+	PnL           float64              `json:"pnl,omitempty"`
+	Quantity      int                  `json:"quantity,omitempty"`
+	Price         float64              `json:"price,omitempty"`
+	CorrelationID string               `json:"correlation_id,omitempty"`
+	Detail        string               `json:"detail,omitempty"`
+}
+
+// Journal appends entries to a JSON Lines file and passes them to its listeners. A nil
+// *Journal is valid and records nothing, so callers can hold one unconditionally when
+// journaling is disabled.
+type Journal struct {
+	mu            sync.Mutex
+	file          *os.File // nil for a journal that only feeds listeners
+	logger        *log.Logger
+	correlationID string
+	spots         map[string]float64
+	listeners     []func(Entry)
+}
+
+// New returns a journal that keeps no file, for when only its listeners need the entries
+func New(logger *log.Logger) *Journal {
+	if logger == nil {
+		logger = log.New(os.Stderr, "journal: ", log.LstdFlags)
+	}
+	return &Journal{logger: logger, spots: make(map[string]float64)}
+}
+
+// Open opens the journal at path for appending, creating it and its directory if needed
+func Open(path string, logger *log.Logger) (*Journal, error) {
+	if logger == nil {
+		logger = log.New(os.Stderr, "journal: ", log.LstdFlags)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("creating journal directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) // #nosec G304 - path comes from config
+	if err != nil {
+		return nil, fmt.Errorf("opening journal: %w", err)
+	}
+	return &Journal{file: file, logger: logger, spots: make(map[string]float64)}, nil
+}
+
+// Close closes the journal file
+func (j *Journal) Close() error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	return j.file.Close()
+}
+
+// AddListener passes every entry recorded from now on to fn. Listeners run on the
+// recording goroutine, possibly while storage locks are held, so fn must not block or
+// call back into storage.
+func (j *Journal) AddListener(fn func(Entry)) {
+	if j == nil || fn == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.listeners = append(j.listeners, fn)
+}
+
+// SetCorrelationID tags subsequent entries that carry no correlation ID of their own,
+// e.g. with the ID of the trading cycle that is running
+func (j *Journal) SetCorrelationID(id string) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.correlationID = id
+}
+
+// NoteSpot remembers the latest underlying price for symbol so later entries can carry it
+func (j *Journal) NoteSpot(symbol string, spot float64) {
+	if j == nil || spot <= 0 {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.spots[symbol] = spot
+}
+
+// Record appends e to the journal, filling in its time, correlation ID and spot when unset.
+// Write failures are logged rather than returned so journaling never stops trading.
+func (j *Journal) Record(e Entry) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if e.CorrelationID == "" {
+		e.CorrelationID = j.correlationID
+	}
+	if e.Spot == 0 && e.Symbol != "" {
+		e.Spot = j.spots[e.Symbol]
+	}
+	if j.file != nil {
+		j.write(e)
+	}
+	listeners := j.listeners
+	j.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(e)
+	}
+}
+
+// write appends e to the file; j.mu must be held
+func (j *Journal) write(e Entry) {
+	line, err := json.Marshal(e)
+	if err != nil {
+		j.logger.Printf("Warning: Failed to encode journal entry: %v", err)
+		return
+	}
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		j.logger.Printf("Warning: Failed to write journal entry for position %s: %v", e.PositionID, err)
+	}
+}
+
+// ObserveTransition records a position state transition; register it with
+// models.SetTransitionObserver
+func (j *Journal) ObserveTransition(ev models.TransitionEvent) {
+	j.Record(Entry{
+		Time:       ev.Time,
+		Kind:       KindTransition,
+		PositionID: ev.PositionID,
+		Symbol:     ev.Symbol,
+		From:       ev.From,
+		To:         ev.To,
+		Condition:  ev.Condition,
+		OrderID:    ev.OrderID,
+		PnL:        ev.PnL,
+		Quantity:   ev.Quantity,
+	})
+}
@@ -0,0 +1,139 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+)
+
+func TestJournal_RecordsTransitionsAndReplaysTimeline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "journal.jsonl")
+	j, err := Open(path, nil)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	models.SetTransitionObserver(j.ObserveTransition)
+	defer models.SetTransitionObserver(nil)
+
+	j.SetCorrelationID("cycle-1")
+	j.NoteSpot("SPY", 452.10)
+
+	position := models.NewPosition("pos-1", "SPY", 400, 460, time.Now().AddDate(0, 0, 45), 1)
+	if err := position.TransitionState(models.StateSubmitted, models.ConditionOrderPlaced); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+	position.EntryOrderID = "100"
+	j.Record(Entry{Kind: KindOrderPlaced, PositionID: "pos-1", Symbol: "SPY", OrderID: "100", Quantity: 1, Price: 2.50})
+	if err := position.TransitionState(models.StateOpen, models.ConditionOrderFilled); err != nil {
+		t.Fatalf("Transition failed: %v", err)
+	}
+	j.Record(Entry{Kind: KindReconcile, PositionID: "other", Detail: "phantom removed"})
+	if err := j.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	entries, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(entries))
+	}
+
+	timeline := Timeline(entries, "pos-1")
+	if len(timeline) != 3 {
+		t.Fatalf("Expected 3 entries for pos-1, got %d", len(timeline))
+	}
+	first, last := timeline[0], timeline[2]
+	if first.Kind != KindTransition || first.From != models.StateIdle || first.To != models.StateSubmitted ||
+		first.Condition != models.ConditionOrderPlaced {
+		t.Errorf("Unexpected first entry %+v", first)
+	}
+	if first.CorrelationID != "cycle-1" || first.Spot != 452.10 {
+		t.Errorf("Expected correlation ID and spot to be filled in, got %q and %.2f", first.CorrelationID, first.Spot)
+	}
+	if last.To != models.StateOpen || last.OrderID != "100" {
+		t.Errorf("Expected the fill transition to carry entry order 100, got %+v", last)
+	}
+	if !strings.Contains(last.String(), "submitted -> open (order_filled)") {
+		t.Errorf("Unexpected timeline line %q", last.String())
+	}
+
+	summaries := Positions(entries)
+	if len(summaries) != 2 {
+		t.Fatalf("Expected 2 positions, got %d", len(summaries))
+	}
+	for _, s := range summaries {
+		if s.PositionID == "pos-1" && (s.Events != 3 || s.State != models.StateOpen) {
+			t.Errorf("Unexpected summary %+v", s)
+		}
+	}
+}
+
+func TestRead_SkipsTruncatedFinalLine(t *testing.T) {
+	data := `{"time":"2025-03-03T15:00:00Z","kind":"order_placed","position_id":"a"}
+{"time":"2025-03-03T15:01:00Z","kind":"order_fil`
+	entries, err := Read(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Expected a truncated final line to be skipped, got %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected 1 entry, got %d", len(entries))
+	}
+
+	corrupt := "not json\n" + `{"time":"2025-03-03T15:00:00Z","kind":"order_placed"}`
+	if _, err := Read(strings.NewReader(corrupt)); err == nil {
+		t.Error("Expected a malformed line before the end to be an error")
+	}
+}
+
+func TestMatchPosition(t *testing.T) {
+	entries := []Entry{{PositionID: "abc12345-1"}, {PositionID: "abc12399-2"}, {PositionID: "def00000-3"}}
+
+	if id, err := MatchPosition(entries, "def"); err != nil || id != "def00000-3" {
+		t.Errorf("Expected unique prefix to match, got %q (%v)", id, err)
+	}
+	if _, err := MatchPosition(entries, "abc"); err == nil {
+		t.Error("Expected an ambiguous prefix to fail")
+	}
+	if _, err := MatchPosition(entries, "zzz"); err == nil {
+		t.Error("Expected an unknown prefix to fail")
+	}
+}
+
+func TestNilJournalRecordsNothing(t *testing.T) {
+	var j *Journal
+	j.SetCorrelationID("x")
+	j.NoteSpot("SPY", 450)
+	j.Record(Entry{Kind: KindOrderPlaced})
+	if err := j.Close(); err != nil {
+		t.Errorf("Expected nil journal Close to succeed, got %v", err)
+	}
+	if _, err := os.Stat("journal.jsonl"); !os.IsNotExist(err) {
+		t.Error("Expected no file to be written")
+	}
+}
+
+func TestListenersReceiveEntriesWithoutAFile(t *testing.T) {
+	j := New(nil)
+	j.SetCorrelationID("cycle-1")
+	j.NoteSpot("SPY", 451.25)
+
+	var got []Entry
+	j.AddListener(func(e Entry) { got = append(got, e) })
+	j.Record(Entry{Kind: KindOrderPlaced, PositionID: "pos-1", Symbol: "SPY", OrderID: "42"})
+
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 entry passed to the listener, got %d", len(got))
+	}
+	if got[0].CorrelationID != "cycle-1" || got[0].Spot != 451.25 || got[0].Time.IsZero() {
+		t.Errorf("Expected listener entry to be filled in like a written one, got %+v", got[0])
+	}
+	if err := j.Close(); err != nil {
+		t.Errorf("Expected Close without a file to succeed, got %v", err)
+	}
+}
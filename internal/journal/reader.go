@@ -0,0 +1,179 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+)
+
+// maxLineBytes bounds a single journal line; entries are a few hundred bytes
+const maxLineBytes = 1 << 20
+
+// ReadFile reads every entry in the journal at path
+func ReadFile(path string) ([]Entry, error) {
+	f, err := os.Open(path) // #nosec G304 - path is chosen by the operator
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return Read(f)
+}
+
+// Read decodes journal entries from r. A malformed final line, as left by a crash in the
+// middle of a write, is skipped; a malformed line anywhere else is an error.
+func Read(r io.Reader) ([]Entry, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+
+	var entries []Entry
+	var pending error
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if pending != nil {
+			return nil, pending
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			pending = fmt.Errorf("journal line %d: %w", lineNo, err)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Timeline returns the entries for one position in time order
+func Timeline(entries []Entry, positionID string) []Entry {
+	var timeline []Entry
+	for _, e := range entries {
+		if e.PositionID == positionID {
+			timeline = append(timeline, e)
+		}
+	}
+	sort.SliceStable(timeline, func(i, k int) bool {
+		return timeline[i].Time.Before(timeline[k].Time)
+	})
+	return timeline
+}
+
+// PositionSummary is what the journal knows about one position
+type PositionSummary struct {
+	PositionID string
+	Symbol     string
+	Events     int
+	First      time.Time
+	Last       time.Time
+	State      models.PositionState // State after the latest recorded transition
+}
+
+// Positions summarizes every position in entries, most recently active first
+func Positions(entries []Entry) []PositionSummary {
+	byID := make(map[string]*PositionSummary)
+	lastTransition := make(map[string]time.Time)
+	for _, e := range entries {
+		if e.PositionID == "" {
+			continue
+		}
+		s, ok := byID[e.PositionID]
+		if !ok {
+			s = &PositionSummary{PositionID: e.PositionID, First: e.Time}
+			byID[e.PositionID] = s
+		}
+		s.Events++
+		if e.Symbol != "" {
+			s.Symbol = e.Symbol
+		}
+		if e.Time.Before(s.First) {
+			s.First = e.Time
+		}
+		if e.Time.After(s.Last) {
+			s.Last = e.Time
+		}
+		if e.Kind == KindTransition && !e.Time.Before(lastTransition[e.PositionID]) {
+			lastTransition[e.PositionID] = e.Time
+			s.State = e.To
+		}
+	}
+
+	summaries := make([]PositionSummary, 0, len(byID))
+	for _, s := range byID {
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, k int) bool {
+		return summaries[i].Last.After(summaries[k].Last)
+	})
+	return summaries
+}
+
+// MatchPosition resolves a full or prefix position ID against the journal, as printed
+// in log lines that shorten IDs. It fails when the prefix is unknown or ambiguous.
+func MatchPosition(entries []Entry, prefix string) (string, error) {
+	var matches []string
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if e.PositionID == prefix {
+			return prefix, nil
+		}
+		if strings.HasPrefix(e.PositionID, prefix) && !seen[e.PositionID] {
+			seen[e.PositionID] = true
+			matches = append(matches, e.PositionID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no position matching %q in journal", prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%q matches %d positions: %s", prefix, len(matches), strings.Join(matches, ", "))
+	}
+}
+
+// String formats the entry as one timeline line
+func (e Entry) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %-18s", e.Time.UTC().Format("2006-01-02 15:04:05"), e.Kind)
+	if e.Kind == KindTransition {
+		fmt.Fprintf(&b, " %s -> %s", e.From, e.To)
+		if e.Condition != "" {
+			fmt.Fprintf(&b, " (%s)", e.Condition)
+		}
+	}
+	if e.OrderID != "" {
+		fmt.Fprintf(&b, " order=%s", e.OrderID)
+	}
+	if e.Quantity != 0 {
+		fmt.Fprintf(&b, " qty=%d", e.Quantity)
+	}
+	if e.Price != 0 {
+		fmt.Fprintf(&b, " price=%.2f", e.Price)
+	}
+	if e.Spot != 0 {
+		fmt.Fprintf(&b, " spot=%.2f", e.Spot)
+	}
+	if e.PnL != 0 {
+		fmt.Fprintf(&b, " pnl=%.2f", e.PnL)
+	}
+	if e.Detail != "" {
+		fmt.Fprintf(&b, " %s", e.Detail)
+	}
+	if e.CorrelationID != "" {
+		fmt.Fprintf(&b, " [%s]", e.CorrelationID)
+	}
+	return b.String()
+}
@@ -0,0 +1,235 @@
+package metrics
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/journal"
+)
+
+// Circuit breaker states reported by SetCircuitBreakerState
+var breakerStates = []string{"closed", "half-open", "open"}
+
+// maxTrackedOrders bounds the working orders remembered for fill latency
+const maxTrackedOrders = 256
+
+// Recorder holds the bot's metrics. A nil *Recorder is valid and records nothing, so
+// callers can hold one unconditionally when metrics are disabled.
+type Recorder struct {
+	registry *Registry
+
+	cycleDuration      *Histogram
+	lastCycle          *Gauge
+	brokerRequests     *Counter
+	brokerLatency      *Histogram
+	breakerState       *Gauge
+	openPositions      *Gauge
+	unrealizedPnL      *Gauge
+	buyingPower        *Gauge
+	fillLatency        *Histogram
+	reconciliations    *Counter
+	reconcileDecisions *Counter
+	dashboardFailures  *Counter
+
+	mu     sync.Mutex
+	placed map[string]placedOrder // Working entry and exit orders by ID
+}
+
+type placedOrder struct {
+	kind string
+	at   time.Time
+}
+
+// NewRecorder registers the bot's metrics on a new registry
+func NewRecorder() *Recorder {
+	r := NewRegistry()
+	rec := &Recorder{
+		registry: r,
+		cycleDuration: r.Histogram("strangler_trading_cycle_duration_seconds",
+			"Time taken by a trading cycle.",
+			[]float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}),
+		lastCycle: r.Gauge("strangler_trading_cycle_last_timestamp_seconds",
+			"Unix time the last trading cycle finished."),
+		brokerRequests: r.Counter("strangler_broker_requests_total",
+			"Broker API requests by endpoint and HTTP status; status is \"error\" when no response arrived.",
+			"endpoint", "status"),
+		brokerLatency: r.Histogram("strangler_broker_request_duration_seconds",
+			"Broker API request latency by endpoint and HTTP status.",
+			[]float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+			"endpoint", "status"),
+		breakerState: r.Gauge("strangler_broker_circuit_breaker_state",
+			"1 for the broker circuit breaker's current state, 0 for the others.",
+			"state"),
+		openPositions: r.Gauge("strangler_open_positions",
+			"Positions currently tracked and not closed."),
+		unrealizedPnL: r.Gauge("strangler_unrealized_pnl_dollars",
+			"Unrealized P&L of open positions at the last stop-loss check, gross of fees."),
+		buyingPower: r.Gauge("strangler_option_buying_power_dollars",
+			"Option buying power at the last trading cycle that checked it."),
+		fillLatency: r.Histogram("strangler_order_fill_latency_seconds",
+			"Time from placing an entry or exit order to its fill.",
+			[]float64{1, 5, 15, 30, 60, 120, 300, 600, 1800},
+			"order"),
+		reconciliations: r.Counter("strangler_reconciliations_total",
+			"Reconciliations with the broker by result.",
+			"result"),
+		reconcileDecisions: r.Counter("strangler_reconciliation_decisions_total",
+			"Positions changed by reconciliation, by decision.",
+			"decision"),
+		dashboardFailures: r.Counter("strangler_dashboard_server_failures_total",
+			"Times the dashboard server stopped with an error."),
+		placed: make(map[string]placedOrder),
+	}
+
+	// Series that may stay at zero for a long time are exported from the start
+	rec.reconciliations.Add(0, "ok")
+	rec.reconciliations.Add(0, "failed")
+	rec.dashboardFailures.Add(0)
+	return rec
+}
+
+// Handler serves the metrics to Prometheus scrapes
+func (r *Recorder) Handler() http.Handler {
+	if r == nil {
+		return http.NotFoundHandler()
+	}
+	return r.registry
+}
+
+// OnScrape runs fn before every scrape, to set gauges read from storage or the broker
+func (r *Recorder) OnScrape(fn func()) {
+	if r == nil {
+		return
+	}
+	r.registry.OnScrape(fn)
+}
+
+// ObserveCycle records a trading cycle that took elapsed and finished at end
+func (r *Recorder) ObserveCycle(end time.Time, elapsed time.Duration) {
+	if r == nil {
+		return
+	}
+	r.cycleDuration.Observe(elapsed.Seconds())
+	r.lastCycle.Set(float64(end.UnixNano()) / 1e9)
+}
+
+// ObserveBrokerRequest records one broker API request; it matches broker.RequestObserver
+func (r *Recorder) ObserveBrokerRequest(endpoint, status string, elapsed time.Duration) {
+	if r == nil {
+		return
+	}
+	r.brokerRequests.Inc(endpoint, status)
+	r.brokerLatency.Observe(elapsed.Seconds(), endpoint, status)
+}
+
+// SetCircuitBreakerState marks state as the broker circuit breaker's current state
+func (r *Recorder) SetCircuitBreakerState(state string) {
+	if r == nil {
+		return
+	}
+	known := false
+	for _, s := range breakerStates {
+		if s == state {
+			known = true
+			r.breakerState.Set(1, s)
+		} else {
+			r.breakerState.Set(0, s)
+		}
+	}
+	if !known {
+		r.breakerState.Set(1, state)
+	}
+}
+
+// SetOpenPositions records the number of open positions
+func (r *Recorder) SetOpenPositions(n int) {
+	if r == nil {
+		return
+	}
+	r.openPositions.Set(float64(n))
+}
+
+// SetUnrealizedPnL records the total unrealized P&L of open positions in dollars
+func (r *Recorder) SetUnrealizedPnL(pnl float64) {
+	if r == nil {
+		return
+	}
+	r.unrealizedPnL.Set(pnl)
+}
+
+// SetBuyingPower records the option buying power in dollars
+func (r *Recorder) SetBuyingPower(bp float64) {
+	if r == nil {
+		return
+	}
+	r.buyingPower.Set(bp)
+}
+
+// ReconcileRun records a reconciliation with the broker and whether it failed
+func (r *Recorder) ReconcileRun(err error) {
+	if r == nil {
+		return
+	}
+	if err != nil {
+		r.reconciliations.Inc("failed")
+		return
+	}
+	r.reconciliations.Inc("ok")
+}
+
+// ReconcileDecision records a change reconciliation made, e.g. "phantom_removed"
+func (r *Recorder) ReconcileDecision(decision string) {
+	if r == nil {
+		return
+	}
+	r.reconcileDecisions.Inc(decision)
+}
+
+// DashboardServerFailed records the dashboard server stopping with an error
+func (r *Recorder) DashboardServerFailed() {
+	if r == nil {
+		return
+	}
+	r.dashboardFailures.Inc()
+}
+
+// ObserveJournal measures fill latency from the order events the bot journals. Register
+// it with journal.AddListener.
+func (r *Recorder) ObserveJournal(e journal.Entry) {
+	if r == nil || e.OrderID == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch e.Kind {
+	case journal.KindOrderPlaced:
+		kind := fillKind(e.Detail)
+		if kind != "" && len(r.placed) < maxTrackedOrders {
+			r.placed[e.OrderID] = placedOrder{kind: kind, at: e.Time}
+		}
+	case journal.KindOrderFilled:
+		if placed, ok := r.placed[e.OrderID]; ok {
+			delete(r.placed, e.OrderID)
+			if latency := e.Time.Sub(placed.at); latency >= 0 {
+				r.fillLatency.Observe(latency.Seconds(), placed.kind)
+			}
+		}
+	case journal.KindOrderPartialFill, journal.KindOrderCanceled, journal.KindOrderFailed:
+		delete(r.placed, e.OrderID)
+	}
+}
+
+// fillKind labels the orders the bot waits on to fill, from their journal detail.
+// Resting profit-target orders and adjustment rolls are not measured.
+func fillKind(detail string) string {
+	switch {
+	case detail == "entry":
+		return "entry"
+	case strings.HasPrefix(detail, "exit"):
+		return "exit"
+	}
+	return ""
+}
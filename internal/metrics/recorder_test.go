@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/journal"
+)
+
+func TestRecorder_NilRecordsNothing(t *testing.T) {
+	var rec *Recorder
+	rec.ObserveCycle(time.Now(), time.Second)
+	rec.ObserveBrokerRequest("/markets/quotes", "200", time.Millisecond)
+	rec.SetCircuitBreakerState("open")
+	rec.ReconcileRun(nil)
+	rec.ObserveJournal(journal.Entry{Kind: journal.KindOrderPlaced, OrderID: "1"})
+	if rec.Handler() == nil {
+		t.Fatal("a nil recorder should still return a handler")
+	}
+}
+
+func TestRecorder_FillLatencyFromJournal(t *testing.T) {
+	rec := NewRecorder()
+	placed := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+
+	rec.ObserveJournal(journal.Entry{Kind: journal.KindOrderPlaced, OrderID: "11", Time: placed, Detail: "entry"})
+	rec.ObserveJournal(journal.Entry{Kind: journal.KindOrderPlaced, OrderID: "12", Time: placed, Detail: "exit (stop_loss)"})
+	rec.ObserveJournal(journal.Entry{Kind: journal.KindOrderPlaced, OrderID: "13", Time: placed, Detail: "profit_target"})
+	rec.ObserveJournal(journal.Entry{Kind: journal.KindOrderFilled, OrderID: "11", Time: placed.Add(20 * time.Second)})
+	rec.ObserveJournal(journal.Entry{Kind: journal.KindOrderFailed, OrderID: "12", Time: placed.Add(time.Minute)})
+	rec.ObserveJournal(journal.Entry{Kind: journal.KindOrderFilled, OrderID: "13", Time: placed.Add(time.Hour)})
+
+	text := scrape(t, rec.registry)
+	assertLines(t, text,
+		`strangler_order_fill_latency_seconds_bucket{order="entry",le="15"} 0`,
+		`strangler_order_fill_latency_seconds_bucket{order="entry",le="30"} 1`,
+		`strangler_order_fill_latency_seconds_count{order="entry"} 1`,
+	)
+	if len(rec.placed) != 0 {
+		t.Errorf("settled orders should be forgotten, %d remain", len(rec.placed))
+	}
+}
+
+func TestRecorder_CircuitBreakerStateIsOneHot(t *testing.T) {
+	rec := NewRecorder()
+	rec.SetCircuitBreakerState("half-open")
+
+	assertLines(t, scrape(t, rec.registry),
+		`strangler_broker_circuit_breaker_state{state="closed"} 0`,
+		`strangler_broker_circuit_breaker_state{state="half-open"} 1`,
+		`strangler_broker_circuit_breaker_state{state="open"} 0`,
+	)
+}
+
+func TestRecorder_ReconciliationOutcomes(t *testing.T) {
+	rec := NewRecorder()
+	assertLines(t, scrape(t, rec.registry), `strangler_reconciliations_total{result="failed"} 0`)
+
+	rec.ReconcileRun(nil)
+	rec.ReconcileRun(errors.New("broker down"))
+	rec.ReconcileDecision("phantom_removed")
+
+	assertLines(t, scrape(t, rec.registry),
+		`strangler_reconciliations_total{result="ok"} 1`,
+		`strangler_reconciliations_total{result="failed"} 1`,
+		`strangler_reconciliation_decisions_total{decision="phantom_removed"} 1`,
+	)
+}
+
+func TestRecorder_CycleAndBrokerMetrics(t *testing.T) {
+	rec := NewRecorder()
+	end := time.Unix(1772463600, 0)
+	rec.ObserveCycle(end, 1500*time.Millisecond)
+	rec.ObserveBrokerRequest("/accounts/:account/orders", "201", 300*time.Millisecond)
+
+	assertLines(t, scrape(t, rec.registry),
+		`strangler_trading_cycle_last_timestamp_seconds 1.7724636e+09`,
+		`strangler_trading_cycle_duration_seconds_bucket{le="2.5"} 1`,
+		`strangler_broker_requests_total{endpoint="/accounts/:account/orders",status="201"} 1`,
+		`strangler_broker_request_duration_seconds_bucket{endpoint="/accounts/:account/orders",status="201",le="0.25"} 0`,
+		`strangler_broker_request_duration_seconds_bucket{endpoint="/accounts/:account/orders",status="201",le="0.5"} 1`,
+	)
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func scrape(t *testing.T, r *Registry) string {
+	t.Helper()
+	var b strings.Builder
+	if err := r.WriteText(&b); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	return b.String()
+}
+
+func assertLines(t *testing.T, text string, want ...string) {
+	t.Helper()
+	for _, line := range want {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("missing line %q in:\n%s", line, text)
+		}
+	}
+}
+
+func TestRegistry_WritesTextFormat(t *testing.T) {
+	r := NewRegistry()
+	requests := r.Counter("requests_total", "Requests by path.\nSecond line.", "path", "status")
+	requests.Inc("/a", "200")
+	requests.Add(2, "/a", "200")
+	requests.Inc(`/b"q\`, "500")
+	temp := r.Gauge("temperature", "Current temperature.")
+	temp.Set(-1.5)
+
+	text := scrape(t, r)
+	assertLines(t, text,
+		`# HELP requests_total Requests by path.\nSecond line.`,
+		`# TYPE requests_total counter`,
+		`requests_total{path="/a",status="200"} 3`,
+		`requests_total{path="/b\"q\\",status="500"} 1`,
+		`# TYPE temperature gauge`,
+		`temperature -1.5`,
+	)
+	if strings.Index(text, "requests_total") > strings.Index(text, "temperature") {
+		t.Errorf("families should be written in registration order:\n%s", text)
+	}
+}
+
+func TestRegistry_HistogramBucketsAreCumulative(t *testing.T) {
+	r := NewRegistry()
+	h := r.Histogram("latency_seconds", "Latency.", []float64{1, 0.5}, "op")
+	for _, v := range []float64{0.2, 0.7, 3} {
+		h.Observe(v, "get")
+	}
+
+	assertLines(t, scrape(t, r),
+		`# TYPE latency_seconds histogram`,
+		`latency_seconds_bucket{op="get",le="0.5"} 1`,
+		`latency_seconds_bucket{op="get",le="1"} 2`,
+		`latency_seconds_bucket{op="get",le="+Inf"} 3`,
+		`latency_seconds_sum{op="get"} 3.9`,
+		`latency_seconds_count{op="get"} 3`,
+	)
+}
+
+func TestRegistry_OnScrapeRunsBeforeWriting(t *testing.T) {
+	r := NewRegistry()
+	g := r.Gauge("scraped", "Set on scrape.")
+	scrapes := 0
+	r.OnScrape(func() {
+		scrapes++
+		g.Set(float64(scrapes))
+	})
+
+	scrape(t, r)
+	assertLines(t, scrape(t, r), "scraped 2")
+}
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("hits_total", "Hits.").Inc()
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if got := rec.Header().Get("Content-Type"); got != contentType {
+		t.Errorf("Content-Type = %q, want %q", got, contentType)
+	}
+	assertLines(t, rec.Body.String(), "hits_total 1")
+}
+
+func TestRegistry_WrongLabelCountPanics(t *testing.T) {
+	r := NewRegistry()
+	c := r.Counter("labelled_total", "Labelled.", "a")
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a missing label value")
+		}
+	}()
+	c.Inc()
+}
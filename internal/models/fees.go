@@ -0,0 +1,20 @@
+package models
+
+import "math"
+
+// FeeSchedule is what the broker charges to fill an option order.
+type FeeSchedule struct {
+	PerContract float64 // Commission per option contract, each leg counted
+	PerLeg      float64 // Regulatory and exchange fees per leg of an order
+	MinPerOrder float64 // Least a filled order is charged
+}
+
+// OrderFees returns the charge for filling quantity contracts on each of legs legs of one order.
+// Nothing is charged for an order that filled nothing.
+func (f FeeSchedule) OrderFees(legs, quantity int) float64 {
+	if legs <= 0 || quantity <= 0 {
+		return 0
+	}
+	fees := f.PerContract*float64(legs*quantity) + f.PerLeg*float64(legs)
+	return math.Max(fees, f.MinPerOrder)
+}
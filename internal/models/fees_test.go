@@ -0,0 +1,31 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFeeSchedule_OrderFees(t *testing.T) {
+	schedule := FeeSchedule{PerContract: 0.35, PerLeg: 0.10, MinPerOrder: 1.00}
+	tests := []struct {
+		name           string
+		legs, quantity int
+		want           float64
+	}{
+		{"strangle of 3", 2, 3, 2.30},
+		{"minimum applies to small orders", 2, 1, 1.00},
+		{"four-leg punt", 4, 2, 3.20},
+		{"nothing filled", 2, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schedule.OrderFees(tt.legs, tt.quantity); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("OrderFees(%d, %d) = %.4f, want %.2f", tt.legs, tt.quantity, got, tt.want)
+			}
+		})
+	}
+
+	if got := (FeeSchedule{}).OrderFees(2, 5); got != 0 {
+		t.Errorf("Expected a fee-free schedule to charge nothing, got %.2f", got)
+	}
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// MachineState is the persisted form of a StateMachine. The current state is not repeated
+// here; it is the position's canonical State.
+type MachineState struct {
+	PreviousState       PositionState         `json:"previous_state,omitempty"`
+	TransitionTime      time.Time             `json:"transition_time"`
+	TransitionCounts    map[PositionState]int `json:"transition_counts,omitempty"`
+	FourthDownOption    FourthDownOption      `json:"fourth_down_option,omitempty"`
+	FourthDownStartTime time.Time             `json:"fourth_down_start_time,omitempty"`
+	PuntCount           int                   `json:"punt_count,omitempty"`
+	MaxAdjustments      int                   `json:"max_adjustments"`
+	MaxTimeRolls        int                   `json:"max_time_rolls"`
+}
+
+// Snapshot returns the machine's counters, limits and Fourth Down metadata for persistence
+func (sm *StateMachine) Snapshot() MachineState {
+	counts := make(map[PositionState]int, len(sm.transitionCount))
+	for state, count := range sm.transitionCount {
+		counts[state] = count
+	}
+	return MachineState{
+		PreviousState:       sm.previousState,
+		TransitionTime:      sm.transitionTime,
+		TransitionCounts:    counts,
+		FourthDownOption:    sm.fourthDownOption,
+		FourthDownStartTime: sm.fourthDownStartTime,
+		PuntCount:           sm.puntCount,
+		MaxAdjustments:      sm.maxAdjustments,
+		MaxTimeRolls:        sm.maxTimeRolls,
+	}
+}
+
+// RestoreStateMachine rebuilds a machine in state from a persisted snapshot and checks it
+// with ValidateStateConsistency
+func RestoreStateMachine(state PositionState, snapshot MachineState) (*StateMachine, error) {
+	sm := &StateMachine{
+		currentState:        state,
+		previousState:       snapshot.PreviousState,
+		transitionTime:      snapshot.TransitionTime,
+		transitionCount:     make(map[PositionState]int, len(snapshot.TransitionCounts)),
+		fourthDownOption:    snapshot.FourthDownOption,
+		fourthDownStartTime: snapshot.FourthDownStartTime,
+		puntCount:           snapshot.PuntCount,
+		maxAdjustments:      snapshot.MaxAdjustments,
+		maxTimeRolls:        snapshot.MaxTimeRolls,
+	}
+	if sm.previousState == "" {
+		sm.previousState = state
+	}
+	for s, count := range snapshot.TransitionCounts {
+		if count < 0 {
+			return nil, fmt.Errorf("negative transition count %d for state %s", count, s)
+		}
+		sm.transitionCount[s] = count
+	}
+	if err := sm.ValidateStateConsistency(); err != nil {
+		return nil, err
+	}
+	return sm, nil
+}
+
+// positionJSON has Position's fields without its JSON methods
+type positionJSON Position
+
+// MarshalJSON writes the position with its state machine's snapshot, so transition
+// limits and Fourth Down timing survive a restart
+func (p Position) MarshalJSON() ([]byte, error) {
+	var machine *MachineState
+	if p.StateMachine != nil {
+		snapshot := p.StateMachine.Snapshot()
+		machine = &snapshot
+	}
+	return json.Marshal(struct {
+		positionJSON
+		Machine *MachineState `json:"machine,omitempty"`
+	}{positionJSON(p), machine})
+}
+
+// fallbackStateMachine rebuilds a machine in state when its snapshot fails validation. The
+// persisted transition counts are kept as a floor, so a used adjustment or roll stays used,
+// along with the Fourth Down metadata and any positive limits.
+func fallbackStateMachine(state PositionState, snapshot MachineState) *StateMachine {
+	sm := NewStateMachineFromState(state)
+	for s, count := range snapshot.TransitionCounts {
+		if count > sm.transitionCount[s] {
+			sm.transitionCount[s] = count
+		}
+	}
+	if snapshot.MaxAdjustments > 0 {
+		sm.maxAdjustments = snapshot.MaxAdjustments
+	}
+	if snapshot.MaxTimeRolls > 0 {
+		sm.maxTimeRolls = snapshot.MaxTimeRolls
+	}
+	sm.fourthDownOption = snapshot.FourthDownOption
+	sm.fourthDownStartTime = snapshot.FourthDownStartTime
+	sm.puntCount = snapshot.PuntCount
+	return sm
+}
+
+// UnmarshalJSON reads a position and restores its state machine from the persisted
+// snapshot. Positions written before snapshots existed get a machine rebuilt from State
+// on first use; a snapshot that fails validation is logged and replaced by one rebuilt
+// from State, rather than failing the whole load.
+func (p *Position) UnmarshalJSON(data []byte) error {
+	var decoded struct {
+		positionJSON
+		Machine *MachineState `json:"machine,omitempty"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*p = Position(decoded.positionJSON)
+	p.StateMachine = nil
+
+	if decoded.Machine != nil {
+		sm, err := RestoreStateMachine(p.State, *decoded.Machine)
+		if err != nil {
+			log.Printf("CRITICAL: position %s has an inconsistent state machine, rebuilding it from state %s "+
+				"and keeping its transition counts: %v", p.ID, p.State, err)
+			sm = fallbackStateMachine(p.State, *decoded.Machine)
+		}
+		p.StateMachine = sm
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// ExecutionSide tells whether a worked order opened or closed a position.
+type ExecutionSide string
+
+const (
+	// ExecutionEntry is a sell-to-open credit order
+	ExecutionEntry ExecutionSide = "entry"
+	// ExecutionExit is a buy-to-close debit order
+	ExecutionExit ExecutionSide = "exit"
+)
+
+// OrderExecution records how a limit order was walked from mid toward the natural price
+// until it filled. Prices are per spread.
+type OrderExecution struct {
+	Side         ExecutionSide `json:"side"`
+	OrderID      string        `json:"order_id"`
+	MidPrice     float64       `json:"mid_price"`     // Mid when the walk started
+	NaturalPrice float64       `json:"natural_price"` // Sum of bids (entry) or asks (exit) when the walk started
+	LimitPrice   float64       `json:"limit_price"`   // Limit of the order that filled
+	FillPrice    float64       `json:"fill_price"`
+	Steps        int           `json:"steps"`       // Number of times the order was re-priced
+	Improvement  float64       `json:"improvement"` // Fill better than natural, per spread; negative is worse
+	StartedAt    time.Time     `json:"started_at"`
+	FilledAt     time.Time     `json:"filled_at"`
+}
+
+// PriceImprovement returns how much better fill is than natural for the given side, per spread
+func PriceImprovement(side ExecutionSide, natural, fill float64) float64 {
+	if side == ExecutionEntry {
+		return fill - natural
+	}
+	return natural - fill
+}
+
+// LegFill is what one leg of an order actually executed.
+type LegFill struct {
+	OptionSymbol string  `json:"option_symbol"`
+	Side         string  `json:"side"`
+	ExecQuantity float64 `json:"exec_quantity"`
+	AvgFillPrice float64 `json:"avg_fill_price"`
+}
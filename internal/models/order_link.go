@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// OrderRole identifies what a linked child order does for its position.
+type OrderRole string
+
+const (
+	// OrderRoleProfitTarget is a resting GTC buy-to-close at the profit target
+	OrderRoleProfitTarget OrderRole = "profit_target"
+	// OrderRoleStopLoss is the close order sent when the position monitor stops a position out
+	OrderRoleStopLoss OrderRole = "stop_loss"
+)
+
+// OrderLinkStatus is the lifecycle state of a linked order as last seen by the bot.
+type OrderLinkStatus string
+
+const (
+	// OrderLinkWorking means the order is live at the broker
+	OrderLinkWorking OrderLinkStatus = "working"
+	// OrderLinkFilled means the order filled
+	OrderLinkFilled OrderLinkStatus = "filled"
+	// OrderLinkCancelled means the order was canceled, expired or rejected without filling
+	OrderLinkCancelled OrderLinkStatus = "cancelled"
+)
+
+// OrderLink records a child order placed on behalf of a position, such as its resting
+// profit target. Links are persisted with the position so they survive restarts.
+type OrderLink struct {
+	Role       OrderRole       `json:"role"`
+	OrderID    string          `json:"order_id"`
+	Status     OrderLinkStatus `json:"status"`
+	LimitPrice float64         `json:"limit_price"` // Debit limit per spread
+	PlacedAt   time.Time       `json:"placed_at"`
+	UpdatedAt  time.Time       `json:"updated_at,omitempty"`
+	LastError  string          `json:"last_error,omitempty"` // Most recent cancel failure, cleared on success
+}
+
+// WorkingOrder returns the position's working linked order with the given role, or nil.
+// The returned pointer refers into the position, so updates through it are kept.
+func (p *Position) WorkingOrder(role OrderRole) *OrderLink {
+	for i := len(p.LinkedOrders) - 1; i >= 0; i-- {
+		if p.LinkedOrders[i].Role == role && p.LinkedOrders[i].Status == OrderLinkWorking {
+			return &p.LinkedOrders[i]
+		}
+	}
+	return nil
+}
+
+// LinkOrder records a newly placed child order as working
+func (p *Position) LinkOrder(role OrderRole, orderID string, limitPrice float64) {
+	p.LinkedOrders = append(p.LinkedOrders, OrderLink{
+		Role:       role,
+		OrderID:    orderID,
+		Status:     OrderLinkWorking,
+		LimitPrice: limitPrice,
+		PlacedAt:   time.Now().UTC(),
+	})
+}
+
+// SetStatus updates the link status and clears any recorded error
+func (l *OrderLink) SetStatus(status OrderLinkStatus) {
+	l.Status = status
+	l.LastError = ""
+	l.UpdatedAt = time.Now().UTC()
+}
@@ -9,6 +9,10 @@ import (
 
 const sharesPerContract = 100.0
 
+// AdjustmentOrderPending marks a position claimed for a roll or punt whose order ID is
+// not known yet
+const AdjustmentOrderPending = "pending"
+
 // IVReading represents a single implied volatility reading for a symbol on a specific date
 type IVReading struct {
 	Symbol    string    `json:"symbol"`
@@ -26,16 +30,24 @@ type Position struct {
 	Symbol         string        `json:"symbol"`
 	EntryOrderID   string        `json:"entry_order_id,omitempty"`
 	ExitOrderID    string        `json:"exit_order_id,omitempty"`
+	AdjustmentOrderID string     `json:"adjustment_order_id,omitempty"` // Roll or punt order working against the position
 	ExitReason     string        `json:"exit_reason,omitempty"`
 	Expiration     time.Time     `json:"expiration"`
 	EntryDate      time.Time     `json:"entry_date,omitempty"`
 	ExitDate       time.Time     `json:"exit_date,omitempty"`
 	LastChecked    time.Time     `json:"last_checked,omitempty"` // Last time reconciliation checked this position
+	LinkedOrders   []OrderLink   `json:"linked_orders,omitempty"` // Child orders such as the resting profit target
+	Executions     []OrderExecution `json:"executions,omitempty"` // Price walks worked to a fill, one per entry or exit
+	EntryFills     []LegFill     `json:"entry_fills,omitempty"` // Per-leg quantity and price the entry order executed
 	CreditReceived   float64       `json:"credit_received"`
 	EntryLimitPrice float64       `json:"entry_limit_price"`
 	EntryIV         float64       `json:"entry_iv"`
 	EntrySpot       float64       `json:"entry_spot"`
 	CurrentPnL     float64       `json:"current_pnl"`
+	DayStartDate   string        `json:"day_start_date,omitempty"` // New York trading day DayStartPnL was marked for
+	DayStartPnL    float64       `json:"day_start_pnl,omitempty"`  // Unrealized P&L at the start of DayStartDate, zero if entered that day
+	RealizedPnL    float64       `json:"realized_pnl,omitempty"` // P&L of contracts already bought back by partial exit fills
+	Fees           float64       `json:"fees,omitempty"`         // Commissions and fees charged on every fill so far
 	CallStrike     float64       `json:"call_strike"`
 	PutStrike      float64       `json:"put_strike"`
 	Quantity       int           `json:"quantity"`
@@ -53,12 +65,14 @@ const (
 	AdjustmentDelta AdjustmentType = "delta"
 	// AdjustmentHedge indicates a hedging adjustment
 	AdjustmentHedge AdjustmentType = "hedge"
+	// AdjustmentHold records a deliberate decision to keep the position unchanged
+	AdjustmentHold AdjustmentType = "hold"
 )
 
 // Valid returns true if the AdjustmentType is one of the defined constants
 func (t AdjustmentType) Valid() bool {
 	switch t {
-	case AdjustmentRoll, AdjustmentDelta, AdjustmentHedge:
+	case AdjustmentRoll, AdjustmentDelta, AdjustmentHedge, AdjustmentHold:
 		return true
 	default:
 		return false
@@ -129,6 +143,7 @@ func NewPosition(id, symbol string, putStrike, callStrike float64, expiration ti
 
 // TransitionState moves the position to a new state
 func (p *Position) TransitionState(to PositionState, condition string) error {
+	from := p.ensureMachine().GetCurrentState()
 	err := p.ensureMachine().Transition(to, condition)
 	if err != nil {
 		return fmt.Errorf("position %s state transition failed: %w", p.ID, err)
@@ -159,6 +174,7 @@ func (p *Position) TransitionState(to PositionState, condition string) error {
 		p.EntryLimitPrice = 0 // Additional reset specific to error state
 	}
 
+	notifyTransition(p, from, condition)
 	return nil
 }
 
@@ -169,6 +185,9 @@ func (p *Position) clearNonActiveFields() {
 	p.ExitReason = ""
 	p.CreditReceived = 0
 	p.Quantity = 0
+	p.RealizedPnL = 0
+	p.Fees = 0
+	p.EntryFills = nil
 	p.Adjustments = make([]Adjustment, 0)
 }
 
@@ -185,6 +204,11 @@ func (p *Position) ensureMachine() *StateMachine {
 	return p.StateMachine
 }
 
+// HasWorkingAdjustment reports whether a roll or punt order may be working against the position
+func (p *Position) HasWorkingAdjustment() bool {
+	return p.AdjustmentOrderID != ""
+}
+
 // IsInManagement returns true if position is in football management states
 func (p *Position) IsInManagement() bool {
 	return p.ensureMachine().IsManagementState()
@@ -469,6 +493,24 @@ func (p *Position) Clone() Position {
 		clone.Adjustments = make([]Adjustment, len(p.Adjustments))
 		copy(clone.Adjustments, p.Adjustments)
 	}
+
+	// Deep copy the LinkedOrders slice
+	if p.LinkedOrders != nil {
+		clone.LinkedOrders = make([]OrderLink, len(p.LinkedOrders))
+		copy(clone.LinkedOrders, p.LinkedOrders)
+	}
+
+	// Deep copy the Executions slice
+	if p.Executions != nil {
+		clone.Executions = make([]OrderExecution, len(p.Executions))
+		copy(clone.Executions, p.Executions)
+	}
+
+	// Deep copy the EntryFills slice
+	if p.EntryFills != nil {
+		clone.EntryFills = make([]LegFill, len(p.EntryFills))
+		copy(clone.EntryFills, p.EntryFills)
+	}
 	
 	// Deep copy the StateMachine if it exists
 	if p.StateMachine != nil {
@@ -88,6 +88,8 @@ const (
 	ConditionRollUntested      = "roll_untested"
 	ConditionExecuteAdjustment = "execute_adjustment"
 	ConditionRollAsPunt        = "roll_as_punt"
+	ConditionStraddleFormed    = "straddle_formed"
+	ConditionStrangleInverted  = "strangle_inverted"
 
 	// Return from adjustments
 	ConditionAdjustmentComplete = "adjustment_complete"
@@ -143,10 +145,13 @@ var ValidTransitions = []StateTransition{
 	// Adjustment transitions
 	{StateSecondDown, StateAdjusting, ConditionRollUntested, "Rolling untested side"},
 	{StateThirdDown, StateAdjusting, ConditionExecuteAdjustment, "Executing adjustment strategy"},
+	{StateFourthDown, StateAdjusting, ConditionExecuteAdjustment, "Inverting strangle (Fourth Down Option A)"},
 	{StateFourthDown, StateRolling, ConditionRollAsPunt, "Rolling to new expiration as punt strategy"},
 
 	// Return from adjustments
 	{StateAdjusting, StateFirstDown, ConditionAdjustmentComplete, "Adjustment completed successfully"},
+	{StateAdjusting, StateThirdDown, ConditionStraddleFormed, "Untested side rolled to tested strike, managing as straddle"},
+	{StateAdjusting, StateFourthDown, ConditionStrangleInverted, "Untested side rolled past tested strike, managing inverted strangle"},
 	{StateRolling, StateFirstDown, ConditionRollComplete, "Time roll completed"},
 	{StateAdjusting, StateError, ConditionAdjustmentFailed, "Adjustment failed"},
 	{StateRolling, StateError, ConditionRollFailed, "Time roll failed"},
@@ -143,6 +143,59 @@ func TestStateMachine_FootballSystemFlow(t *testing.T) {
 	}
 }
 
+func TestStateMachine_ThirdDownStraddleFlow(t *testing.T) {
+	sm := NewStateMachineFromState(StateSecondDown)
+
+	steps := []struct {
+		to        PositionState
+		condition string
+	}{
+		{StateThirdDown, ConditionStrikeBreached},
+		{StateAdjusting, ConditionExecuteAdjustment},
+		{StateThirdDown, ConditionStraddleFormed},
+	}
+	for _, step := range steps {
+		if err := sm.Transition(step.to, step.condition); err != nil {
+			t.Fatalf("Transition to %s failed: %v", step.to, err)
+		}
+	}
+
+	if sm.GetManagementPhase() != 3 {
+		t.Errorf("Should remain in phase 3 after forming straddle, got %d", sm.GetManagementPhase())
+	}
+	if sm.GetTransitionCount(StateAdjusting) != 1 {
+		t.Errorf("Straddle should count as one adjustment, got %d", sm.GetTransitionCount(StateAdjusting))
+	}
+
+	// Straddle completion must not be usable to skip back to First Down
+	sm2 := NewStateMachineFromState(StateAdjusting)
+	if err := sm2.Transition(StateFirstDown, ConditionStraddleFormed); err == nil {
+		t.Error("straddle_formed should only return to Third Down")
+	}
+}
+
+func TestStateMachine_FourthDownInvertedStrangleFlow(t *testing.T) {
+	sm := NewStateMachineFromState(StateThirdDown)
+
+	if err := sm.Transition(StateFourthDown, ConditionAdjustmentFailed); err != nil {
+		t.Fatalf("Transition to fourth down failed: %v", err)
+	}
+	sm.SetFourthDownOption(OptionA)
+	if err := sm.Transition(StateAdjusting, ConditionExecuteAdjustment); err != nil {
+		t.Fatalf("Transition to adjusting failed: %v", err)
+	}
+	if err := sm.Transition(StateFourthDown, ConditionStrangleInverted); err != nil {
+		t.Fatalf("Return to fourth down failed: %v", err)
+	}
+
+	if sm.GetFourthDownOption() != OptionA {
+		t.Errorf("Fourth Down option should survive the adjustment, got %q", sm.GetFourthDownOption())
+	}
+	if sm.GetManagementPhase() != 4 {
+		t.Errorf("Should remain in phase 4 after inverting, got %d", sm.GetManagementPhase())
+	}
+}
+
 func TestStateMachine_AdjustmentLimits(t *testing.T) {
 	sm := NewStateMachine()
 
@@ -1274,7 +1327,7 @@ func TestPosition_CreditCalculationConsistency(t *testing.T) {
 	}
 }
 
-func TestPosition_JSONSerialization_ExcludesStateMachine(t *testing.T) {
+func TestPosition_JSONSerialization_PersistsStateMachine(t *testing.T) {
 	// Create a new position and transition it to Open state
 	pos := NewPosition("test-pos", "SPY", 400, 410, time.Now().AddDate(0, 0, 45), 1)
 
@@ -1331,9 +1384,14 @@ func TestPosition_JSONSerialization_ExcludesStateMachine(t *testing.T) {
 		t.Fatalf("Failed to unmarshal position from JSON: %v", err)
 	}
 
-	// Verify StateMachine is nil after deserialization (as expected)
-	if deserializedPos.StateMachine != nil {
-		t.Error("StateMachine should be nil after JSON deserialization")
+	// The state machine is restored from its persisted snapshot
+	if deserializedPos.StateMachine == nil {
+		t.Fatal("StateMachine should be restored after JSON deserialization")
+	}
+	if deserializedPos.StateMachine.GetCurrentState() != StateOpen ||
+		deserializedPos.StateMachine.GetPreviousState() != StateSubmitted ||
+		deserializedPos.StateMachine.GetTransitionCount(StateSubmitted) != 1 {
+		t.Errorf("Restored StateMachine does not match the original: %+v", deserializedPos.StateMachine.Snapshot())
 	}
 
 	// Verify persisted state is correct
@@ -1341,24 +1399,107 @@ func TestPosition_JSONSerialization_ExcludesStateMachine(t *testing.T) {
 		t.Errorf("Expected deserialized position state to be %s, got %s", StateOpen, deserializedPos.GetCurrentState())
 	}
 
+	// Positions written before machine snapshots existed rebuild the machine lazily
+	delete(obj, "machine")
+	legacyData, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("Failed to marshal legacy position: %v", err)
+	}
+	var legacyPos Position
+	if err := json.Unmarshal(legacyData, &legacyPos); err != nil {
+		t.Fatalf("Failed to unmarshal legacy position: %v", err)
+	}
+	if legacyPos.StateMachine != nil {
+		t.Error("StateMachine should be nil for a position without a persisted snapshot")
+	}
+
 	// Verify lazy initialization works - calling a method that uses StateMachine
 	// should initialize it from the persisted state
-	managementPhase := deserializedPos.GetManagementPhase()
+	managementPhase := legacyPos.GetManagementPhase()
 	if managementPhase != 0 { // Open state should have management phase 0
 		t.Errorf("Expected management phase 0 for Open state, got %d", managementPhase)
 	}
 
 	// Verify StateMachine is now initialized after lazy initialization
-	if deserializedPos.StateMachine == nil {
+	if legacyPos.StateMachine == nil {
 		t.Error("StateMachine should be initialized after lazy initialization")
 	}
 
 	// Verify the StateMachine has the correct state
-	if deserializedPos.StateMachine.GetCurrentState() != StateOpen {
+	if legacyPos.StateMachine.GetCurrentState() != StateOpen {
 		t.Errorf("StateMachine should be in %s state after lazy initialization, got %s",
-			StateOpen, deserializedPos.StateMachine.GetCurrentState())
+			StateOpen, legacyPos.StateMachine.GetCurrentState())
 	}
 }
 
 // Helper function to check if string contains substring
 func contains(s, substr string) bool { return strings.Contains(s, substr) }
+
+func TestPosition_JSONRoundTripKeepsTransitionLimits(t *testing.T) {
+	pos := NewPosition("limits-pos", "SPY", 400, 460, time.Now().AddDate(0, 0, 45), 1)
+	steps := []struct {
+		to        PositionState
+		condition string
+	}{
+		{StateSubmitted, ConditionOrderPlaced},
+		{StateOpen, ConditionOrderFilled},
+		{StateFirstDown, ConditionStartManagement},
+		{StateSecondDown, ConditionStrikeChallenged},
+		{StateThirdDown, ConditionStrikeBreached},
+		{StateFourthDown, ConditionAdjustmentFailed},
+		{StateRolling, ConditionRollAsPunt},
+		{StateFirstDown, ConditionRollComplete},
+	}
+	for _, step := range steps {
+		if err := pos.TransitionState(step.to, step.condition); err != nil {
+			t.Fatalf("Failed to transition to %s: %v", step.to, err)
+		}
+		if step.to == StateFourthDown {
+			pos.SetFourthDownOption(OptionC)
+		}
+	}
+	fourthDownStart := pos.StateMachine.Snapshot().FourthDownStartTime
+	if pos.CanRoll() {
+		t.Fatal("Expected the only time roll to be used up before the restart")
+	}
+
+	data, err := json.Marshal(pos)
+	if err != nil {
+		t.Fatalf("Failed to marshal position: %v", err)
+	}
+	var restored Position
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("Failed to unmarshal position: %v", err)
+	}
+	if restored.CanRoll() {
+		t.Error("Expected the used time roll to survive a restart")
+	}
+	if restored.GetFourthDownOption() != OptionC {
+		t.Errorf("Expected Fourth Down option %s to survive a restart, got %s", OptionC, restored.GetFourthDownOption())
+	}
+	if got := restored.StateMachine.Snapshot().FourthDownStartTime; !got.Equal(fourthDownStart) {
+		t.Errorf("Expected Fourth Down start %v, got %v", fourthDownStart, got)
+	}
+}
+
+func TestPosition_UnmarshalRebuildsInconsistentStateMachine(t *testing.T) {
+	data := `{"id":"bad","state":"first_down","machine":{"previous_state":"adjusting",` +
+		`"transition_time":"2025-03-03T15:00:00Z","transition_counts":{"first_down":1,"adjusting":5,"rolling":1},` +
+		`"fourth_down_option":"option_a","max_adjustments":3,"max_time_rolls":1}}`
+	var pos Position
+	if err := json.Unmarshal([]byte(data), &pos); err != nil {
+		t.Fatalf("Expected a position with an inconsistent machine to still load, got %v", err)
+	}
+	if pos.ID != "bad" || pos.GetCurrentState() != StateFirstDown {
+		t.Errorf("Expected position bad in %s, got %s in %s", StateFirstDown, pos.ID, pos.GetCurrentState())
+	}
+	if pos.CanAdjust() || pos.CanRoll() {
+		t.Error("Expected the persisted adjustment and roll counts to be kept as a floor")
+	}
+	if got := pos.StateMachine.GetTransitionCount(StateAdjusting); got != 5 {
+		t.Errorf("Expected adjustment count 5, got %d", got)
+	}
+	if pos.GetFourthDownOption() != OptionA {
+		t.Errorf("Expected Fourth Down option %s to be kept, got %s", OptionA, pos.GetFourthDownOption())
+	}
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// TradingHalt records a risk circuit breaker that blocks new entries for the rest of
+// a trading day. It is persisted so a restart during the session keeps the halt.
+type TradingHalt struct {
+	Date        string    `json:"date"` // New York trading day (YYYY-MM-DD) the halt applies to
+	Reason      string    `json:"reason"`
+	TriggeredAt time.Time `json:"triggered_at"`
+	DailyPnL    float64   `json:"daily_pnl"`  // Realized plus unrealized P&L when triggered, in dollars
+	LossLimit   float64   `json:"loss_limit"` // Dollar loss limit that was breached
+	Flattened   bool      `json:"flattened"`  // Whether open positions were closed when triggered
+}
+
+// ActiveOn reports whether the halt applies on the given trading day (YYYY-MM-DD)
+func (h *TradingHalt) ActiveOn(day string) bool {
+	return h != nil && h.Date == day
+}
+
+// EntryPause records an operator's request to stop opening positions. Unlike a
+// TradingHalt it does not expire; it lasts until the operator resumes entries.
+type EntryPause struct {
+	Reason   string    `json:"reason,omitempty"`
+	PausedAt time.Time `json:"paused_at"`
+	PausedBy string    `json:"paused_by,omitempty"` // Where the request came from, e.g. the dashboard client address
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"sync"
+	"time"
+)
+
+// TransitionEvent describes one successful position state transition
+type TransitionEvent struct {
+	PositionID string
+	Symbol     string
+	From       PositionState
+	To         PositionState
+	Condition  string
+	Time       time.Time
+	PnL        float64 // CurrentPnL plus RealizedPnL at the time of the transition
+	Quantity   int
+	OrderID    string // Entry order while entering, otherwise the exit order, if any
+}
+
+var (
+	transitionObserverMu sync.RWMutex
+	transitionObserver   func(TransitionEvent)
+)
+
+// SetTransitionObserver registers fn to be called after every successful
+// Position.TransitionState, e.g. to journal the transition. nil removes it.
+// fn runs synchronously on the transitioning goroutine and must not block.
+func SetTransitionObserver(fn func(TransitionEvent)) {
+	transitionObserverMu.Lock()
+	defer transitionObserverMu.Unlock()
+	transitionObserver = fn
+}
+
+// notifyTransition reports a completed transition of p from the given state
+func notifyTransition(p *Position, from PositionState, condition string) {
+	transitionObserverMu.RLock()
+	fn := transitionObserver
+	transitionObserverMu.RUnlock()
+	if fn == nil {
+		return
+	}
+
+	orderID := p.ExitOrderID
+	if p.State == StateSubmitted || (from == StateSubmitted && p.State == StateOpen) {
+		orderID = p.EntryOrderID
+	}
+	fn(TransitionEvent{
+		PositionID: p.ID,
+		Symbol:     p.Symbol,
+		From:       from,
+		To:         p.State,
+		Condition:  condition,
+		Time:       time.Now().UTC(),
+		PnL:        p.CurrentPnL + p.RealizedPnL,
+		Quantity:   p.Quantity,
+		OrderID:    orderID,
+	})
+}
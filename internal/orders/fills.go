@@ -0,0 +1,239 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/journal"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+)
+
+// FillSummary is what a strangle, roll or punt order actually executed
+type FillSummary struct {
+	Quantity int     // Complete strangles filled
+	Price    float64 // Net credit or debit per strangle; zero when the broker reported no price
+	Legs     []models.LegFill
+}
+
+// SummarizeFill derives the filled strangle count and net price from an order. Multileg
+// orders are read per leg: only contracts filled on every leg count, and the price is the
+// sum of the sold legs less the bought legs, so a roll reads as the rolls completed. Orders without legs use the order totals.
+func SummarizeFill(order *broker.Order) FillSummary {
+	if order == nil {
+		return FillSummary{}
+	}
+	if len(order.Legs) == 0 {
+		return FillSummary{
+			Quantity: int(math.Round(order.ExecQuantity)),
+			Price:    math.Abs(order.AvgFillPrice),
+		}
+	}
+
+	minExec := math.Inf(1)
+	net := 0.0
+	legs := make([]models.LegFill, 0, len(order.Legs))
+	for _, leg := range order.Legs {
+		minExec = math.Min(minExec, leg.ExecQuantity)
+		if strings.HasPrefix(strings.ToLower(leg.Side), "sell") {
+			net += leg.AvgFillPrice
+		} else {
+			net -= leg.AvgFillPrice
+		}
+		legs = append(legs, models.LegFill{
+			OptionSymbol: leg.OptionSymbol,
+			Side:         leg.Side,
+			ExecQuantity: leg.ExecQuantity,
+			AvgFillPrice: leg.AvgFillPrice,
+		})
+	}
+
+	fill := FillSummary{Quantity: int(math.Floor(minExec + 1e-6)), Legs: legs}
+	if fill.Quantity > 0 {
+		fill.Price = math.Abs(net)
+	}
+	return fill
+}
+
+// ErrUnevenFill reports an order that filled more contracts on some legs than on others
+var ErrUnevenFill = errors.New("order filled its legs unevenly")
+
+// unevenLegPauser is recorded as who paused entries after an uneven fill
+const unevenLegPauser = "order manager"
+
+// ReportUnevenFill handles an order that filled some legs beyond its complete strangles.
+// Those contracts belong to no tracked position, so new entries are paused until an
+// operator reconciles them with the broker and resumes, and the failure is passed to the
+// uneven-fill hook. It returns false when every leg filled evenly.
+func (m *Manager) ReportUnevenFill(positionID string, orderID int, fill FillSummary) bool {
+	var excess []string
+	for _, leg := range fill.Legs {
+		if extra := leg.ExecQuantity - float64(fill.Quantity); extra > 1e-6 {
+			excess = append(excess, fmt.Sprintf("%s %s x%.0f", leg.Side, leg.OptionSymbol, extra))
+		}
+	}
+	if len(excess) == 0 {
+		return false
+	}
+
+	err := fmt.Errorf("%w: order %d for position %s tracks %d complete, untracked %s",
+		ErrUnevenFill, orderID, positionID, fill.Quantity, strings.Join(excess, ", "))
+	m.logger.Printf("CRITICAL: %v; pausing new entries until it is reconciled with the broker", err)
+	if m.storage.GetEntryPause() == nil {
+		pause := &models.EntryPause{
+			Reason:   err.Error() + "; reconcile with the broker, then resume",
+			PausedAt: time.Now().UTC(),
+			PausedBy: unevenLegPauser,
+		}
+		if perr := m.storage.SetEntryPause(pause); perr != nil {
+			m.logger.Printf("CRITICAL: Failed to pause new entries after uneven fill: %v", perr)
+		}
+	}
+	if m.onUnevenFill != nil {
+		m.onUnevenFill(err)
+	}
+	return true
+}
+
+// settlePartialFill resolves a position whose entry or exit order is being given up on
+// after filling only part of its quantity. Any working remainder is canceled first. An
+// entry then opens at the filled quantity and actual credit; an exit leaves the position
+// open with the bought-back contracts removed and their P&L realized. order is the latest
+// known state of the order, or nil to fetch it. It returns false, leaving the position
+// untouched, when nothing filled or the remainder could not be confirmed canceled.
+func (m *Manager) settlePartialFill(positionID string, order *broker.Order) bool {
+	position, found := m.storage.GetPositionByID(positionID)
+	if !found {
+		return false
+	}
+
+	isEntry := position.GetCurrentState() == models.StateSubmitted
+	orderIDStr := position.ExitOrderID
+	if isEntry {
+		orderIDStr = position.EntryOrderID
+	}
+	orderID, err := parseOrderID(orderIDStr)
+	if err != nil {
+		return false
+	}
+
+	if order == nil || order.ID != orderID {
+		ctx, cancel := context.WithTimeout(context.Background(), m.config.CallTimeout)
+		resp, err := m.broker.GetOrderStatusCtx(ctx, orderID)
+		cancel()
+		if err != nil || resp == nil || resp.Order.ID == 0 {
+			m.logger.Printf("Could not check order %d of position %s for partial fills: %v", orderID, positionID, err)
+			return false
+		}
+		order = &resp.Order
+	}
+	if SummarizeFill(order).Quantity == 0 {
+		return false
+	}
+
+	if !isTerminalStatus(strings.ToLower(order.Status)) {
+		ctx, cancel := context.WithTimeout(context.Background(), m.config.CancelTimeout+m.config.CallTimeout)
+		final, err := m.CancelAndConfirm(ctx, orderID)
+		cancel()
+		if err != nil {
+			m.logger.Printf("CRITICAL: Order %d for position %s partially filled but its remainder was not canceled: %v",
+				orderID, positionID, err)
+			return false
+		}
+		order = final
+	}
+	if strings.EqualFold(order.Status, "filled") {
+		m.handleOrderFilled(positionID, isEntry)
+		return true
+	}
+
+	fill := SummarizeFill(order)
+	m.ReportUnevenFill(positionID, orderID, fill)
+
+	if isEntry {
+		return m.openPartialEntry(&position, orderID, order, fill)
+	}
+	return m.reducePartialExit(&position, orderID, fill)
+}
+
+// openPartialEntry opens a position at what its entry order filled before the rest was canceled
+func (m *Manager) openPartialEntry(position *models.Position, orderID int, order *broker.Order, fill FillSummary) bool {
+	requested := int(math.Round(order.Quantity))
+	position.Quantity = fill.Quantity
+	if fill.Price > 0 {
+		position.CreditReceived = fill.Price
+	}
+	position.EntryFills = fill.Legs
+	position.Fees += m.strangleFees(fill.Quantity)
+
+	if err := position.TransitionState(models.StateOpen, "order_filled"); err != nil {
+		m.logger.Printf("Failed to open partially filled position %s: %v", position.ID, err)
+		return false
+	}
+	if err := m.storage.UpdatePosition(position); err != nil {
+		m.logger.Printf("Failed to save partially filled position %s: %v", position.ID, err)
+		return false
+	}
+
+	m.logger.Printf("Entry order %d for position %s filled %d of %d contracts at $%.2f credit; remainder canceled, position opened",
+		orderID, position.ID, fill.Quantity, requested, position.CreditReceived)
+	m.journalOrder(journal.KindOrderPartialFill, position, orderIDString(orderID), fill.Quantity, fill.Price,
+		fmt.Sprintf("entry filled %d of %d, remainder canceled", fill.Quantity, requested))
+
+	if m.onEntryFilled != nil {
+		m.onEntryFilled(position.ID)
+	}
+	return true
+}
+
+// reducePartialExit keeps a position open after its exit order bought back only part of it
+func (m *Manager) reducePartialExit(position *models.Position, orderID int, fill FillSummary) bool {
+	m.journalOrder(journal.KindOrderPartialFill, position, orderIDString(orderID), fill.Quantity, fill.Price,
+		"exit bought back part, remainder canceled")
+	if link := position.WorkingOrder(models.OrderRoleStopLoss); link != nil && link.OrderID == position.ExitOrderID {
+		link.SetStatus(models.OrderLinkCancelled)
+	}
+	m.realizeExitFill(position, fill.Quantity, fill.Price)
+	position.ExitOrderID = ""
+	position.ExitReason = ""
+
+	if position.Quantity == 0 {
+		reason := m.timeoutTransitionReason(position.GetCurrentState())
+		if err := m.storage.ClosePositionByID(position.ID, position.RealizedPnL, reason); err != nil {
+			m.logger.Printf("Failed to close fully bought back position %s: %v", position.ID, err)
+			return false
+		}
+		m.logger.Printf("Exit order %d bought back the rest of position %s. Final P&L: $%.2f",
+			orderID, position.ID, position.RealizedPnL)
+		return true
+	}
+
+	if err := m.storage.UpdatePosition(position); err != nil {
+		m.logger.Printf("Failed to save partially closed position %s: %v", position.ID, err)
+		return false
+	}
+	m.logger.Printf("Exit order %d for position %s bought back %d contracts at $%.2f; %d remain open, realized P&L $%.2f",
+		orderID, position.ID, fill.Quantity, fill.Price, position.Quantity, position.RealizedPnL)
+	return true
+}
+
+// realizeExitFill removes contracts bought back at debit per strangle from the position,
+// adds their P&L to RealizedPnL and charges the order's fees
+func (m *Manager) realizeExitFill(position *models.Position, filled int, debit float64) {
+	filled = min(filled, position.Quantity)
+	if filled <= 0 {
+		return
+	}
+	position.Fees += m.strangleFees(filled)
+	if debit > 0 {
+		position.RealizedPnL += (position.GetNetCredit() - debit) * float64(filled) * 100
+	} else {
+		m.logger.Printf("Warning: No fill price for %d contracts bought back on position %s; their P&L is not realized",
+			filled, position.ID)
+	}
+	position.Quantity -= filled
+}
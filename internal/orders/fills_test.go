@@ -0,0 +1,256 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/storage"
+)
+
+// partialFillBroker reports one partially filled order until it is canceled
+type partialFillBroker struct {
+	mockBrokerForOrders
+	order    broker.Order
+	canceled []int
+}
+
+func (b *partialFillBroker) GetOrderStatusCtx(ctx context.Context, orderID int) (*broker.OrderResponse, error) {
+	return &broker.OrderResponse{Order: b.order}, nil
+}
+
+func (b *partialFillBroker) CancelOrderCtx(ctx context.Context, orderID int) (*broker.OrderResponse, error) {
+	b.canceled = append(b.canceled, orderID)
+	b.order.Status = "canceled"
+	b.order.RemainingQuantity = 0
+	return &broker.OrderResponse{Order: b.order}, nil
+}
+
+// strangleOrder builds a multileg order with exec contracts filled on each leg
+func strangleOrder(id int, side string, quantity, exec, putPrice, callPrice float64) broker.Order {
+	order := broker.Order{
+		ID:                id,
+		Type:              "credit",
+		Class:             "multileg",
+		Status:            "partially_filled",
+		Quantity:          quantity,
+		ExecQuantity:      exec,
+		RemainingQuantity: quantity - exec,
+	}
+	if side == "buy_to_close" {
+		order.Type = "debit"
+	}
+	order.Legs = []broker.OrderLegStatus{
+		{OptionSymbol: "SPY250117P00400000", Side: side, Quantity: quantity, ExecQuantity: exec, AvgFillPrice: putPrice},
+		{OptionSymbol: "SPY250117C00460000", Side: side, Quantity: quantity, ExecQuantity: exec, AvgFillPrice: callPrice},
+	}
+	return order
+}
+
+func newPartialFillManager(b broker.Broker, s storage.Interface) *Manager {
+	logger := log.New(os.Stderr, "test: ", log.LstdFlags)
+	return NewManager(b, s, logger, nil, Config{
+		PollInterval:  time.Millisecond,
+		Timeout:       time.Second,
+		CallTimeout:   100 * time.Millisecond,
+		CancelTimeout: 100 * time.Millisecond,
+	})
+}
+
+func TestSummarizeFill(t *testing.T) {
+	even := strangleOrder(1, "sell_to_open", 5, 2, 1.30, 1.20)
+	uneven := strangleOrder(2, "sell_to_open", 5, 2, 1.30, 1.20)
+	uneven.Legs[1].ExecQuantity = 3
+	unfilledLeg := strangleOrder(3, "buy_to_close", 5, 2, 0.60, 0.40)
+	unfilledLeg.Legs[0].ExecQuantity = 0
+
+	tests := []struct {
+		name     string
+		order    broker.Order
+		quantity int
+		price    float64
+	}{
+		{"order totals without legs", broker.Order{ExecQuantity: 3, AvgFillPrice: -2.10}, 3, 2.10},
+		{"sold legs add up to the credit", even, 2, 2.50},
+		{"uneven legs count complete strangles", uneven, 2, 2.50},
+		{"a leg with no fills fills no strangles", unfilledLeg, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fill := SummarizeFill(&tt.order)
+			if fill.Quantity != tt.quantity || math.Abs(fill.Price-tt.price) > 1e-9 {
+				t.Errorf("Expected %d @ %.2f, got %d @ %.2f", tt.quantity, tt.price, fill.Quantity, fill.Price)
+			}
+		})
+	}
+}
+
+func TestManager_HandleOrderTimeout_PartialEntryFill(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	position := models.NewPosition("partial-entry", "SPY", 400, 460, time.Now().AddDate(0, 0, 45), 5)
+	if err := position.TransitionState(models.StateSubmitted, "order_placed"); err != nil {
+		t.Fatalf("Failed to set up test position: %v", err)
+	}
+	position.EntryOrderID = "100"
+	position.Quantity = 5
+	position.CreditReceived = 2.60
+	if err := mockStorage.AddPosition(position); err != nil {
+		t.Fatalf("Failed to set up test position in storage: %v", err)
+	}
+
+	// 2 of 5 strangles filled at $1.30 + $1.20
+	b := &partialFillBroker{order: strangleOrder(100, "sell_to_open", 5, 2, 1.30, 1.20)}
+	m := newPartialFillManager(b, mockStorage)
+	var opened string
+	m.SetOnEntryFilled(func(positionID string) { opened = positionID })
+
+	m.handleOrderTimeout("partial-entry")
+
+	if len(b.canceled) != 1 || b.canceled[0] != 100 {
+		t.Errorf("Expected the remainder of order 100 to be canceled, got cancels %v", b.canceled)
+	}
+	updated, found := mockStorage.GetPositionByID("partial-entry")
+	if !found {
+		t.Fatal("Expected the partially filled position to stay open")
+	}
+	if updated.GetCurrentState() != models.StateOpen {
+		t.Errorf("Expected position state %s, got %s", models.StateOpen, updated.GetCurrentState())
+	}
+	if updated.Quantity != 2 || math.Abs(updated.CreditReceived-2.50) > 1e-9 {
+		t.Errorf("Expected 2 contracts at $2.50 credit, got %d at $%.2f", updated.Quantity, updated.CreditReceived)
+	}
+	if len(updated.EntryFills) != 2 || updated.EntryFills[0].ExecQuantity != 2 {
+		t.Errorf("Expected per-leg entry fills to be recorded, got %+v", updated.EntryFills)
+	}
+	if opened != "partial-entry" {
+		t.Errorf("Expected entry-filled callback for the opened position, got %q", opened)
+	}
+}
+
+func TestManager_HandleOrderTimeout_UnevenEntryFillPausesEntries(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	position := models.NewPosition("uneven-entry", "SPY", 400, 460, time.Now().AddDate(0, 0, 45), 5)
+	if err := position.TransitionState(models.StateSubmitted, "order_placed"); err != nil {
+		t.Fatalf("Failed to set up test position: %v", err)
+	}
+	position.EntryOrderID = "110"
+	position.Quantity = 5
+	if err := mockStorage.AddPosition(position); err != nil {
+		t.Fatalf("Failed to set up test position in storage: %v", err)
+	}
+
+	// 3 puts but only 2 calls sold: one short put belongs to no strangle
+	order := strangleOrder(110, "sell_to_open", 5, 2, 1.30, 1.20)
+	order.Legs[0].ExecQuantity = 3
+	b := &partialFillBroker{order: order}
+	m := newPartialFillManager(b, mockStorage)
+	var reported error
+	m.SetOnUnevenFill(func(err error) { reported = err })
+
+	m.handleOrderTimeout("uneven-entry")
+
+	updated, found := mockStorage.GetPositionByID("uneven-entry")
+	if !found || updated.Quantity != 2 || updated.GetCurrentState() != models.StateOpen {
+		t.Fatalf("Expected the 2 complete strangles to open, got %+v", updated)
+	}
+	if !errors.Is(reported, ErrUnevenFill) {
+		t.Errorf("Expected ErrUnevenFill to be reported, got %v", reported)
+	}
+	pause := mockStorage.GetEntryPause()
+	if pause == nil || pause.PausedBy != unevenLegPauser || !strings.Contains(pause.Reason, "SPY250117P00400000 x1") {
+		t.Errorf("Expected entries paused naming the excess put, got %+v", pause)
+	}
+}
+
+func TestManager_HandleOrderTimeout_PartialExitFill(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	position := models.NewPosition("partial-exit", "SPY", 400, 460, time.Now().AddDate(0, 0, 45), 5)
+	if err := position.TransitionState(models.StateSubmitted, "order_placed"); err != nil {
+		t.Fatalf("Failed to set up test position: %v", err)
+	}
+	if err := position.TransitionState(models.StateOpen, "order_filled"); err != nil {
+		t.Fatalf("Failed to set up test position: %v", err)
+	}
+	position.EntryOrderID = "100"
+	position.Quantity = 5
+	position.CreditReceived = 2.50
+	position.ExitOrderID = "200"
+	position.ExitReason = "time"
+	if err := mockStorage.AddPosition(position); err != nil {
+		t.Fatalf("Failed to set up test position in storage: %v", err)
+	}
+
+	// 2 of 5 strangles bought back at $0.60 + $0.40
+	b := &partialFillBroker{order: strangleOrder(200, "buy_to_close", 5, 2, 0.60, 0.40)}
+	m := newPartialFillManager(b, mockStorage)
+	m.config.Fees = models.FeeSchedule{PerContract: 0.35}
+
+	m.handleOrderTimeout("partial-exit")
+
+	if len(b.canceled) != 1 || b.canceled[0] != 200 {
+		t.Errorf("Expected the remainder of order 200 to be canceled, got cancels %v", b.canceled)
+	}
+	if len(mockStorage.GetHistory()) != 0 {
+		t.Fatal("Expected the partially closed position not to be closed")
+	}
+	updated, found := mockStorage.GetPositionByID("partial-exit")
+	if !found {
+		t.Fatal("Expected the partially closed position to stay open")
+	}
+	if updated.GetCurrentState() != models.StateOpen {
+		t.Errorf("Expected position state %s, got %s", models.StateOpen, updated.GetCurrentState())
+	}
+	if updated.Quantity != 3 {
+		t.Errorf("Expected 3 contracts to remain, got %d", updated.Quantity)
+	}
+	if math.Abs(updated.RealizedPnL-300) > 1e-9 {
+		t.Errorf("Expected $300 realized on 2 contracts bought back, got $%.2f", updated.RealizedPnL)
+	}
+	if math.Abs(updated.Fees-1.40) > 1e-9 {
+		t.Errorf("Expected $1.40 fees for 4 contracts bought back, got $%.2f", updated.Fees)
+	}
+	if updated.ExitOrderID != "" || updated.ExitReason != "" {
+		t.Errorf("Expected exit order to be cleared, got %q (%q)", updated.ExitOrderID, updated.ExitReason)
+	}
+}
+
+func TestManager_PollOrderStatus_CanceledAfterPartialEntryFill(t *testing.T) {
+	mockStorage := storage.NewMockStorage()
+	position := models.NewPosition("canceled-entry", "SPY", 400, 460, time.Now().AddDate(0, 0, 45), 5)
+	if err := position.TransitionState(models.StateSubmitted, "order_placed"); err != nil {
+		t.Fatalf("Failed to set up test position: %v", err)
+	}
+	position.EntryOrderID = "100"
+	position.Quantity = 5
+	if err := mockStorage.AddPosition(position); err != nil {
+		t.Fatalf("Failed to set up test position in storage: %v", err)
+	}
+
+	// The broker canceled the order at the close after 3 strangles filled
+	order := strangleOrder(100, "sell_to_open", 5, 3, 1.25, 1.15)
+	order.Status = "canceled"
+	b := &partialFillBroker{order: order}
+	m := newPartialFillManager(b, mockStorage)
+
+	m.PollOrderStatus("canceled-entry", 100, true)
+
+	if len(b.canceled) != 0 {
+		t.Errorf("Expected no cancel for an order that already ended, got %v", b.canceled)
+	}
+	updated, found := mockStorage.GetPositionByID("canceled-entry")
+	if !found {
+		t.Fatal("Expected the partially filled position to be kept")
+	}
+	if updated.GetCurrentState() != models.StateOpen || updated.Quantity != 3 ||
+		math.Abs(updated.CreditReceived-2.40) > 1e-9 {
+		t.Errorf("Expected open with 3 contracts at $2.40, got %s with %d at $%.2f",
+			updated.GetCurrentState(), updated.Quantity, updated.CreditReceived)
+	}
+}
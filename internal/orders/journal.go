@@ -0,0 +1,33 @@
+package orders
+
+import (
+	"fmt"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/journal"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+)
+
+// SetJournal records order placements, fills, cancels and failures handled by the manager
+// in j. A nil journal disables it.
+func (m *Manager) SetJournal(j *journal.Journal) {
+	m.journal = j
+}
+
+// journalOrder records an order event for position
+func (m *Manager) journalOrder(kind journal.Kind, position *models.Position, orderID string, quantity int, price float64, detail string) {
+	m.journal.Record(journal.Entry{
+		Kind:       kind,
+		PositionID: position.ID,
+		Symbol:     position.Symbol,
+		OrderID:    orderID,
+		Quantity:   quantity,
+		Price:      price,
+		PnL:        position.CurrentPnL + position.RealizedPnL,
+		Detail:     detail,
+	})
+}
+
+// orderIDString formats a broker order ID the way positions store it
+func orderIDString(orderID int) string {
+	return fmt.Sprintf("%d", orderID)
+}
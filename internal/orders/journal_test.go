@@ -0,0 +1,64 @@
+package orders
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/journal"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/storage"
+)
+
+func TestManager_JournalsPartialEntryFill(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	j, err := journal.Open(path, nil)
+	if err != nil {
+		t.Fatalf("Failed to open journal: %v", err)
+	}
+	models.SetTransitionObserver(j.ObserveTransition)
+	defer models.SetTransitionObserver(nil)
+
+	mockStorage := storage.NewMockStorage()
+	position := models.NewPosition("journal-entry", "SPY", 400, 460, time.Now().AddDate(0, 0, 45), 5)
+	if err := position.TransitionState(models.StateSubmitted, "order_placed"); err != nil {
+		t.Fatalf("Failed to set up test position: %v", err)
+	}
+	position.EntryOrderID = "100"
+	if err := mockStorage.AddPosition(position); err != nil {
+		t.Fatalf("Failed to set up test position in storage: %v", err)
+	}
+
+	b := &partialFillBroker{order: strangleOrder(100, "sell_to_open", 5, 2, 1.30, 1.20)}
+	m := newPartialFillManager(b, mockStorage)
+	m.SetJournal(j)
+	m.handleOrderTimeout("journal-entry")
+	if err := j.Close(); err != nil {
+		t.Fatalf("Failed to close journal: %v", err)
+	}
+
+	entries, err := journal.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read journal: %v", err)
+	}
+	timeline := journal.Timeline(entries, "journal-entry")
+	var kinds []journal.Kind
+	for _, e := range timeline {
+		kinds = append(kinds, e.Kind)
+	}
+	want := []journal.Kind{journal.KindTransition, journal.KindTransition, journal.KindOrderPartialFill}
+	if len(kinds) != len(want) {
+		t.Fatalf("Expected events %v, got %v", want, kinds)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("Expected events %v, got %v", want, kinds)
+		}
+	}
+	if fill := timeline[2]; fill.OrderID != "100" || fill.Quantity != 2 {
+		t.Errorf("Expected partial fill of 2 on order 100, got %+v", fill)
+	}
+	if opened := timeline[1]; opened.To != models.StateOpen || opened.OrderID != "100" {
+		t.Errorf("Expected the open transition to reference entry order 100, got %+v", opened)
+	}
+}
@@ -0,0 +1,331 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/journal"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/strategy"
+)
+
+var (
+	// ErrProfitTargetFilled is returned when a position's profit-target order filled
+	// before it could be canceled. The position has been closed at the fill.
+	ErrProfitTargetFilled = errors.New("profit target order filled")
+	// ErrCancelUnconfirmed is returned when the broker did not confirm a linked order
+	// cancel in time. The order may still be working, so no close should be sent.
+	ErrCancelUnconfirmed = errors.New("linked order cancel not confirmed")
+)
+
+// maxCancelPollInterval caps the wait between status checks while confirming a cancel
+const maxCancelPollInterval = time.Second
+
+// SetOnEntryFilled registers fn to run after an entry order fills and the position is open,
+// e.g. to place the position's profit-target order.
+func (m *Manager) SetOnEntryFilled(fn func(positionID string)) {
+	m.onEntryFilled = fn
+}
+
+// SetOnUnevenFill registers fn to run with ErrUnevenFill when an order fills its legs
+// unevenly, e.g. to count it as a failed reconciliation.
+func (m *Manager) SetOnUnevenFill(fn func(err error)) {
+	m.onUnevenFill = fn
+}
+
+// PlaceProfitTarget places a resting GTC buy-to-close for the whole strangle at maxDebit
+// per spread and links it to the position. It does nothing if a profit target is already working.
+func (m *Manager) PlaceProfitTarget(ctx context.Context, positionID string, maxDebit float64) error {
+	position, found := m.storage.GetPositionByID(positionID)
+	if !found {
+		return fmt.Errorf("position %s not found", positionID)
+	}
+	if link := position.WorkingOrder(models.OrderRoleProfitTarget); link != nil {
+		m.logger.Printf("Position %s already has profit target order %s", positionID, link.OrderID)
+		return nil
+	}
+	if maxDebit <= 0 {
+		return fmt.Errorf("invalid profit target debit %.2f", maxDebit)
+	}
+
+	tag := fmt.Sprintf("pt-%s-%d", positionID, time.Now().Unix())
+	callCtx, cancel := context.WithTimeout(ctx, m.config.CallTimeout)
+	resp, err := m.broker.CloseStranglePositionCtx(callCtx, position.Symbol, position.PutStrike, position.CallStrike,
+		position.Expiration.Format("2006-01-02"), position.Quantity, maxDebit, tag)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to place profit target order: %w", err)
+	}
+	if resp == nil || resp.Order.ID == 0 {
+		return fmt.Errorf("profit target order placement returned no order ID")
+	}
+
+	// Re-read so updates made while the order was being placed are kept
+	if latest, ok := m.storage.GetPositionByID(positionID); ok {
+		position = latest
+	}
+	position.LinkOrder(models.OrderRoleProfitTarget, fmt.Sprintf("%d", resp.Order.ID), maxDebit)
+	if err := m.storage.UpdatePosition(&position); err != nil {
+		return fmt.Errorf("profit target order %d placed but link not saved: %w", resp.Order.ID, err)
+	}
+
+	m.logger.Printf("Profit target order %d placed for position %s: GTC buy-to-close at $%.2f debit",
+		resp.Order.ID, positionID, maxDebit)
+	m.journalOrder(journal.KindOrderPlaced, &position, orderIDString(resp.Order.ID), position.Quantity, maxDebit,
+		string(models.OrderRoleProfitTarget))
+	return nil
+}
+
+// RepriceProfitTarget moves the position's working profit-target order to maxDebit in place.
+// The order keeps its ID, so there is never a moment with two profit targets or none.
+func (m *Manager) RepriceProfitTarget(ctx context.Context, positionID string, maxDebit float64) error {
+	position, found := m.storage.GetPositionByID(positionID)
+	if !found {
+		return fmt.Errorf("position %s not found", positionID)
+	}
+	link := position.WorkingOrder(models.OrderRoleProfitTarget)
+	if link == nil {
+		return fmt.Errorf("position %s has no working profit target order", positionID)
+	}
+	orderID, err := parseOrderID(link.OrderID)
+	if err != nil {
+		return fmt.Errorf("invalid profit target order ID %q: %w", link.OrderID, err)
+	}
+
+	tickSize, err := m.broker.GetTickSize(position.Symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get tick size for %s: %w", position.Symbol, err)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, m.config.CallTimeout)
+	_, err = m.broker.ModifyOrderCtx(callCtx, orderID, maxDebit, tickSize, "")
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to re-price profit target order %d: %w", orderID, err)
+	}
+
+	previous := link.LimitPrice
+	link.LimitPrice = maxDebit
+	link.SetStatus(models.OrderLinkWorking)
+	if err := m.storage.UpdatePosition(&position); err != nil {
+		return fmt.Errorf("profit target order %d re-priced but link not saved: %w", orderID, err)
+	}
+	m.logger.Printf("Profit target order %d for position %s re-priced from $%.2f to $%.2f",
+		orderID, positionID, previous, maxDebit)
+	return nil
+}
+
+// CancelLinkedOrders cancels the position's resting profit-target order and waits for the
+// broker to confirm the cancel. Stop-loss links are the position's exit order and are left
+// to the exit order poller. It returns ErrProfitTargetFilled if the profit target
+// filled first (the position is then closed), and ErrCancelUnconfirmed if an order could
+// not be confirmed canceled. In both cases the caller must not send another close order.
+func (m *Manager) CancelLinkedOrders(ctx context.Context, positionID string) error {
+	position, found := m.storage.GetPositionByID(positionID)
+	if !found {
+		return fmt.Errorf("position %s not found", positionID)
+	}
+	if position.WorkingOrder(models.OrderRoleProfitTarget) == nil {
+		return nil
+	}
+
+	var result error
+	for i := range position.LinkedOrders {
+		link := &position.LinkedOrders[i]
+		if link.Role != models.OrderRoleProfitTarget || link.Status != models.OrderLinkWorking {
+			continue
+		}
+		order, err := m.cancelLinkedOrder(ctx, link)
+		if err != nil {
+			link.LastError = err.Error()
+			link.UpdatedAt = time.Now().UTC()
+			m.logger.Printf("CRITICAL: Could not confirm cancel of %s order %s for position %s: %v",
+				link.Role, link.OrderID, positionID, err)
+			result = errors.Join(result, err)
+			continue
+		}
+
+		if strings.ToLower(order.Status) == "filled" {
+			if err := m.closeOnProfitTarget(&position, link, order); err != nil {
+				return fmt.Errorf("%w, but closing position %s failed: %v", ErrProfitTargetFilled, positionID, err)
+			}
+			return ErrProfitTargetFilled
+		}
+
+		link.SetStatus(models.OrderLinkCancelled)
+		m.applyPartialFill(&position, link, order)
+		m.logger.Printf("%s order %s for position %s canceled", link.Role, link.OrderID, positionID)
+		m.journalOrder(journal.KindOrderCanceled, &position, link.OrderID, 0, link.LimitPrice, string(link.Role))
+	}
+
+	if err := m.storage.UpdatePosition(&position); err != nil {
+		result = errors.Join(result, fmt.Errorf("failed to save linked order state: %w", err))
+	}
+	return result
+}
+
+// cancelLinkedOrder cancels a linked order and waits for the broker to confirm it
+func (m *Manager) cancelLinkedOrder(ctx context.Context, link *models.OrderLink) (*broker.Order, error) {
+	orderID, err := parseOrderID(link.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid order ID %q: %v", ErrCancelUnconfirmed, link.OrderID, err)
+	}
+	return m.CancelAndConfirm(ctx, orderID)
+}
+
+// CancelAndConfirm requests a cancel and polls the order until it reaches a terminal state.
+// A failed cancel request is not fatal by itself: the order may already be terminal.
+func (m *Manager) CancelAndConfirm(ctx context.Context, orderID int) (*broker.Order, error) {
+	callCtx, cancel := context.WithTimeout(ctx, m.config.CallTimeout)
+	_, cancelErr := m.broker.CancelOrderCtx(callCtx, orderID)
+	cancel()
+	if cancelErr != nil {
+		m.logger.Printf("Cancel request for order %d failed, checking its status: %v", orderID, cancelErr)
+	}
+
+	confirmCtx, confirmCancel := context.WithTimeout(ctx, m.config.CancelTimeout)
+	defer confirmCancel()
+	interval := m.config.PollInterval
+	if interval > maxCancelPollInterval {
+		interval = maxCancelPollInterval
+	}
+
+	lastStatus := "unknown"
+	for {
+		statusCtx, statusCancel := context.WithTimeout(confirmCtx, m.config.CallTimeout)
+		resp, err := m.broker.GetOrderStatusCtx(statusCtx, orderID)
+		statusCancel()
+		if err == nil && resp != nil && resp.Order.ID != 0 {
+			lastStatus = strings.ToLower(resp.Order.Status)
+			if isTerminalStatus(lastStatus) {
+				return &resp.Order, nil
+			}
+		}
+
+		select {
+		case <-confirmCtx.Done():
+			return nil, fmt.Errorf("%w: order %d still %s (cancel error: %v)",
+				ErrCancelUnconfirmed, orderID, lastStatus, cancelErr)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// SyncLinkedOrders checks resting profit-target orders against the broker's order list in
+// one request. A filled profit target closes its position; orders canceled, expired or
+// rejected outside the bot are marked as such. It returns the IDs of closed positions.
+func (m *Manager) SyncLinkedOrders(ctx context.Context) []string {
+	var linked []models.Position
+	for _, position := range m.storage.GetCurrentPositions() {
+		if position.WorkingOrder(models.OrderRoleProfitTarget) != nil {
+			linked = append(linked, position)
+		}
+	}
+	if len(linked) == 0 {
+		return nil
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, m.config.CallTimeout)
+	resp, err := m.broker.GetOrdersCtx(callCtx)
+	cancel()
+	if err != nil || resp == nil {
+		m.logger.Printf("Warning: Could not fetch orders to sync linked orders: %v", err)
+		return nil
+	}
+	byID := make(map[string]*broker.Order, len(resp.Orders.Order))
+	for i := range resp.Orders.Order {
+		byID[fmt.Sprintf("%d", resp.Orders.Order[i].ID)] = &resp.Orders.Order[i]
+	}
+
+	var closed []string
+	for i := range linked {
+		position := &linked[i]
+		link := position.WorkingOrder(models.OrderRoleProfitTarget)
+		order, ok := byID[link.OrderID]
+		if !ok {
+			continue
+		}
+		switch status := strings.ToLower(order.Status); {
+		case status == "filled":
+			if err := m.closeOnProfitTarget(position, link, order); err != nil {
+				m.logger.Printf("Failed to close position %s after profit target fill: %v", position.ID, err)
+				continue
+			}
+			closed = append(closed, position.ID)
+		case isTerminalStatus(status):
+			m.logger.Printf("Warning: %s order %s for position %s is %s at the broker",
+				link.Role, link.OrderID, position.ID, status)
+			link.SetStatus(models.OrderLinkCancelled)
+			m.applyPartialFill(position, link, order)
+			if err := m.storage.UpdatePosition(position); err != nil {
+				m.logger.Printf("Failed to save linked order state for position %s: %v", position.ID, err)
+			}
+		}
+	}
+	return closed
+}
+
+// closeOnProfitTarget records a filled profit-target order and closes the position at its fill
+func (m *Manager) closeOnProfitTarget(position *models.Position, link *models.OrderLink, order *broker.Order) error {
+	debit := math.Abs(order.AvgFillPrice)
+	if debit == 0 {
+		debit = link.LimitPrice
+	}
+	pnl := (position.GetNetCredit()-debit)*float64(position.Quantity)*100 + position.RealizedPnL
+
+	link.SetStatus(models.OrderLinkFilled)
+	position.ExitOrderID = link.OrderID
+	position.ExitReason = string(strategy.ExitReasonProfitTarget)
+	position.CurrentPnL = pnl
+	position.Fees += m.strangleFees(position.Quantity)
+	if err := m.storage.UpdatePosition(position); err != nil {
+		return fmt.Errorf("failed to save profit target fill: %w", err)
+	}
+	// An empty reason lets storage pick the close condition valid for the current state
+	if err := m.storage.ClosePositionByID(position.ID, pnl, ""); err != nil {
+		return err
+	}
+
+	m.logger.Printf("Profit target order %s filled for position %s at $%.2f debit, position closed. Final P&L: $%.2f",
+		link.OrderID, position.ID, debit, pnl)
+	m.journal.Record(journal.Entry{
+		Kind:       journal.KindOrderFilled,
+		PositionID: position.ID,
+		Symbol:     position.Symbol,
+		OrderID:    link.OrderID,
+		Quantity:   position.Quantity,
+		Price:      debit,
+		PnL:        pnl,
+		Detail:     string(models.OrderRoleProfitTarget),
+	})
+	return nil
+}
+
+// applyPartialFill reduces the position by contracts a canceled close order bought back,
+// realizing their P&L at the order's fill price
+func (m *Manager) applyPartialFill(position *models.Position, link *models.OrderLink, order *broker.Order) {
+	fill := SummarizeFill(order)
+	if fill.Quantity <= 0 {
+		return
+	}
+	if fill.Price == 0 {
+		fill.Price = link.LimitPrice
+	}
+	m.logger.Printf("Warning: %s order %s for position %s bought back %d of %d contracts before it ended; %d remain",
+		link.Role, link.OrderID, position.ID, fill.Quantity, position.Quantity, max(position.Quantity-fill.Quantity, 0))
+	m.realizeExitFill(position, fill.Quantity, fill.Price)
+}
+
+// isTerminalStatus reports whether a Tradier order status is final
+func isTerminalStatus(status string) bool {
+	switch status {
+	case "filled", "canceled", "cancelled", "rejected", "expired":
+		return true
+	}
+	return false
+}
@@ -10,9 +10,11 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/journal"
 	"github.com/eddiefleurent/scranton_strangler/internal/models"
 	"github.com/eddiefleurent/scranton_strangler/internal/storage"
 	"github.com/eddiefleurent/scranton_strangler/internal/strategy"
@@ -21,16 +23,21 @@ import (
 
 // Config contains configuration for the order manager.
 type Config struct {
-	PollInterval time.Duration
-	Timeout      time.Duration
-	CallTimeout  time.Duration
+	PollInterval  time.Duration
+	Timeout       time.Duration
+	CallTimeout   time.Duration
+	// CancelTimeout bounds how long a linked order cancel waits for broker confirmation
+	CancelTimeout time.Duration
+	// Fees is charged to a position for every entry and exit fill
+	Fees models.FeeSchedule
 }
 
 // DefaultConfig is the default configuration for the order manager.
 var DefaultConfig = Config{
-	PollInterval: 5 * time.Second,
-	Timeout:      5 * time.Minute,
-	CallTimeout:  5 * time.Second,
+	PollInterval:  5 * time.Second,
+	Timeout:       5 * time.Minute,
+	CallTimeout:   5 * time.Second,
+	CancelTimeout: 30 * time.Second,
 }
 
 // Manager handles order execution and status polling.
@@ -40,6 +47,20 @@ type Manager struct {
 	logger  *log.Logger
 	stop    <-chan struct{}
 	config  Config
+
+	// onEntryFilled runs after an entry order fills and the position is open
+	onEntryFilled func(positionID string)
+
+	// onUnevenFill runs when an order leaves contracts on some legs outside any position
+	onUnevenFill func(err error)
+
+	// journal records order events; nil disables it
+	journal *journal.Journal
+
+	// watching holds the orders WatchOrder is tracking; watches counts their goroutines
+	watchMu  sync.Mutex
+	watching map[int]bool
+	watches  sync.WaitGroup
 }
 
 // NewManager creates a new order manager instance.
@@ -70,6 +91,9 @@ func NewManager(
 	if cfg.CallTimeout <= 0 {
 		cfg.CallTimeout = DefaultConfig.CallTimeout
 	}
+	if cfg.CancelTimeout <= 0 {
+		cfg.CancelTimeout = DefaultConfig.CancelTimeout
+	}
 
 	// Validate required dependencies (fail fast to avoid later panics)
 	if broker == nil {
@@ -158,6 +182,9 @@ func (m *Manager) PollOrderStatus(positionID string, orderID int, isEntryOrder b
 			switch status {
 			case "canceled", "cancelled", "rejected", "expired":
 				m.logger.Printf("Order failed for position %s: %s", positionID, orderStatus.Order.Status)
+				if m.settlePartialFill(positionID, &orderStatus.Order) {
+					return
+				}
 				m.handleOrderFailed(positionID, orderID, orderStatus.Order.Status)
 				return
 			case "pending", "open", "partial", "partially_filled", "filled":
@@ -172,6 +199,39 @@ func (m *Manager) PollOrderStatus(positionID string, orderID int, isEntryOrder b
 	}
 }
 
+// ResumeOrder picks up an order placed before the bot restarted. An order that filled or
+// ended while nothing was tracking it is settled immediately; one still working is polled
+// in the background as if it had just been placed. It reports whether the order is working.
+func (m *Manager) ResumeOrder(ctx context.Context, positionID string, orderID int, isEntryOrder bool) (bool, error) {
+	statusCtx, cancel := context.WithTimeout(ctx, m.config.CallTimeout)
+	orderStatus, err := m.broker.GetOrderStatusCtx(statusCtx, orderID)
+	cancel()
+	if err != nil {
+		return false, fmt.Errorf("failed to get order status: %w", err)
+	}
+	if orderStatus == nil || orderStatus.Order.ID == 0 {
+		return false, fmt.Errorf("invalid order status response")
+	}
+
+	status := strings.ToLower(orderStatus.Order.Status)
+	if m.isOrderCompletelyFilled(orderStatus) {
+		m.logger.Printf("Order %d for position %s filled while the bot was down", orderID, positionID)
+		m.handleOrderFilled(positionID, isEntryOrder)
+		return false, nil
+	}
+	if isTerminalStatus(status) {
+		m.logger.Printf("Order %d for position %s ended while the bot was down: %s", orderID, positionID, status)
+		if !m.settlePartialFill(positionID, &orderStatus.Order) {
+			m.handleOrderFailed(positionID, orderID, orderStatus.Order.Status)
+		}
+		return false, nil
+	}
+
+	m.logger.Printf("Resuming tracking of order %d for position %s (%s)", orderID, positionID, status)
+	go m.PollOrderStatus(positionID, orderID, isEntryOrder)
+	return true, nil
+}
+
 func (m *Manager) handleOrderFilled(positionID string, isEntryOrder bool) {
 	// Try to get position by ID first (for multiple positions support)
 	position, found := m.storage.GetPositionByID(positionID)
@@ -196,14 +256,16 @@ func (m *Manager) handleOrderFilled(positionID string, isEntryOrder bool) {
 				orderStatus, err := m.broker.GetOrderStatusCtx(ctx, orderIDInt)
 				if err == nil && orderStatus != nil && orderStatus.Order.ID != 0 {
 					// Only update position details if order actually executed
-					if orderStatus.Order.ExecQuantity > 0 {
-						// Set the actual executed quantity using proper rounding
-						position.Quantity = int(math.Round(orderStatus.Order.ExecQuantity))
+					if fill := SummarizeFill(&orderStatus.Order); fill.Quantity > 0 {
+						// Set the executed quantity, counting only strangles filled on every leg
+						position.Quantity = fill.Quantity
+						position.EntryFills = fill.Legs
+						position.Fees += m.strangleFees(fill.Quantity)
 						
 						// Handle credit received based on order type
 						if orderStatus.Order.Type == "credit" {
-							// For credit orders (sell-to-open), AvgFillPrice should be positive credit received
-							position.CreditReceived = math.Abs(orderStatus.Order.AvgFillPrice)
+							// For credit orders (sell-to-open), the net fill price is the credit received
+							position.CreditReceived = fill.Price
 							m.logger.Printf("Position %s filled: qty=%d, credit_received=%.4f (credit order)", 
 								positionID, position.Quantity, position.CreditReceived)
 						} else {
@@ -237,6 +299,11 @@ func (m *Manager) handleOrderFilled(positionID string, isEntryOrder bool) {
 		}
 
 		m.logger.Printf("Position %s successfully transitioned to %s state", positionID, targetState)
+		m.journalOrder(journal.KindOrderFilled, &position, position.EntryOrderID, position.Quantity, position.CreditReceived, "entry")
+
+		if m.onEntryFilled != nil {
+			m.onEntryFilled(positionID)
+		}
 	} else {
 		// For exit orders, use ClosePosition API for atomic state transition and persistence
 		transitionReason = m.exitConditionFromReason(position.ExitReason)
@@ -253,6 +320,15 @@ func (m *Manager) handleOrderFilled(positionID string, isEntryOrder bool) {
 			// Fallback to credit received if CurrentPnL is zero
 			finalPnL = position.CreditReceived * float64(position.Quantity) * 100
 		}
+		finalPnL += position.RealizedPnL
+
+		if link := position.WorkingOrder(models.OrderRoleStopLoss); link != nil && link.OrderID == position.ExitOrderID {
+			link.SetStatus(models.OrderLinkFilled)
+		}
+		position.Fees += m.strangleFees(position.Quantity)
+		if err := m.storage.UpdatePosition(&position); err != nil {
+			m.logger.Printf("Failed to record exit fill for position %s: %v", positionID, err)
+		}
 
 		// Close position using position ID
 		if err := m.storage.ClosePositionByID(positionID, finalPnL, transitionReason); err != nil {
@@ -261,6 +337,8 @@ func (m *Manager) handleOrderFilled(positionID string, isEntryOrder bool) {
 		}
 
 		m.logger.Printf("Position %s successfully closed. Final P&L: $%.2f", positionID, finalPnL)
+		m.journalOrder(journal.KindOrderFilled, &position, position.ExitOrderID, position.Quantity, 0,
+			fmt.Sprintf("exit (%s)", exitReason))
 	}
 }
 
@@ -279,6 +357,9 @@ func (m *Manager) handleOrderFailed(positionID string, orderID int, reason strin
 	if isExitOrder {
 		m.logger.Printf("Exit order failed for position %s: %s, keeping position active and clearing exit order", positionID, reason)
 		// For exit order failures, keep position state unchanged and clear the exit order ID
+		if link := position.WorkingOrder(models.OrderRoleStopLoss); link != nil && link.OrderID == position.ExitOrderID {
+			link.SetStatus(models.OrderLinkCancelled)
+		}
 		position.ExitOrderID = ""
 		position.ExitReason = ""
 	} else {
@@ -293,6 +374,7 @@ func (m *Manager) handleOrderFailed(positionID string, orderID int, reason strin
 		return
 	}
 
+	m.journalOrder(journal.KindOrderFailed, &position, orderIDString(orderID), 0, 0, reason)
 	if isExitOrder {
 		m.logger.Printf("Position %s kept active due to exit order failure: %s", positionID, reason)
 	} else {
@@ -307,6 +389,11 @@ func (m *Manager) handleOrderTimeout(positionID string) {
 		return
 	}
 
+	// An order that filled part of its quantity is settled at what it filled
+	if m.settlePartialFill(positionID, nil) {
+		return
+	}
+
 	// Before closing position, check if broker actually has open positions matching this trade
 	// This prevents closing positions that actually filled but we lost track due to polling timeout
 	m.logger.Printf("Order timeout for position %s - verifying broker state before closing", positionID)
@@ -358,6 +445,12 @@ func (m *Manager) handleOrderTimeout(positionID string) {
 	// Detect entry vs exit order timeout
 	isExitOrder := position.ExitOrderID != "" && position.GetCurrentState() != models.StateSubmitted
 
+	timedOutOrder := position.EntryOrderID
+	if isExitOrder {
+		timedOutOrder = position.ExitOrderID
+	}
+	m.journalOrder(journal.KindOrderFailed, &position, timedOutOrder, 0, 0, "timed out")
+
 	var transitionReason string
 	if isExitOrder {
 		// For exit order timeouts, determine transition reason based on current state
@@ -392,6 +485,7 @@ func (m *Manager) handleOrderTimeout(positionID string) {
 		if finalPnL == 0 {
 			finalPnL = position.CreditReceived * float64(position.Quantity) * 100
 		}
+		finalPnL += position.RealizedPnL
 		closeReason = transitionReason
 
 		if err := m.storage.ClosePositionByID(positionID, finalPnL, closeReason); err != nil {
@@ -725,6 +819,11 @@ func (m *Manager) getPositionsWithTimeout(ctx context.Context) ([]broker.Positio
 	return m.broker.GetPositionsCtx(ctx)
 }
 
+// strangleFees is what the fee schedule charges to fill quantity strangles in one order
+func (m *Manager) strangleFees(quantity int) float64 {
+	return m.config.Fees.OrderFees(2, quantity)
+}
+
 // parseOrderID converts a string order ID to integer
 func parseOrderID(orderIDStr string) (int, error) {
 	if orderIDStr == "" {
@@ -51,6 +51,18 @@ func (m *mockBrokerForOrders) GetQuote(symbol string) (*broker.QuoteItem, error)
 	return &broker.QuoteItem{Symbol: symbol, Last: 100.0}, nil
 }
 
+func (m *mockBrokerForOrders) GetQuotes(symbols []string) ([]broker.QuoteItem, error) {
+	return m.GetQuotesCtx(context.Background(), symbols)
+}
+
+func (m *mockBrokerForOrders) GetQuotesCtx(ctx context.Context, symbols []string) ([]broker.QuoteItem, error) {
+	quotes := make([]broker.QuoteItem, 0, len(symbols))
+	for _, s := range symbols {
+		quotes = append(quotes, broker.QuoteItem{Symbol: s, Last: 100.0})
+	}
+	return quotes, nil
+}
+
 func (m *mockBrokerForOrders) GetExpirations(symbol string) ([]string, error) {
 	return []string{"2024-12-20"}, nil
 }
@@ -97,6 +109,18 @@ func (m *mockBrokerForOrders) CancelOrderCtx(ctx context.Context, orderID int) (
 	return m.CancelOrder(orderID)
 }
 
+func (m *mockBrokerForOrders) ModifyOrder(orderID int, price, tickSize float64, duration string) (*broker.OrderResponse, error) {
+	m.callCount++
+	resp := &broker.OrderResponse{}
+	resp.Order.ID = orderID
+	resp.Order.Status = "ok"
+	return resp, nil
+}
+
+func (m *mockBrokerForOrders) ModifyOrderCtx(ctx context.Context, orderID int, price, tickSize float64, duration string) (*broker.OrderResponse, error) {
+	return m.ModifyOrder(orderID, price, tickSize, duration)
+}
+
 func (m *mockBrokerForOrders) GetOrders() (*broker.OrdersResponse, error) {
 	m.callCount++
 	return &broker.OrdersResponse{}, nil
@@ -122,6 +146,14 @@ func (m *mockBrokerForOrders) PlaceSellToCloseOrder(optionSymbol string, quantit
 	return &broker.OrderResponse{}, nil
 }
 
+func (m *mockBrokerForOrders) PlaceMultiLegOrder(legs []broker.OrderLeg, priceType broker.NetPriceType, price float64, duration string, tag string) (*broker.OrderResponse, error) {
+	return &broker.OrderResponse{}, nil
+}
+
+func (m *mockBrokerForOrders) PlaceMultiLegOrderCtx(ctx context.Context, legs []broker.OrderLeg, priceType broker.NetPriceType, price float64, duration string, tag string) (*broker.OrderResponse, error) {
+	return &broker.OrderResponse{}, nil
+}
+
 func (m *mockBrokerForOrders) PlaceBuyToCloseMarketOrder(optionSymbol string, quantity int, duration string, tag string) (*broker.OrderResponse, error) {
 	return &broker.OrderResponse{}, nil
 }
@@ -280,24 +312,7 @@ func TestManager_IsOrderTerminal(t *testing.T) {
 			var orderResp *broker.OrderResponse
 			if tt.orderStatus != "" {
 				orderResp = &broker.OrderResponse{
-					Order: struct {
-						CreateDate        string  `json:"create_date"`
-						Type              string  `json:"type"`
-						Symbol            string  `json:"symbol"`
-						Side              string  `json:"side"`
-						Class             string  `json:"class"`
-						Status            string  `json:"status"`
-						Duration          string  `json:"duration"`
-						TransactionDate   string  `json:"transaction_date"`
-						AvgFillPrice      float64 `json:"avg_fill_price"`
-						ExecQuantity      float64 `json:"exec_quantity"`
-						LastFillPrice     float64 `json:"last_fill_price"`
-						LastFillQuantity  float64 `json:"last_fill_quantity"`
-						RemainingQuantity float64 `json:"remaining_quantity"`
-						ID                int     `json:"id"`
-						Price             float64 `json:"price"`
-						Quantity          float64 `json:"quantity"`
-					}{
+					Order: broker.Order{
 						ID:     tt.orderID,
 						Status: tt.orderStatus,
 					},
@@ -367,24 +382,7 @@ func TestManager_PollOrderStatus_OrderFilled(t *testing.T) {
 
 	// Mock broker that returns "filled" status immediately
 	orderResp := &broker.OrderResponse{
-		Order: struct {
-			CreateDate        string  `json:"create_date"`
-			Type              string  `json:"type"`
-			Symbol            string  `json:"symbol"`
-			Side              string  `json:"side"`
-			Class             string  `json:"class"`
-			Status            string  `json:"status"`
-			Duration          string  `json:"duration"`
-			TransactionDate   string  `json:"transaction_date"`
-			AvgFillPrice      float64 `json:"avg_fill_price"`
-			ExecQuantity      float64 `json:"exec_quantity"`
-			LastFillPrice     float64 `json:"last_fill_price"`
-			LastFillQuantity  float64 `json:"last_fill_quantity"`
-			RemainingQuantity float64 `json:"remaining_quantity"`
-			ID                int     `json:"id"`
-			Price             float64 `json:"price"`
-			Quantity          float64 `json:"quantity"`
-		}{
+		Order: broker.Order{
 			ID:     123,
 			Status: "filled",
 		},
@@ -443,24 +441,7 @@ func TestManager_PollOrderStatus_OrderCanceled(t *testing.T) {
 
 	// Mock broker that returns "canceled" status
 	orderResp := &broker.OrderResponse{
-		Order: struct {
-			CreateDate        string  `json:"create_date"`
-			Type              string  `json:"type"`
-			Symbol            string  `json:"symbol"`
-			Side              string  `json:"side"`
-			Class             string  `json:"class"`
-			Status            string  `json:"status"`
-			Duration          string  `json:"duration"`
-			TransactionDate   string  `json:"transaction_date"`
-			AvgFillPrice      float64 `json:"avg_fill_price"`
-			ExecQuantity      float64 `json:"exec_quantity"`
-			LastFillPrice     float64 `json:"last_fill_price"`
-			LastFillQuantity  float64 `json:"last_fill_quantity"`
-			RemainingQuantity float64 `json:"remaining_quantity"`
-			ID                int     `json:"id"`
-			Price             float64 `json:"price"`
-			Quantity          float64 `json:"quantity"`
-		}{
+		Order: broker.Order{
 			ID:     123,
 			Status: "canceled",
 		},
@@ -519,24 +500,7 @@ func TestManager_PollOrderStatus_Timeout(t *testing.T) {
 
 	// Mock broker that always returns pending status
 	orderResp := &broker.OrderResponse{
-		Order: struct {
-			CreateDate        string  `json:"create_date"`
-			Type              string  `json:"type"`
-			Symbol            string  `json:"symbol"`
-			Side              string  `json:"side"`
-			Class             string  `json:"class"`
-			Status            string  `json:"status"`
-			Duration          string  `json:"duration"`
-			TransactionDate   string  `json:"transaction_date"`
-			AvgFillPrice      float64 `json:"avg_fill_price"`
-			ExecQuantity      float64 `json:"exec_quantity"`
-			LastFillPrice     float64 `json:"last_fill_price"`
-			LastFillQuantity  float64 `json:"last_fill_quantity"`
-			RemainingQuantity float64 `json:"remaining_quantity"`
-			ID                int     `json:"id"`
-			Price             float64 `json:"price"`
-			Quantity          float64 `json:"quantity"`
-		}{
+		Order: broker.Order{
 			ID:     123,
 			Status: "pending",
 		},
@@ -853,24 +817,7 @@ func TestManager_HandleOrderFill_CreditVsDebitFills(t *testing.T) {
 
 			// Mock broker that returns filled order with specific type and fill price
 			orderResp := &broker.OrderResponse{
-				Order: struct {
-					CreateDate        string  `json:"create_date"`
-					Type              string  `json:"type"`
-					Symbol            string  `json:"symbol"`
-					Side              string  `json:"side"`
-					Class             string  `json:"class"`
-					Status            string  `json:"status"`
-					Duration          string  `json:"duration"`
-					TransactionDate   string  `json:"transaction_date"`
-					AvgFillPrice      float64 `json:"avg_fill_price"`
-					ExecQuantity      float64 `json:"exec_quantity"`
-					LastFillPrice     float64 `json:"last_fill_price"`
-					LastFillQuantity  float64 `json:"last_fill_quantity"`
-					RemainingQuantity float64 `json:"remaining_quantity"`
-					ID                int     `json:"id"`
-					Price             float64 `json:"price"`
-					Quantity          float64 `json:"quantity"`
-				}{
+				Order: broker.Order{
 					ID:           123,
 					Status:       "filled",
 					Type:         tt.orderType,
@@ -951,24 +898,7 @@ func TestManager_IsOrderCompletelyFilled(t *testing.T) {
 		{
 			name: "explicitly_filled_status",
 			orderResponse: &broker.OrderResponse{
-				Order: struct {
-					CreateDate        string  `json:"create_date"`
-					Type              string  `json:"type"`
-					Symbol            string  `json:"symbol"`
-					Side              string  `json:"side"`
-					Class             string  `json:"class"`
-					Status            string  `json:"status"`
-					Duration          string  `json:"duration"`
-					TransactionDate   string  `json:"transaction_date"`
-					AvgFillPrice      float64 `json:"avg_fill_price"`
-					ExecQuantity      float64 `json:"exec_quantity"`
-					LastFillPrice     float64 `json:"last_fill_price"`
-					LastFillQuantity  float64 `json:"last_fill_quantity"`
-					RemainingQuantity float64 `json:"remaining_quantity"`
-					ID                int     `json:"id"`
-					Price             float64 `json:"price"`
-					Quantity          float64 `json:"quantity"`
-				}{
+				Order: broker.Order{
 					Status:            "filled",
 					ExecQuantity:      3.0,
 					Quantity:          3.0,
@@ -981,24 +911,7 @@ func TestManager_IsOrderCompletelyFilled(t *testing.T) {
 		{
 			name: "partial_status_but_fully_executed",
 			orderResponse: &broker.OrderResponse{
-				Order: struct {
-					CreateDate        string  `json:"create_date"`
-					Type              string  `json:"type"`
-					Symbol            string  `json:"symbol"`
-					Side              string  `json:"side"`
-					Class             string  `json:"class"`
-					Status            string  `json:"status"`
-					Duration          string  `json:"duration"`
-					TransactionDate   string  `json:"transaction_date"`
-					AvgFillPrice      float64 `json:"avg_fill_price"`
-					ExecQuantity      float64 `json:"exec_quantity"`
-					LastFillPrice     float64 `json:"last_fill_price"`
-					LastFillQuantity  float64 `json:"last_fill_quantity"`
-					RemainingQuantity float64 `json:"remaining_quantity"`
-					ID                int     `json:"id"`
-					Price             float64 `json:"price"`
-					Quantity          float64 `json:"quantity"`
-				}{
+				Order: broker.Order{
 					Status:            "partial",
 					ExecQuantity:      3.0,
 					Quantity:          3.0,
@@ -1011,24 +924,7 @@ func TestManager_IsOrderCompletelyFilled(t *testing.T) {
 		{
 			name: "partially_filled_status_with_remaining",
 			orderResponse: &broker.OrderResponse{
-				Order: struct {
-					CreateDate        string  `json:"create_date"`
-					Type              string  `json:"type"`
-					Symbol            string  `json:"symbol"`
-					Side              string  `json:"side"`
-					Class             string  `json:"class"`
-					Status            string  `json:"status"`
-					Duration          string  `json:"duration"`
-					TransactionDate   string  `json:"transaction_date"`
-					AvgFillPrice      float64 `json:"avg_fill_price"`
-					ExecQuantity      float64 `json:"exec_quantity"`
-					LastFillPrice     float64 `json:"last_fill_price"`
-					LastFillQuantity  float64 `json:"last_fill_quantity"`
-					RemainingQuantity float64 `json:"remaining_quantity"`
-					ID                int     `json:"id"`
-					Price             float64 `json:"price"`
-					Quantity          float64 `json:"quantity"`
-				}{
+				Order: broker.Order{
 					Status:            "partially_filled",
 					ExecQuantity:      1.0,
 					Quantity:          3.0,
@@ -1041,24 +937,7 @@ func TestManager_IsOrderCompletelyFilled(t *testing.T) {
 		{
 			name: "zero_remaining_quantity",
 			orderResponse: &broker.OrderResponse{
-				Order: struct {
-					CreateDate        string  `json:"create_date"`
-					Type              string  `json:"type"`
-					Symbol            string  `json:"symbol"`
-					Side              string  `json:"side"`
-					Class             string  `json:"class"`
-					Status            string  `json:"status"`
-					Duration          string  `json:"duration"`
-					TransactionDate   string  `json:"transaction_date"`
-					AvgFillPrice      float64 `json:"avg_fill_price"`
-					ExecQuantity      float64 `json:"exec_quantity"`
-					LastFillPrice     float64 `json:"last_fill_price"`
-					LastFillQuantity  float64 `json:"last_fill_quantity"`
-					RemainingQuantity float64 `json:"remaining_quantity"`
-					ID                int     `json:"id"`
-					Price             float64 `json:"price"`
-					Quantity          float64 `json:"quantity"`
-				}{
+				Order: broker.Order{
 					Status:            "open",
 					ExecQuantity:      2.999999, // slightly under due to precision
 					Quantity:          3.0,
@@ -1071,24 +950,7 @@ func TestManager_IsOrderCompletelyFilled(t *testing.T) {
 		{
 			name: "rejected_order_with_zero_remaining",
 			orderResponse: &broker.OrderResponse{
-				Order: struct {
-					CreateDate        string  `json:"create_date"`
-					Type              string  `json:"type"`
-					Symbol            string  `json:"symbol"`
-					Side              string  `json:"side"`
-					Class             string  `json:"class"`
-					Status            string  `json:"status"`
-					Duration          string  `json:"duration"`
-					TransactionDate   string  `json:"transaction_date"`
-					AvgFillPrice      float64 `json:"avg_fill_price"`
-					ExecQuantity      float64 `json:"exec_quantity"`
-					LastFillPrice     float64 `json:"last_fill_price"`
-					LastFillQuantity  float64 `json:"last_fill_quantity"`
-					RemainingQuantity float64 `json:"remaining_quantity"`
-					ID                int     `json:"id"`
-					Price             float64 `json:"price"`
-					Quantity          float64 `json:"quantity"`
-				}{
+				Order: broker.Order{
 					Status:            "rejected", // Order was rejected
 					ExecQuantity:      0.0,        // Nothing executed
 					Quantity:          6.0,        // Requested 6 contracts
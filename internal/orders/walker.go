@@ -0,0 +1,202 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/util"
+)
+
+// PriceWalk describes how an unfilled limit order is re-priced from mid toward the natural
+// price. Prices are per spread: entries walk their credit down, exits walk their debit up.
+type PriceWalk struct {
+	Side     models.ExecutionSide
+	Mid      float64
+	Natural  float64
+	Start    float64 // Mid rounded to a tick away from the natural price
+	Limit    float64 // Last price the walk reaches
+	Step     float64 // Price conceded per step
+	Interval time.Duration
+
+	tickSize float64
+}
+
+// NewPriceWalk builds a walk that starts at mid and concedes stepTicks every interval. It stops
+// maxConcessionTicks from the start or at the natural price, whichever comes first.
+func NewPriceWalk(side models.ExecutionSide, mid, natural, tickSize float64, stepTicks, maxConcessionTicks int,
+	interval time.Duration) (*PriceWalk, error) {
+	if tickSize <= 0 {
+		return nil, fmt.Errorf("invalid tick size %.4f", tickSize)
+	}
+	if mid <= 0 || natural <= 0 {
+		return nil, fmt.Errorf("invalid mid $%.2f or natural $%.2f", mid, natural)
+	}
+	if stepTicks <= 0 || maxConcessionTicks < 0 {
+		return nil, fmt.Errorf("invalid step (%d) or max concession (%d) ticks", stepTicks, maxConcessionTicks)
+	}
+
+	w := &PriceWalk{
+		Side:     side,
+		Mid:      mid,
+		Natural:  natural,
+		Step:     float64(stepTicks) * tickSize,
+		Interval: interval,
+		tickSize: tickSize,
+	}
+	concession := float64(maxConcessionTicks) * tickSize
+	switch side {
+	case models.ExecutionEntry:
+		w.Start = math.Max(util.FloorToTick(mid, tickSize), tickSize)
+		w.Limit = math.Max(w.Start-concession, util.CeilToTick(natural, tickSize))
+		w.Limit = math.Max(math.Min(w.Limit, w.Start), tickSize)
+	case models.ExecutionExit:
+		w.Start = math.Max(util.CeilToTick(mid, tickSize), tickSize)
+		w.Limit = math.Max(math.Min(w.Start+concession, util.FloorToTick(natural, tickSize)), w.Start)
+	default:
+		return nil, fmt.Errorf("unknown execution side %q", side)
+	}
+	w.Start = util.RoundToTick(w.Start, tickSize)
+	w.Limit = util.RoundToTick(w.Limit, tickSize)
+	return w, nil
+}
+
+// next returns the price one step after price, clamped to the walk limit
+func (w *PriceWalk) next(price float64) float64 {
+	if w.Side == models.ExecutionEntry {
+		return util.RoundToTick(math.Max(price-w.Step, w.Limit), w.tickSize)
+	}
+	return util.RoundToTick(math.Min(price+w.Step, w.Limit), w.tickSize)
+}
+
+// exhausted reports whether price has reached the walk limit
+func (w *PriceWalk) exhausted(price float64) bool {
+	return math.Abs(price-w.Limit) < w.tickSize/2
+}
+
+// WalkOrder tracks an order like PollOrderStatus, and moves it one step along walk each time
+// it rests unfilled for walk.Interval. Each step modifies the working order's price in place,
+// so the order keeps its ID and can never be live twice. Once the walk reaches its limit the
+// order is left working until fill or timeout.
+func (m *Manager) WalkOrder(positionID string, orderID int, isEntryOrder bool, walk *PriceWalk) {
+	if walk == nil {
+		m.PollOrderStatus(positionID, orderID, isEntryOrder)
+		return
+	}
+	m.logger.Printf("Walking order %d for position %s from $%.2f toward $%.2f (mid $%.2f, natural $%.2f)",
+		orderID, positionID, walk.Start, walk.Limit, walk.Mid, walk.Natural)
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.config.Timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(m.config.PollInterval)
+	defer ticker.Stop()
+
+	execution := models.OrderExecution{
+		Side:         walk.Side,
+		OrderID:      fmt.Sprintf("%d", orderID),
+		MidPrice:     walk.Mid,
+		NaturalPrice: walk.Natural,
+		StartedAt:    time.Now().UTC(),
+	}
+	price := walk.Start
+	nextStep := time.Now().Add(walk.Interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.logger.Printf("Order walk timeout for position %s, order %d at $%.2f", positionID, orderID, price)
+			m.handleOrderTimeout(positionID)
+			return
+		case <-m.stop:
+			m.logger.Printf("Shutdown signal received while walking order %d for position %s", orderID, positionID)
+			return
+		case <-ticker.C:
+		}
+
+		statusCtx, statusCancel := context.WithTimeout(ctx, m.config.CallTimeout)
+		resp, err := m.broker.GetOrderStatusCtx(statusCtx, orderID)
+		statusCancel()
+		if err != nil || resp == nil || resp.Order.ID == 0 {
+			m.logger.Printf("Could not check walked order %d for position %s: %v", orderID, positionID, err)
+			continue
+		}
+		if m.isOrderCompletelyFilled(resp) {
+			m.recordExecution(positionID, &execution, price, &resp.Order)
+			m.handleOrderFilled(positionID, isEntryOrder)
+			return
+		}
+		if status := strings.ToLower(resp.Order.Status); status != "filled" && isTerminalStatus(status) {
+			m.logger.Printf("Walked order %d for position %s ended: %s", orderID, positionID, resp.Order.Status)
+			if m.settlePartialFill(positionID, &resp.Order) {
+				return
+			}
+			m.handleOrderFailed(positionID, orderID, resp.Order.Status)
+			return
+		}
+
+		if time.Now().Before(nextStep) || walk.exhausted(price) {
+			continue
+		}
+		nextStep = time.Now().Add(walk.Interval)
+		newPrice := walk.next(price)
+
+		// A failed modify leaves the order working at its old price; if it filled, the next poll sees it
+		modifyCtx, modifyCancel := context.WithTimeout(ctx, m.config.CallTimeout)
+		_, err = m.broker.ModifyOrderCtx(modifyCtx, orderID, newPrice, walk.tickSize, "")
+		modifyCancel()
+		if err != nil {
+			m.logger.Printf("Could not re-price order %d for position %s, leaving it working at $%.2f: %v",
+				orderID, positionID, price, err)
+			continue
+		}
+
+		execution.Steps++
+		m.logger.Printf("Re-priced order %d for position %s from $%.2f to $%.2f (step %d)",
+			orderID, positionID, price, newPrice, execution.Steps)
+		price = newPrice
+		if isEntryOrder {
+			m.updateEntryLimit(positionID, price)
+		}
+	}
+}
+
+// updateEntryLimit records the current limit of a walked entry order on its position
+func (m *Manager) updateEntryLimit(positionID string, price float64) {
+	position, found := m.storage.GetPositionByID(positionID)
+	if !found {
+		return
+	}
+	position.EntryLimitPrice = price
+	if err := m.storage.UpdatePosition(&position); err != nil {
+		m.logger.Printf("Failed to record entry limit $%.2f for position %s: %v", price, positionID, err)
+	}
+}
+
+// recordExecution stores the completed walk, with its fill improvement, on the position
+func (m *Manager) recordExecution(positionID string, execution *models.OrderExecution, limit float64, order *broker.Order) {
+	execution.LimitPrice = limit
+	execution.FillPrice = math.Abs(order.AvgFillPrice)
+	if execution.FillPrice == 0 {
+		execution.FillPrice = limit
+	}
+	execution.Improvement = models.PriceImprovement(execution.Side, execution.NaturalPrice, execution.FillPrice)
+	execution.FilledAt = time.Now().UTC()
+
+	m.logger.Printf("Position %s %s filled at $%.2f after %d re-prices: $%.2f better than natural $%.2f (mid $%.2f)",
+		positionID, execution.Side, execution.FillPrice, execution.Steps, execution.Improvement,
+		execution.NaturalPrice, execution.MidPrice)
+
+	position, found := m.storage.GetPositionByID(positionID)
+	if !found {
+		return
+	}
+	position.Executions = append(position.Executions, *execution)
+	if err := m.storage.UpdatePosition(&position); err != nil {
+		m.logger.Printf("Failed to record execution for position %s: %v", positionID, err)
+	}
+}
@@ -0,0 +1,144 @@
+package orders
+
+import (
+	"context"
+	"log"
+	"math"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/storage"
+)
+
+// walkBroker keeps one order working and fills it once it has been re-priced to fillAt
+type walkBroker struct {
+	mockBrokerForOrders
+	mu       sync.Mutex
+	price    float64
+	fillAt   float64
+	modified []float64
+}
+
+func (b *walkBroker) GetOrderStatusCtx(ctx context.Context, orderID int) (*broker.OrderResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	resp := &broker.OrderResponse{}
+	resp.Order.ID = orderID
+	resp.Order.Type = "credit"
+	resp.Order.Quantity = 1
+	resp.Order.Status = "open"
+	if math.Abs(b.price-b.fillAt) < 1e-9 {
+		resp.Order.Status = "filled"
+		resp.Order.ExecQuantity = 1
+		resp.Order.AvgFillPrice = b.price
+	}
+	return resp, nil
+}
+
+func (b *walkBroker) ModifyOrderCtx(ctx context.Context, orderID int, price, tickSize float64, duration string) (*broker.OrderResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.price = price
+	b.modified = append(b.modified, price)
+	resp := &broker.OrderResponse{}
+	resp.Order.ID = orderID
+	resp.Order.Status = "ok"
+	return resp, nil
+}
+
+func TestNewPriceWalk(t *testing.T) {
+	tests := []struct {
+		name         string
+		side         models.ExecutionSide
+		mid, natural float64
+		maxTicks     int
+		start, limit float64
+		wantErr      bool
+	}{
+		{"entry concedes max ticks", models.ExecutionEntry, 2.505, 2.30, 5, 2.50, 2.45, false},
+		{"entry stops at natural", models.ExecutionEntry, 2.50, 2.47, 5, 2.50, 2.47, false},
+		{"exit concedes max ticks", models.ExecutionExit, 1.205, 1.40, 5, 1.21, 1.26, false},
+		{"exit stops at natural", models.ExecutionExit, 1.20, 1.22, 5, 1.20, 1.22, false},
+		{"locked market does not walk", models.ExecutionExit, 1.20, 1.20, 5, 1.20, 1.20, false},
+		{"no natural", models.ExecutionEntry, 2.50, 0, 5, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			walk, err := NewPriceWalk(tt.side, tt.mid, tt.natural, 0.01, 1, tt.maxTicks, time.Second)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if math.Abs(walk.Start-tt.start) > 1e-9 || math.Abs(walk.Limit-tt.limit) > 1e-9 {
+				t.Errorf("Expected start %.2f limit %.2f, got %.2f and %.2f", tt.start, tt.limit, walk.Start, walk.Limit)
+			}
+		})
+	}
+}
+
+func TestManager_WalkOrder_RepricesUntilFilled(t *testing.T) {
+	logger := log.New(os.Stderr, "test: ", log.LstdFlags)
+	mockStorage := storage.NewMockStorage()
+	position := models.NewPosition("walk-pos", "SPY", 400, 460, time.Now().AddDate(0, 0, 45), 1)
+	if err := position.TransitionState(models.StateSubmitted, "order_placed"); err != nil {
+		t.Fatalf("Failed to set up test position: %v", err)
+	}
+	position.EntryOrderID = "100"
+	if err := mockStorage.AddPosition(position); err != nil {
+		t.Fatalf("Failed to set up test position in storage: %v", err)
+	}
+
+	// Mid $2.50, natural $2.40: two-tick steps to $2.48 and $2.46, which fills
+	b := &walkBroker{price: 2.50, fillAt: 2.46}
+	walk, err := NewPriceWalk(models.ExecutionEntry, 2.50, 2.40, 0.01, 2, 5, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to build walk: %v", err)
+	}
+
+	m := NewManager(b, mockStorage, logger, nil, Config{
+		PollInterval: time.Millisecond,
+		Timeout:      2 * time.Second,
+		CallTimeout:  100 * time.Millisecond,
+	})
+	m.WalkOrder("walk-pos", 100, true, walk)
+
+	if len(b.modified) != 2 || math.Abs(b.modified[0]-2.48) > 1e-9 || math.Abs(b.modified[1]-2.46) > 1e-9 {
+		t.Fatalf("Expected order 100 re-priced to 2.48 then 2.46, got %v", b.modified)
+	}
+	if b.callCount != 0 {
+		t.Errorf("Expected no cancel or other base broker calls, got %d", b.callCount)
+	}
+
+	updated, found := mockStorage.GetPositionByID("walk-pos")
+	if !found {
+		t.Fatal("Expected to find updated position")
+	}
+	if updated.GetCurrentState() != models.StateOpen {
+		t.Errorf("Expected position state %s, got %s", models.StateOpen, updated.GetCurrentState())
+	}
+	if updated.EntryOrderID != "100" || math.Abs(updated.CreditReceived-2.46) > 1e-9 {
+		t.Errorf("Expected entry order 100 filled at 2.46, got %s at %.2f", updated.EntryOrderID, updated.CreditReceived)
+	}
+	if math.Abs(updated.EntryLimitPrice-2.46) > 1e-9 {
+		t.Errorf("Expected entry limit to follow the walk to 2.46, got %.2f", updated.EntryLimitPrice)
+	}
+	if len(updated.Executions) != 1 {
+		t.Fatalf("Expected one recorded execution, got %d", len(updated.Executions))
+	}
+	e := updated.Executions[0]
+	if e.Steps != 2 || e.OrderID != "100" {
+		t.Errorf("Unexpected walk record: steps=%d order=%s", e.Steps, e.OrderID)
+	}
+	if math.Abs(e.Improvement-0.06) > 1e-9 {
+		t.Errorf("Expected $0.06 improvement over natural, got %.4f", e.Improvement)
+	}
+}
@@ -0,0 +1,88 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+)
+
+// errWatchStopped reports a watch ended by shutdown while its order was still working
+var errWatchStopped = errors.New("order watch stopped")
+
+// WatchOrder polls an order in the background until it ends, then hands the order as last
+// reported to onDone. An order still working after timeout is canceled and confirmed, so
+// whatever it executed before the cancel is in the order onDone receives; an error means the
+// order's outcome is unknown and it may still be working. Shutdown ends the watch without
+// calling onDone, leaving the order to whoever finds it next.
+func (m *Manager) WatchOrder(orderID int, timeout time.Duration, onDone func(order *broker.Order, err error)) {
+	m.watchMu.Lock()
+	if m.watching == nil {
+		m.watching = make(map[int]bool)
+	}
+	m.watching[orderID] = true
+	m.watchMu.Unlock()
+	m.watches.Add(1)
+
+	go func() {
+		defer m.watches.Done()
+		defer func() {
+			m.watchMu.Lock()
+			delete(m.watching, orderID)
+			m.watchMu.Unlock()
+		}()
+
+		order, err := m.awaitOrder(orderID, timeout)
+		if errors.Is(err, errWatchStopped) {
+			m.logger.Printf("Shutdown signal received while watching order %d", orderID)
+			return
+		}
+		onDone(order, err)
+	}()
+}
+
+// IsWatching reports whether WatchOrder is still tracking an order. It stays true until the
+// watch's onDone has returned.
+func (m *Manager) IsWatching(orderID int) bool {
+	m.watchMu.Lock()
+	defer m.watchMu.Unlock()
+	return m.watching[orderID]
+}
+
+// WaitForWatches blocks until every order watch has ended
+func (m *Manager) WaitForWatches() {
+	m.watches.Wait()
+}
+
+// awaitOrder polls orderID until it reaches a terminal state, canceling it once timeout passes
+func (m *Manager) awaitOrder(orderID int, timeout time.Duration) (*broker.Order, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		statusCtx, statusCancel := context.WithTimeout(context.Background(), m.config.CallTimeout)
+		resp, err := m.broker.GetOrderStatusCtx(statusCtx, orderID)
+		statusCancel()
+		if err != nil {
+			m.logger.Printf("Error checking watched order %d: %v", orderID, err)
+		} else if resp != nil && resp.Order.ID != 0 && isTerminalStatus(strings.ToLower(resp.Order.Status)) {
+			return &resp.Order, nil
+		}
+
+		select {
+		case <-m.stop:
+			return nil, errWatchStopped
+		case <-deadline.C:
+			m.logger.Printf("Watched order %d not filled within %v, canceling", orderID, timeout)
+			order, err := m.CancelAndConfirm(context.Background(), orderID)
+			if err != nil {
+				return nil, fmt.Errorf("canceling order %d: %w", orderID, err)
+			}
+			return order, nil
+		case <-time.After(m.config.PollInterval):
+		}
+	}
+}
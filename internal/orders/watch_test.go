@@ -0,0 +1,83 @@
+package orders
+
+import (
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/storage"
+)
+
+func TestManager_WatchOrder_HandsOverFilledOrder(t *testing.T) {
+	order := strangleOrder(700, "sell_to_open", 1, 1, 1.30, 1.20)
+	order.Status = "filled"
+	b := &partialFillBroker{order: order}
+	m := newPartialFillManager(b, storage.NewMockStorage())
+
+	var got *broker.Order
+	m.WatchOrder(700, time.Second, func(order *broker.Order, err error) {
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if !m.IsWatching(700) {
+			t.Error("Expected order 700 to stay watched until onDone returns")
+		}
+		got = order
+	})
+	m.WaitForWatches()
+
+	if got == nil || got.Status != "filled" {
+		t.Fatalf("Expected the filled order, got %+v", got)
+	}
+	if len(b.canceled) != 0 {
+		t.Errorf("Expected no cancel for a filled order, got %v", b.canceled)
+	}
+	if m.IsWatching(700) {
+		t.Error("Expected order 700 to be unwatched once its watch ended")
+	}
+}
+
+func TestManager_WatchOrder_CancelsAfterTimeout(t *testing.T) {
+	b := &partialFillBroker{order: strangleOrder(701, "sell_to_open", 3, 2, 1.30, 1.20)}
+	m := newPartialFillManager(b, storage.NewMockStorage())
+
+	var got *broker.Order
+	m.WatchOrder(701, 20*time.Millisecond, func(order *broker.Order, err error) {
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		got = order
+	})
+	m.WaitForWatches()
+
+	if len(b.canceled) != 1 || b.canceled[0] != 701 {
+		t.Fatalf("Expected order 701 to be canceled once, got %v", b.canceled)
+	}
+	if got == nil || got.Status != "canceled" || got.ExecQuantity != 2 {
+		t.Errorf("Expected the canceled order with its 2 executed contracts, got %+v", got)
+	}
+}
+
+func TestManager_WatchOrder_StopSkipsCallback(t *testing.T) {
+	logger := log.New(os.Stderr, "test: ", log.LstdFlags)
+	stop := make(chan struct{})
+	b := &partialFillBroker{order: strangleOrder(702, "sell_to_open", 1, 0, 0, 0)}
+	m := NewManager(b, storage.NewMockStorage(), logger, stop, Config{
+		PollInterval: time.Millisecond,
+		CallTimeout:  100 * time.Millisecond,
+	})
+
+	called := false
+	m.WatchOrder(702, time.Minute, func(*broker.Order, error) { called = true })
+	close(stop)
+	m.WaitForWatches()
+
+	if called {
+		t.Error("Expected shutdown to end the watch without calling onDone")
+	}
+	if len(b.canceled) != 0 {
+		t.Errorf("Expected shutdown to leave the order working, got cancels %v", b.canceled)
+	}
+}
@@ -0,0 +1,153 @@
+// Package pricing implements Black-Scholes-Merton option pricing, greeks and an
+// implied volatility solver. It is used to fill in greeks when the broker's option
+// chain omits them, as the Tradier sandbox often does and production does shortly
+// after the open.
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// minVol and maxVol bound the implied volatility search (0.01% to 500%)
+	minVol = 0.0001
+	maxVol = 5.0
+	// ivPriceTolerance is the pricing error at which the IV solver stops, in dollars per share
+	ivPriceTolerance = 1e-6
+	// maxIVIterations caps the IV solver; bisection alone converges well within this
+	maxIVIterations = 100
+	// daysPerYear converts annual theta to the per-day theta the broker reports
+	daysPerYear = 365.0
+)
+
+// ErrPriceOutOfBounds is returned when an option price lies outside the no-arbitrage
+// bounds, so no volatility reproduces it.
+var ErrPriceOutOfBounds = errors.New("price outside no-arbitrage bounds")
+
+// Model holds the market inputs shared by every option on an underlying.
+// Rates are annualised and continuously compounded (0.045 = 4.5%).
+type Model struct {
+	RiskFreeRate  float64
+	DividendYield float64
+}
+
+// Greeks holds option sensitivities in the broker's units: theta per calendar day,
+// and vega, rho and phi per one percentage point.
+type Greeks struct {
+	Delta float64
+	Gamma float64
+	Theta float64
+	Vega  float64
+	Rho   float64
+	Phi   float64
+}
+
+// d1d2 returns the Black-Scholes d1 and d2 terms
+func (m Model) d1d2(spot, strike, years, vol float64) (float64, float64) {
+	volSqrtT := vol * math.Sqrt(years)
+	d1 := (math.Log(spot/strike) + (m.RiskFreeRate-m.DividendYield+vol*vol/2)*years) / volSqrtT
+	return d1, d1 - volSqrtT
+}
+
+// Price returns the theoretical price per share of a European option
+func (m Model) Price(isCall bool, spot, strike, years, vol float64) float64 {
+	if years <= 0 || vol <= 0 {
+		return intrinsic(isCall, spot, strike)
+	}
+	d1, d2 := m.d1d2(spot, strike, years, vol)
+	discS := spot * math.Exp(-m.DividendYield*years)
+	discK := strike * math.Exp(-m.RiskFreeRate*years)
+	if isCall {
+		return discS*normCDF(d1) - discK*normCDF(d2)
+	}
+	return discK*normCDF(-d2) - discS*normCDF(-d1)
+}
+
+// Greeks returns the option sensitivities at the given volatility
+func (m Model) Greeks(isCall bool, spot, strike, years, vol float64) Greeks {
+	if years <= 0 || vol <= 0 || spot <= 0 || strike <= 0 {
+		return Greeks{}
+	}
+	d1, d2 := m.d1d2(spot, strike, years, vol)
+	sqrtT := math.Sqrt(years)
+	divDisc := math.Exp(-m.DividendYield * years)
+	rateDisc := math.Exp(-m.RiskFreeRate * years)
+	pdf := normPDF(d1)
+
+	g := Greeks{
+		Gamma: divDisc * pdf / (spot * vol * sqrtT),
+		Vega:  spot * divDisc * pdf * sqrtT / 100,
+	}
+	decay := -spot * divDisc * pdf * vol / (2 * sqrtT)
+	if isCall {
+		g.Delta = divDisc * normCDF(d1)
+		g.Theta = (decay - m.RiskFreeRate*strike*rateDisc*normCDF(d2) + m.DividendYield*spot*divDisc*normCDF(d1)) / daysPerYear
+		g.Rho = strike * years * rateDisc * normCDF(d2) / 100
+		g.Phi = -spot * years * divDisc * normCDF(d1) / 100
+	} else {
+		g.Delta = divDisc * (normCDF(d1) - 1)
+		g.Theta = (decay + m.RiskFreeRate*strike*rateDisc*normCDF(-d2) - m.DividendYield*spot*divDisc*normCDF(-d1)) / daysPerYear
+		g.Rho = -strike * years * rateDisc * normCDF(-d2) / 100
+		g.Phi = spot * years * divDisc * normCDF(-d1) / 100
+	}
+	return g
+}
+
+// ImpliedVol solves for the volatility that reproduces price. It uses Newton steps
+// on vega, falling back to bisection whenever a step leaves the bracket.
+func (m Model) ImpliedVol(isCall bool, price, spot, strike, years float64) (float64, error) {
+	if spot <= 0 || strike <= 0 || years <= 0 {
+		return 0, fmt.Errorf("invalid inputs: spot %.4f, strike %.4f, years %.6f", spot, strike, years)
+	}
+
+	lower := m.Price(isCall, spot, strike, years, minVol)
+	upper := m.Price(isCall, spot, strike, years, maxVol)
+	if price <= lower || price >= upper {
+		return 0, fmt.Errorf("%w: %.4f not in (%.4f, %.4f)", ErrPriceOutOfBounds, price, lower, upper)
+	}
+
+	lo, hi := minVol, maxVol
+	vol := 0.2
+	for i := 0; i < maxIVIterations; i++ {
+		diff := m.Price(isCall, spot, strike, years, vol) - price
+		if math.Abs(diff) < ivPriceTolerance {
+			return vol, nil
+		}
+		// Price increases with volatility, so the sign of diff narrows the bracket
+		if diff > 0 {
+			hi = vol
+		} else {
+			lo = vol
+		}
+
+		next := vol
+		if vega := m.Greeks(isCall, spot, strike, years, vol).Vega * 100; vega > 1e-10 {
+			next = vol - diff/vega
+		}
+		if next <= lo || next >= hi || next == vol {
+			next = (lo + hi) / 2
+		}
+		vol = next
+	}
+	return vol, nil
+}
+
+// intrinsic returns the exercise value of an option per share
+func intrinsic(isCall bool, spot, strike float64) float64 {
+	if isCall {
+		return math.Max(spot-strike, 0)
+	}
+	return math.Max(strike-spot, 0)
+}
+
+// normCDF is the standard normal cumulative distribution function
+func normCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// normPDF is the standard normal probability density function
+func normPDF(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
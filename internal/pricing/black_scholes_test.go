@@ -0,0 +1,150 @@
+package pricing
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+)
+
+func TestModel_PriceMatchesReferenceValues(t *testing.T) {
+	// Hull, Options, Futures and Other Derivatives: S=42, K=40, r=10%, sigma=20%, T=0.5
+	m := Model{RiskFreeRate: 0.10}
+	if got := m.Price(true, 42, 40, 0.5, 0.20); math.Abs(got-4.76) > 0.005 {
+		t.Errorf("call price = %.4f, want 4.76", got)
+	}
+	if got := m.Price(false, 42, 40, 0.5, 0.20); math.Abs(got-0.81) > 0.005 {
+		t.Errorf("put price = %.4f, want 0.81", got)
+	}
+}
+
+func TestModel_PutCallParity(t *testing.T) {
+	m := Model{RiskFreeRate: 0.045, DividendYield: 0.013}
+	spot, strike, years, vol := 450.0, 440.0, 45.0/365, 0.18
+
+	call := m.Price(true, spot, strike, years, vol)
+	put := m.Price(false, spot, strike, years, vol)
+	parity := spot*math.Exp(-m.DividendYield*years) - strike*math.Exp(-m.RiskFreeRate*years)
+	if math.Abs(call-put-parity) > 1e-9 {
+		t.Errorf("call - put = %.6f, want %.6f", call-put, parity)
+	}
+
+	cg := m.Greeks(true, spot, strike, years, vol)
+	pg := m.Greeks(false, spot, strike, years, vol)
+	if math.Abs(cg.Delta-pg.Delta-math.Exp(-m.DividendYield*years)) > 1e-9 {
+		t.Errorf("call delta - put delta = %.6f, want e^-qT", cg.Delta-pg.Delta)
+	}
+	if cg.Gamma != pg.Gamma || cg.Vega != pg.Vega {
+		t.Errorf("gamma and vega should match for calls and puts: %+v vs %+v", cg, pg)
+	}
+	if cg.Theta >= 0 || pg.Theta >= 0 {
+		t.Errorf("expected negative theta, got call %.4f put %.4f", cg.Theta, pg.Theta)
+	}
+}
+
+func TestModel_GreeksMatchFiniteDifferences(t *testing.T) {
+	m := Model{RiskFreeRate: 0.045, DividendYield: 0.013}
+	spot, strike, years, vol := 450.0, 430.0, 45.0/365, 0.20
+	const h = 0.01
+
+	for _, isCall := range []bool{true, false} {
+		g := m.Greeks(isCall, spot, strike, years, vol)
+		price := func(s, t, v float64) float64 { return m.Price(isCall, s, strike, t, v) }
+
+		delta := (price(spot+h, years, vol) - price(spot-h, years, vol)) / (2 * h)
+		gamma := (price(spot+h, years, vol) - 2*price(spot, years, vol) + price(spot-h, years, vol)) / (h * h)
+		vega := (price(spot, years, vol+0.0001) - price(spot, years, vol-0.0001)) / 0.0002 / 100
+		theta := (price(spot, years-1/daysPerYear, vol) - price(spot, years, vol))
+
+		checks := []struct {
+			name      string
+			got, want float64
+			tol       float64
+		}{
+			{"delta", g.Delta, delta, 1e-5},
+			{"gamma", g.Gamma, gamma, 1e-4},
+			{"vega", g.Vega, vega, 1e-5},
+			{"theta", g.Theta, theta, 2e-3},
+		}
+		for _, c := range checks {
+			if math.Abs(c.got-c.want) > c.tol {
+				t.Errorf("call=%v %s = %.6f, finite difference %.6f", isCall, c.name, c.got, c.want)
+			}
+		}
+	}
+}
+
+func TestModel_ImpliedVolRoundTrip(t *testing.T) {
+	m := Model{RiskFreeRate: 0.045, DividendYield: 0.013}
+	years := 30.0 / 365
+
+	for _, tt := range []struct {
+		isCall bool
+		strike float64
+		vol    float64
+	}{
+		{true, 450, 0.15},
+		{false, 400, 0.35},
+		{true, 500, 0.12},
+		{false, 449, 0.80},
+	} {
+		price := m.Price(tt.isCall, 450, tt.strike, years, tt.vol)
+		got, err := m.ImpliedVol(tt.isCall, price, 450, tt.strike, years)
+		if err != nil {
+			t.Fatalf("ImpliedVol(%v, K=%.0f) error: %v", tt.isCall, tt.strike, err)
+		}
+		if math.Abs(got-tt.vol) > 1e-4 {
+			t.Errorf("ImpliedVol(%v, K=%.0f) = %.6f, want %.4f", tt.isCall, tt.strike, got, tt.vol)
+		}
+	}
+
+	// A put priced below intrinsic value cannot be reproduced
+	if _, err := m.ImpliedVol(false, 1.0, 400, 450, years); !errors.Is(err, ErrPriceOutOfBounds) {
+		t.Errorf("expected ErrPriceOutOfBounds, got %v", err)
+	}
+}
+
+func TestModel_FillMissingGreeks(t *testing.T) {
+	m := Model{RiskFreeRate: 0.045, DividendYield: 0.013}
+	now := time.Date(2025, 3, 3, 10, 0, 0, 0, newYork)
+	exp := "2025-04-17"
+	years, err := YearsToExpiration(exp, now)
+	if err != nil {
+		t.Fatalf("YearsToExpiration error: %v", err)
+	}
+	putPrice := m.Price(false, 450, 420, years, 0.22)
+
+	brokerGreeks := &broker.Greeks{Delta: 0.31, Gamma: 0.01, MidIV: 0.17}
+	chain := []broker.Option{
+		{Strike: 420, OptionType: "put", ExpirationDate: exp, Bid: putPrice - 0.05, Ask: putPrice + 0.05},
+		{Strike: 470, OptionType: "call", ExpirationDate: exp, Bid: 4.0, Ask: 4.2, Greeks: brokerGreeks},
+		{Strike: 480, OptionType: "call", ExpirationDate: exp, Greeks: &broker.Greeks{MidIV: 0.15}},
+		{Strike: 490, OptionType: "call", ExpirationDate: exp}, // No quote and no greeks
+	}
+
+	out, filled := m.FillMissingGreeks(chain, 450, now)
+	if filled != 2 {
+		t.Errorf("filled = %d, want 2", filled)
+	}
+	if chain[0].Greeks != nil {
+		t.Error("input chain was modified")
+	}
+
+	put := out[0].Greeks
+	if put == nil || math.Abs(put.MidIV-0.22) > 1e-4 || put.Delta >= 0 || put.Delta < -0.5 {
+		t.Errorf("put greeks not solved from mid: %+v", put)
+	}
+	if out[1].Greeks != brokerGreeks {
+		t.Error("broker-supplied greeks should be kept as-is")
+	}
+	call := out[2].Greeks
+	want := m.Greeks(true, 450, 480, years, 0.15)
+	if call == nil || call.MidIV != 0.15 || math.Abs(call.Delta-want.Delta) > 1e-12 {
+		t.Errorf("call greeks should use broker mid IV: %+v", call)
+	}
+	if out[3].Greeks != nil {
+		t.Errorf("unpriced option should stay without greeks: %+v", out[3].Greeks)
+	}
+}
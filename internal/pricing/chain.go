@@ -0,0 +1,93 @@
+package pricing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+)
+
+// expirationCloseHour is when equity options stop trading on expiration day, New York time
+const expirationCloseHour = 16
+
+// newYork is the exchange timezone used to place the expiration close
+var newYork = func() *time.Location {
+	if loc, err := time.LoadLocation("America/New_York"); err == nil {
+		return loc
+	}
+	return time.UTC
+}()
+
+// YearsToExpiration returns the time from now until the 4pm ET close on the
+// expiration date (YYYY-MM-DD), in years. It is zero or negative once expired.
+func YearsToExpiration(expiration string, now time.Time) (float64, error) {
+	d, err := time.ParseInLocation("2006-01-02", expiration, newYork)
+	if err != nil {
+		return 0, fmt.Errorf("invalid expiration %q: %w", expiration, err)
+	}
+	expiry := d.Add(expirationCloseHour * time.Hour)
+	return expiry.Sub(now).Hours() / (24 * daysPerYear), nil
+}
+
+// FillMissingGreeks returns a copy of chain in which options without greeks, or
+// without a mid IV, get values computed locally. Volatility comes from the broker's
+// mid IV when present, otherwise it is solved from the bid/ask mid (or last trade).
+// Greeks the broker did supply are kept. filled counts the options that changed.
+func (m Model) FillMissingGreeks(chain []broker.Option, spot float64, now time.Time) (out []broker.Option, filled int) {
+	out = make([]broker.Option, len(chain))
+	copy(out, chain)
+	if spot <= 0 {
+		return out, 0
+	}
+
+	for i := range out {
+		opt := &out[i]
+		needsIV := opt.Greeks == nil || opt.Greeks.MidIV <= 0
+		needsGreeks := opt.Greeks == nil || (opt.Greeks.Delta == 0 && opt.Greeks.Gamma == 0)
+		if !needsIV && !needsGreeks {
+			continue
+		}
+
+		isCall := broker.OptionTypeMatches(opt.OptionType, broker.OptionTypeCall)
+		if !isCall && !broker.OptionTypeMatches(opt.OptionType, broker.OptionTypePut) {
+			continue
+		}
+		years, err := YearsToExpiration(opt.ExpirationDate, now)
+		if err != nil || years <= 0 {
+			continue
+		}
+
+		var g broker.Greeks
+		if opt.Greeks != nil {
+			g = *opt.Greeks
+		}
+		vol := g.MidIV
+		if needsIV {
+			price := marketPrice(*opt)
+			if price <= 0 {
+				continue
+			}
+			vol, err = m.ImpliedVol(isCall, price, spot, opt.Strike, years)
+			if err != nil {
+				continue
+			}
+			g.MidIV = vol
+		}
+		if needsGreeks {
+			computed := m.Greeks(isCall, spot, opt.Strike, years, vol)
+			g.Delta, g.Gamma, g.Theta, g.Vega = computed.Delta, computed.Gamma, computed.Theta, computed.Vega
+			g.Rho, g.Phi = computed.Rho, computed.Phi
+		}
+		opt.Greeks = &g
+		filled++
+	}
+	return out, filled
+}
+
+// marketPrice returns the bid/ask mid, or the last trade when there is no usable quote
+func marketPrice(opt broker.Option) float64 {
+	if opt.Ask > 0 && opt.Bid >= 0 && opt.Ask >= opt.Bid {
+		return (opt.Bid + opt.Ask) / 2
+	}
+	return opt.Last
+}
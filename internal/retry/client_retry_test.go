@@ -61,6 +61,14 @@ func (f *fakeBroker) GetQuote(symbol string) (*broker.QuoteItem, error) {
 	return &broker.QuoteItem{}, nil
 }
 
+func (f *fakeBroker) GetQuotes(symbols []string) ([]broker.QuoteItem, error) {
+	return []broker.QuoteItem{}, nil
+}
+
+func (f *fakeBroker) GetQuotesCtx(ctx context.Context, symbols []string) ([]broker.QuoteItem, error) {
+	return []broker.QuoteItem{}, nil
+}
+
 func (f *fakeBroker) GetExpirations(symbol string) ([]string, error) {
 	return []string{}, nil
 }
@@ -128,6 +136,14 @@ func (f *fakeBroker) PlaceSellToCloseOrder(optionSymbol string, quantity int, ma
 	return &broker.OrderResponse{}, nil
 }
 
+func (f *fakeBroker) PlaceMultiLegOrder(legs []broker.OrderLeg, priceType broker.NetPriceType, price float64, duration string, tag string) (*broker.OrderResponse, error) {
+	return &broker.OrderResponse{}, nil
+}
+
+func (f *fakeBroker) PlaceMultiLegOrderCtx(ctx context.Context, legs []broker.OrderLeg, priceType broker.NetPriceType, price float64, duration string, tag string) (*broker.OrderResponse, error) {
+	return &broker.OrderResponse{}, nil
+}
+
 func (f *fakeBroker) PlaceBuyToCloseMarketOrder(optionSymbol string, quantity int, duration string, tag string) (*broker.OrderResponse, error) {
 	return &broker.OrderResponse{}, nil
 }
@@ -209,6 +225,14 @@ func (f *fakeBroker) CancelOrderCtx(ctx context.Context, orderID int) (*broker.O
 	return f.successResponse(), nil
 }
 
+func (f *fakeBroker) ModifyOrder(orderID int, price, tickSize float64, duration string) (*broker.OrderResponse, error) {
+	return f.successResponse(), nil
+}
+
+func (f *fakeBroker) ModifyOrderCtx(ctx context.Context, orderID int, price, tickSize float64, duration string) (*broker.OrderResponse, error) {
+	return f.successResponse(), nil
+}
+
 func (f *fakeBroker) GetOrders() (*broker.OrdersResponse, error) {
 	return &broker.OrdersResponse{}, nil
 }
@@ -4,3 +4,6 @@ import "errors"
 
 // ErrNoIVReadings is returned when no IV readings are found for a symbol
 var ErrNoIVReadings = errors.New("no IV readings found")
+
+// ErrSchemaTooNew is returned when a storage file was written by a newer schema version
+var ErrSchemaTooNew = errors.New("storage file schema is newer than this build supports")
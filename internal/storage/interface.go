@@ -1,6 +1,9 @@
 package storage
 
 import (
+	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/eddiefleurent/scranton_strangler/internal/models"
@@ -11,13 +14,14 @@ import (
 // Implementations must be safe for concurrent use - callers can assume all methods
 // are goroutine-safe and can safely call these methods from multiple goroutines.
 //
-// The provided JSONStorage implementation uses sync.RWMutex to serialize access,
-// ensuring all Interface methods are protected for concurrent readers and writers.
+// The provided JSONStorage and SQLiteStorage implementations use sync.RWMutex to serialize
+// access, ensuring all Interface methods are protected for concurrent readers and writers.
 type Interface interface {
 	// Position management
 	GetCurrentPositions() []models.Position
 	AddPosition(pos *models.Position) error
 	UpdatePosition(pos *models.Position) error
+	// ClosePositionByID moves a position to history; finalPnL is gross of the position's Fees, which are deducted
 	ClosePositionByID(id string, finalPnL float64, reason string) error
 	// GetPositionByID retrieves a position by ID, returning a copy to ensure thread safety.
 	// Returns (position, true) if found, or (zero-value, false) if not found.
@@ -38,16 +42,53 @@ type Interface interface {
 	GetStatistics() *Statistics
 	GetDailyPnL(date string) float64
 
+	// Risk circuit breaker. GetTradingHalt returns a copy of the last recorded halt, or nil;
+	// SetTradingHalt persists a halt, and nil clears it.
+	GetTradingHalt() *models.TradingHalt
+	SetTradingHalt(halt *models.TradingHalt) error
+
+	// Operator entry pause. GetEntryPause returns a copy of the pause in effect, or nil;
+	// SetEntryPause persists a pause, and nil resumes entries.
+	GetEntryPause() *models.EntryPause
+	SetEntryPause(pause *models.EntryPause) error
+
 	// IV data storage
 	StoreIVReading(reading *models.IVReading) error
 	GetIVReadings(symbol string, startDate, endDate time.Time) ([]models.IVReading, error)
 	GetLatestIVReading(symbol string) (*models.IVReading, error)
 }
 
-// NewStorage creates a new storage implementation (currently JSON-based)
-// In the future, this can be extended to support different storage backends
-func NewStorage(filepath string) (Interface, error) {
-	return NewJSONStorage(filepath)
+// Storage backends, as named by storage.backend
+const (
+	BackendJSON   = "json"
+	BackendSQLite = "sqlite"
+)
+
+// NewStorage opens the named storage backend at path. An empty backend is chosen from the
+// path by BackendFor.
+func NewStorage(backend, path string) (Interface, error) {
+	if backend == "" {
+		backend = BackendFor(path)
+	}
+	switch strings.ToLower(backend) {
+	case BackendJSON:
+		return NewJSONStorage(path)
+	case BackendSQLite:
+		return NewSQLiteStorage(path)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", backend)
+	}
+}
+
+// BackendFor returns the backend for a path when none is named: SQLite for .db, .sqlite and
+// .sqlite3 files, JSON for anything else
+func BackendFor(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".db", ".sqlite", ".sqlite3":
+		return BackendSQLite
+	default:
+		return BackendJSON
+	}
 }
 
 // Ensure JSONStorage implements Interface
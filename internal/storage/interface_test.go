@@ -27,6 +27,15 @@ func TestInterface(t *testing.T) {
 		}
 		testInterface(t, storage)
 	})
+	// Test with SQLiteStorage (using temporary database)
+	t.Run("SQLiteStorage", func(t *testing.T) {
+		storage, err := NewSQLiteStorage(fmt.Sprintf("%s/test_positions.db", t.TempDir()))
+		if err != nil {
+			t.Fatalf("Failed to create SQLite storage: %v", err)
+		}
+		t.Cleanup(func() { _ = storage.Close() })
+		testInterface(t, storage)
+	})
 }
 
 // testInterface runs common tests on any storage implementation
@@ -583,14 +592,32 @@ func TestInterfaceCompliance(t *testing.T) {
 	// Test that both implementations satisfy the interface
 	var _ Interface = (*MockStorage)(nil)
 	var _ Interface = (*JSONStorage)(nil)
+	var _ Interface = (*SQLiteStorage)(nil)
 
-	// Test factory function
-	tmpFile := fmt.Sprintf("%s/factory.json", t.TempDir())
-	storage, err := NewStorage(tmpFile)
-	if err != nil {
-		t.Fatalf("Factory function failed: %v", err)
+	// Test factory function, naming the backend or leaving it to the file extension
+	testCases := []struct {
+		backend, file string
+		want          string
+	}{
+		{"", "factory.json", "*storage.JSONStorage"},
+		{"", "factory.db", "*storage.SQLiteStorage"},
+		{BackendSQLite, "factory.data", "*storage.SQLiteStorage"},
+		{BackendJSON, "factory.sqlite", "*storage.JSONStorage"},
+	}
+	for _, tc := range testCases {
+		storage, err := NewStorage(tc.backend, fmt.Sprintf("%s/%s", t.TempDir(), tc.file))
+		if err != nil {
+			t.Fatalf("Factory function failed for %q %s: %v", tc.backend, tc.file, err)
+		}
+		if got := fmt.Sprintf("%T", storage); got != tc.want {
+			t.Errorf("NewStorage(%q, %s) returned %s, want %s", tc.backend, tc.file, got, tc.want)
+		}
+		if s, ok := storage.(*SQLiteStorage); ok {
+			_ = s.Close()
+		}
 	}
 
-	// Ensure factory returns the interface
-	_ = storage
+	if _, err := NewStorage("postgres", fmt.Sprintf("%s/factory", t.TempDir())); err == nil {
+		t.Error("Expected an error for an unknown backend")
+	}
 }
@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+)
+
+// Importer is implemented by backends that can be filled from, and read back as, a whole
+// Data snapshot. cmd/migrate uses it to move a JSON file's contents into another backend.
+type Importer interface {
+	Interface
+	// ImportData replaces everything the backend holds with data and persists it
+	ImportData(data *Data) error
+	// ExportData returns a deep copy of everything the backend holds
+	ExportData() *Data
+}
+
+// Ensure JSONStorage implements Importer
+var _ Importer = (*JSONStorage)(nil)
+
+// RowCounts is the number of records of each kind in a Data snapshot
+type RowCounts struct {
+	CurrentPositions int
+	History          int
+	IVReadings       int
+	DailyPnLDays     int
+	Adjustments      int  // Across current positions and history
+	TradingHalt      bool // Whether a trading halt is recorded
+	EntryPause       bool // Whether an entry pause is in effect
+}
+
+// Counts returns the number of records of each kind in d
+func (d *Data) Counts() RowCounts {
+	counts := RowCounts{
+		CurrentPositions: len(d.CurrentPositions),
+		History:          len(d.History),
+		IVReadings:       len(d.IVReadings),
+		DailyPnLDays:     len(d.DailyPnL),
+		TradingHalt:      d.TradingHalt != nil,
+		EntryPause:       d.EntryPause != nil,
+	}
+	for i := range d.CurrentPositions {
+		counts.Adjustments += len(d.CurrentPositions[i].Adjustments)
+	}
+	for i := range d.History {
+		counts.Adjustments += len(d.History[i].Adjustments)
+	}
+	return counts
+}
+
+// ReadDataFile decodes a JSONStorage file, upgrading its schema in memory, without opening
+// it as a backend
+func ReadDataFile(path string) (*Data, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	upgraded, _, err := upgradeSchema(raw)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var data Data
+	if err := json.Unmarshal(upgraded, &data); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	if data.DailyPnL == nil {
+		data.DailyPnL = make(map[string]float64)
+	}
+	return &data, nil
+}
+
+// RecomputeStatistics rebuilds Statistics by replaying closed positions in the order they
+// were closed. History P&L is already net of fees, as recorded by ClosePositionByID.
+func RecomputeStatistics(history []models.Position) *Statistics {
+	stats := &Statistics{}
+	for i := range history {
+		stats.record(history[i].CurrentPnL, history[i].Fees)
+	}
+	return stats
+}
+
+// StatisticsDiff lists the totals on which got differs from want by more than a cent
+func StatisticsDiff(want, got *Statistics) []string {
+	if want == nil {
+		want = &Statistics{}
+	}
+	if got == nil {
+		got = &Statistics{}
+	}
+
+	var diffs []string
+	ints := []struct {
+		name      string
+		want, got int
+	}{
+		{"total_trades", want.TotalTrades, got.TotalTrades},
+		{"winning_trades", want.WinningTrades, got.WinningTrades},
+		{"losing_trades", want.LosingTrades, got.LosingTrades},
+		{"break_even_trades", want.BreakEvenTrades, got.BreakEvenTrades},
+	}
+	for _, f := range ints {
+		if f.want != f.got {
+			diffs = append(diffs, fmt.Sprintf("%s: want %d, got %d", f.name, f.want, f.got))
+		}
+	}
+	floats := []struct {
+		name      string
+		want, got float64
+	}{
+		{"total_pnl", want.TotalPnL, got.TotalPnL},
+		{"total_fees", want.TotalFees, got.TotalFees},
+		{"max_single_trade_loss", want.MaxSingleTradeLoss, got.MaxSingleTradeLoss},
+	}
+	for _, f := range floats {
+		if math.Abs(f.want-f.got) > 0.005 {
+			diffs = append(diffs, fmt.Sprintf("%s: want %.2f, got %.2f", f.name, f.want, f.got))
+		}
+	}
+	return diffs
+}
+
+// VerifyImport checks that dst holds the same number of records as src, the same trading
+// halt and entry pause, and statistics that agree with its own history, returning every
+// mismatch found
+func VerifyImport(src, dst *Data) []string {
+	var problems []string
+	want, got := src.Counts(), dst.Counts()
+	if want != got {
+		problems = append(problems, fmt.Sprintf("row counts: want %+v, got %+v", want, got))
+	}
+	if want.TradingHalt && got.TradingHalt && !sameTradingHalt(src.TradingHalt, dst.TradingHalt) {
+		problems = append(problems, fmt.Sprintf("trading halt: want %+v, got %+v", *src.TradingHalt, *dst.TradingHalt))
+	}
+	if want.EntryPause && got.EntryPause && !sameEntryPause(src.EntryPause, dst.EntryPause) {
+		problems = append(problems, fmt.Sprintf("entry pause: want %+v, got %+v", *src.EntryPause, *dst.EntryPause))
+	}
+	for _, diff := range StatisticsDiff(RecomputeStatistics(dst.History), dst.Statistics) {
+		problems = append(problems, "statistics "+diff)
+	}
+	for _, diff := range StatisticsDiff(RecomputeStatistics(src.History), dst.Statistics) {
+		problems = append(problems, "statistics vs source history "+diff)
+	}
+	return problems
+}
+
+// sameTradingHalt reports whether two recorded halts agree, comparing times by instant
+func sameTradingHalt(a, b *models.TradingHalt) bool {
+	return a.Date == b.Date && a.Reason == b.Reason && a.TriggeredAt.Equal(b.TriggeredAt) &&
+		a.DailyPnL == b.DailyPnL && a.LossLimit == b.LossLimit && a.Flattened == b.Flattened
+}
+
+// sameEntryPause reports whether two entry pauses agree, comparing times by instant
+func sameEntryPause(a, b *models.EntryPause) bool {
+	return a.Reason == b.Reason && a.PausedAt.Equal(b.PausedAt) && a.PausedBy == b.PausedBy
+}
+
+// ImportData replaces the stored data with a copy of data and saves it
+func (s *JSONStorage) ImportData(data *Data) error {
+	if data == nil {
+		return fmt.Errorf("cannot import nil data")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.data
+	s.data = data
+	s.data = s.createDataSnapshot()
+
+	if err := s.saveUnsafe(); err != nil {
+		s.data = previous
+		return err
+	}
+	return nil
+}
+
+// ExportData returns a deep copy of the stored data
+func (s *JSONStorage) ExportData() *Data {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.createDataSnapshot()
+}
@@ -0,0 +1,236 @@
+package storage
+
+import (
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+)
+
+// closeTestPosition opens and closes a one-lot position in s with the given gross P&L
+func closeTestPosition(t *testing.T, s *JSONStorage, id string, pnl, fees float64) {
+	t.Helper()
+	position := models.NewPosition(id, "SPY", 400, 460, time.Now().AddDate(0, 0, 45), 1)
+	if err := position.TransitionState(models.StateSubmitted, models.ConditionOrderPlaced); err != nil {
+		t.Fatalf("Failed to set up position: %v", err)
+	}
+	if err := position.TransitionState(models.StateOpen, models.ConditionOrderFilled); err != nil {
+		t.Fatalf("Failed to set up position: %v", err)
+	}
+	position.Quantity = 1
+	position.CreditReceived = 2.50
+	position.Fees = fees
+	if err := s.AddPosition(position); err != nil {
+		t.Fatalf("AddPosition failed: %v", err)
+	}
+	if err := s.ClosePositionByID(id, pnl, ""); err != nil {
+		t.Fatalf("ClosePositionByID failed: %v", err)
+	}
+}
+
+func TestRecomputeStatistics_MatchesIncrementalStatistics(t *testing.T) {
+	s, err := NewJSONStorage(filepath.Join(mustTempDir(t), "source.json"))
+	if err != nil {
+		t.Fatalf("NewJSONStorage failed: %v", err)
+	}
+	closeTestPosition(t, s, "win-1", 125, 0.70)
+	closeTestPosition(t, s, "loss-1", -300, 1.40)
+	closeTestPosition(t, s, "win-2", 90, 0.70)
+
+	recomputed := RecomputeStatistics(s.GetHistory())
+	if diffs := StatisticsDiff(s.GetStatistics(), recomputed); len(diffs) > 0 {
+		t.Errorf("Expected recomputed statistics to match, got %v", diffs)
+	}
+	if recomputed.CurrentStreak != s.GetStatistics().CurrentStreak {
+		t.Errorf("Expected streak %d, got %d", s.GetStatistics().CurrentStreak, recomputed.CurrentStreak)
+	}
+}
+
+func TestJSONStorage_ImportData(t *testing.T) {
+	dir := mustTempDir(t)
+	sourcePath := filepath.Join(dir, "source.json")
+	source, err := NewJSONStorage(sourcePath)
+	if err != nil {
+		t.Fatalf("NewJSONStorage failed: %v", err)
+	}
+	closeTestPosition(t, source, "closed-1", 125, 0.70)
+	open := models.NewPosition("open-1", "SPY", 410, 470, time.Now().AddDate(0, 0, 40), 1)
+	open.Adjustments = []models.Adjustment{{Date: time.Now(), Type: models.AdjustmentRoll, NewStrike: 475}}
+	if err := source.AddPosition(open); err != nil {
+		t.Fatalf("AddPosition failed: %v", err)
+	}
+	reading := &models.IVReading{Symbol: "SPY", Date: time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), IV: 0.18}
+	if err := source.StoreIVReading(reading); err != nil {
+		t.Fatalf("StoreIVReading failed: %v", err)
+	}
+	if err := source.SetTradingHalt(&models.TradingHalt{Date: "2025-03-03", LossLimit: 500, TriggeredAt: time.Now()}); err != nil {
+		t.Fatalf("SetTradingHalt failed: %v", err)
+	}
+	if err := source.SetEntryPause(&models.EntryPause{Reason: "FOMC", PausedAt: time.Now(), PausedBy: "127.0.0.1"}); err != nil {
+		t.Fatalf("SetEntryPause failed: %v", err)
+	}
+
+	data, err := ReadDataFile(sourcePath)
+	if err != nil {
+		t.Fatalf("ReadDataFile failed: %v", err)
+	}
+	want := RowCounts{CurrentPositions: 1, History: 1, IVReadings: 1, DailyPnLDays: 1,
+		Adjustments: 1, TradingHalt: true, EntryPause: true}
+	if data.Counts() != want {
+		t.Fatalf("Expected source counts %+v, got %+v", want, data.Counts())
+	}
+
+	targetPath := filepath.Join(dir, "target.json")
+	target, err := NewJSONStorage(targetPath)
+	if err != nil {
+		t.Fatalf("NewJSONStorage failed: %v", err)
+	}
+	if err := target.ImportData(data); err != nil {
+		t.Fatalf("ImportData failed: %v", err)
+	}
+
+	reloaded, err := NewJSONStorage(targetPath)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	exported := reloaded.ExportData()
+	if problems := VerifyImport(data, exported); len(problems) > 0 {
+		t.Errorf("Expected a verified import, got %v", problems)
+	}
+	if exported.Counts() != want {
+		t.Errorf("Expected target counts %+v, got %+v", want, exported.Counts())
+	}
+	if _, found := reloaded.GetPositionByID("open-1"); !found {
+		t.Error("Expected the open position to be imported")
+	}
+	if !reloaded.HasInHistory("closed-1") {
+		t.Error("Expected the closed position to be imported into history")
+	}
+}
+
+func TestSQLiteStorage_ImportData(t *testing.T) {
+	dir := mustTempDir(t)
+	sourcePath := filepath.Join(dir, "positions.json")
+	source, err := NewJSONStorage(sourcePath)
+	if err != nil {
+		t.Fatalf("NewJSONStorage failed: %v", err)
+	}
+	closeTestPosition(t, source, "win-1", 125, 0.70)
+	closeTestPosition(t, source, "loss-1", -300, 1.40)
+	open := models.NewPosition("open-1", "SPY", 410, 470, time.Now().AddDate(0, 0, 40), 1)
+	open.Adjustments = []models.Adjustment{{Date: time.Now(), Type: models.AdjustmentRoll, NewStrike: 475}}
+	if err := source.AddPosition(open); err != nil {
+		t.Fatalf("AddPosition failed: %v", err)
+	}
+	for day := 3; day <= 5; day++ {
+		reading := &models.IVReading{Symbol: "SPY", Date: time.Date(2025, 3, day, 21, 0, 0, 0, time.UTC), IV: 0.15}
+		if err := source.StoreIVReading(reading); err != nil {
+			t.Fatalf("StoreIVReading failed: %v", err)
+		}
+	}
+	if err := source.SetTradingHalt(&models.TradingHalt{Date: "2025-03-05", LossLimit: 500}); err != nil {
+		t.Fatalf("SetTradingHalt failed: %v", err)
+	}
+	if err := source.SetEntryPause(&models.EntryPause{Reason: "FOMC", PausedAt: time.Now(), PausedBy: "127.0.0.1"}); err != nil {
+		t.Fatalf("SetEntryPause failed: %v", err)
+	}
+
+	data, err := ReadDataFile(sourcePath)
+	if err != nil {
+		t.Fatalf("ReadDataFile failed: %v", err)
+	}
+
+	targetPath := filepath.Join(dir, "strangler.db")
+	target := openSQLite(t, targetPath)
+	// Importing twice replaces rather than appends
+	for i := 0; i < 2; i++ {
+		if err := target.ImportData(data); err != nil {
+			t.Fatalf("ImportData failed: %v", err)
+		}
+	}
+	_ = target.Close()
+
+	reloaded := openSQLite(t, targetPath)
+	exported := reloaded.ExportData()
+	if problems := VerifyImport(data, exported); len(problems) > 0 {
+		t.Errorf("Expected a verified import, got %v", problems)
+	}
+	position, found := reloaded.GetPositionByID("open-1")
+	if !found || len(position.Adjustments) != 1 {
+		t.Errorf("Expected the open position with its adjustment, got %+v (found %v)", position, found)
+	}
+	want := RowCounts{CurrentPositions: 1, History: 2, IVReadings: 3, DailyPnLDays: 1,
+		Adjustments: 1, TradingHalt: true, EntryPause: true}
+	if exported.Counts() != want {
+		t.Errorf("Expected target counts %+v, got %+v", want, exported.Counts())
+	}
+	if halt := reloaded.GetTradingHalt(); halt == nil || halt.LossLimit != 500 {
+		t.Errorf("Expected the trading halt to be imported, got %+v", halt)
+	}
+	if pause := reloaded.GetEntryPause(); pause == nil || pause.Reason != "FOMC" {
+		t.Errorf("Expected the entry pause to be imported, got %+v", pause)
+	}
+	if got, want := exported.History[1].ID, "loss-1"; got != want {
+		t.Errorf("Expected history in close order, second is %s, want %s", got, want)
+	}
+}
+
+func TestVerifyImport_ReportsMismatches(t *testing.T) {
+	history := []models.Position{{ID: "a", CurrentPnL: 100}, {ID: "b", CurrentPnL: -50}}
+	src := &Data{History: history, DailyPnL: map[string]float64{"2025-03-03": 50}}
+	dst := &Data{History: history[:1], Statistics: RecomputeStatistics(history)}
+
+	problems := VerifyImport(src, dst)
+	if len(problems) == 0 {
+		t.Fatal("Expected missing rows and stale statistics to be reported")
+	}
+
+	stats := RecomputeStatistics(history)
+	if stats.TotalTrades != 2 || math.Abs(stats.TotalPnL-50) > 1e-9 || stats.MaxSingleTradeLoss != -50 {
+		t.Errorf("Unexpected recomputed statistics %+v", stats)
+	}
+}
+
+func TestVerifyImport_ComparesAdjustmentsHaltAndPause(t *testing.T) {
+	pausedAt := time.Date(2025, 3, 3, 14, 30, 0, 0, time.UTC)
+	src := &Data{
+		CurrentPositions: []models.Position{{ID: "a", Adjustments: []models.Adjustment{{Type: models.AdjustmentRoll}}}},
+		TradingHalt:      &models.TradingHalt{Date: "2025-03-03", LossLimit: 500},
+		EntryPause:       &models.EntryPause{Reason: "FOMC", PausedAt: pausedAt},
+	}
+
+	// The same instant in another zone still matches
+	same := &Data{
+		CurrentPositions: src.CurrentPositions,
+		TradingHalt:      &models.TradingHalt{Date: "2025-03-03", LossLimit: 500},
+		EntryPause:       &models.EntryPause{Reason: "FOMC", PausedAt: pausedAt.In(time.FixedZone("EST", -5*3600))},
+	}
+	if problems := VerifyImport(src, same); len(problems) > 0 {
+		t.Errorf("Expected a matching import, got %v", problems)
+	}
+
+	tests := []struct {
+		name string
+		dst  *Data
+		want string
+	}{
+		{"dropped adjustment", &Data{CurrentPositions: []models.Position{{ID: "a"}},
+			TradingHalt: src.TradingHalt, EntryPause: src.EntryPause}, "row counts"},
+		{"dropped halt and pause", &Data{CurrentPositions: src.CurrentPositions}, "row counts"},
+		{"changed halt", &Data{CurrentPositions: src.CurrentPositions,
+			TradingHalt: &models.TradingHalt{Date: "2025-03-04", LossLimit: 500}, EntryPause: src.EntryPause}, "trading halt"},
+		{"changed pause", &Data{CurrentPositions: src.CurrentPositions, TradingHalt: src.TradingHalt,
+			EntryPause: &models.EntryPause{Reason: "CPI", PausedAt: pausedAt}}, "entry pause"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := VerifyImport(src, tt.dst)
+			if len(problems) != 1 || !strings.HasPrefix(problems[0], tt.want) {
+				t.Errorf("Expected one %s problem, got %v", tt.want, problems)
+			}
+		})
+	}
+}
@@ -17,6 +17,9 @@ type MockStorage struct {
 	dailyPnL         map[string]float64
 	statistics       *Statistics
 	history          []models.Position
+	ivReadings       []models.IVReading
+	tradingHalt      *models.TradingHalt
+	entryPause       *models.EntryPause
 	saveCallCount    int
 	loadCallCount    int
 }
@@ -100,6 +103,60 @@ func (m *MockStorage) GetDailyPnL(date string) float64 {
 	return m.dailyPnL[date]
 }
 
+// GetTradingHalt returns a copy of the recorded trading halt, or nil.
+func (m *MockStorage) GetTradingHalt() *models.TradingHalt {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.tradingHalt == nil {
+		return nil
+	}
+	halt := *m.tradingHalt
+	return &halt
+}
+
+// SetTradingHalt records a trading halt; nil clears it. It honours the configured save error.
+func (m *MockStorage) SetTradingHalt(halt *models.TradingHalt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.saveError != nil {
+		return m.saveError
+	}
+	if halt == nil {
+		m.tradingHalt = nil
+		return nil
+	}
+	h := *halt
+	m.tradingHalt = &h
+	return nil
+}
+
+// GetEntryPause returns a copy of the entry pause in effect, or nil.
+func (m *MockStorage) GetEntryPause() *models.EntryPause {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.entryPause == nil {
+		return nil
+	}
+	pause := *m.entryPause
+	return &pause
+}
+
+// SetEntryPause records an entry pause; nil clears it. It honours the configured save error.
+func (m *MockStorage) SetEntryPause(pause *models.EntryPause) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.saveError != nil {
+		return m.saveError
+	}
+	if pause == nil {
+		m.entryPause = nil
+		return nil
+	}
+	p := *pause
+	m.entryPause = &p
+	return nil
+}
+
 // SetSaveError configures the mock to return an error on Save calls.
 func (m *MockStorage) SetSaveError(err error) {
 	m.mu.Lock()
@@ -153,10 +210,11 @@ func (m *MockStorage) SetDailyPnL(date string, pnl float64) {
 }
 
 // Helper method to update statistics (consistent with JSONStorage)
-func (m *MockStorage) updateStatistics(pnl float64) {
+func (m *MockStorage) updateStatistics(pnl, fees float64) {
 	// Note: this method assumes caller has already acquired the mutex
 	m.statistics.TotalTrades++
 	m.statistics.TotalPnL += pnl
+	m.statistics.TotalFees += fees
 
 	if pnl > 0 {
 		m.statistics.WinningTrades++
@@ -199,22 +257,57 @@ func (m *MockStorage) updateStatistics(pnl float64) {
 	}
 }
 
-// StoreIVReading stores a new IV reading (mock implementation)
+// StoreIVReading stores a new IV reading in memory, replacing any reading for the same symbol and day
 func (m *MockStorage) StoreIVReading(reading *models.IVReading) error {
-	// Mock implementation - just return success
+	if reading == nil {
+		return fmt.Errorf("cannot store nil IV reading")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ry, rm, rd := reading.Date.Date()
+	for i, existing := range m.ivReadings {
+		ey, em, ed := existing.Date.Date()
+		if existing.Symbol == reading.Symbol && ey == ry && em == rm && ed == rd {
+			m.ivReadings[i] = *reading
+			return nil
+		}
+	}
+	m.ivReadings = append(m.ivReadings, *reading)
 	return nil
 }
 
-// GetIVReadings retrieves IV readings within a date range (mock implementation)
+// GetIVReadings retrieves stored IV readings for a symbol within a date range
 func (m *MockStorage) GetIVReadings(symbol string, startDate, endDate time.Time) ([]models.IVReading, error) {
-	// Return empty slice for mock
-	return []models.IVReading{}, nil
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	readings := []models.IVReading{}
+	for _, reading := range m.ivReadings {
+		if reading.Symbol == symbol && !reading.Date.Before(startDate) && !reading.Date.After(endDate) {
+			readings = append(readings, reading)
+		}
+	}
+	return readings, nil
 }
 
-// GetLatestIVReading retrieves the most recent IV reading (mock implementation)
+// GetLatestIVReading retrieves the most recent stored IV reading for a symbol
 func (m *MockStorage) GetLatestIVReading(symbol string) (*models.IVReading, error) {
-	// Return not-found error consistent with JSONStorage
-	return nil, fmt.Errorf("%w for symbol %s", ErrNoIVReadings, symbol)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var latest *models.IVReading
+	for i := range m.ivReadings {
+		if m.ivReadings[i].Symbol == symbol && (latest == nil || m.ivReadings[i].Date.After(latest.Date)) {
+			reading := m.ivReadings[i]
+			latest = &reading
+		}
+	}
+	if latest == nil {
+		// Return not-found error consistent with JSONStorage
+		return nil, fmt.Errorf("%w for symbol %s", ErrNoIVReadings, symbol)
+	}
+	return latest, nil
 }
 
 // GetCurrentPositions returns all current open positions
@@ -363,8 +456,11 @@ func (m *MockStorage) ClosePositionByID(id string, finalPnL float64, reason stri
 		return fmt.Errorf("failed to transition to closed: %w", err)
 	}
 
+	finalPnL -= posToClose.Fees
 	posToClose.CurrentPnL = finalPnL
-	posToClose.ExitReason = reason
+	if posToClose.ExitReason == "" {
+		posToClose.ExitReason = reason
+	}
 
 	// Update positions list
 	m.currentPositions = newPositions
@@ -373,7 +469,7 @@ func (m *MockStorage) ClosePositionByID(id string, finalPnL float64, reason stri
 	m.history = append(m.history, *posToClose)
 
 	// Update statistics via shared helper
-	m.updateStatistics(finalPnL)
+	m.updateStatistics(finalPnL, posToClose.Fees)
 
 	// Update daily P&L using NY trading day
 	closedAt := posToClose.ExitDate
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CurrentSchemaVersion is the storage file format this build reads and writes. Bump it
+// together with a new entry in schemaMigrations whenever a change to Data or
+// models.Position would make older files decode differently.
+const CurrentSchemaVersion = 1
+
+// schemaMigration upgrades a decoded storage file from version From to From+1. It works on
+// the raw JSON because the Go types only describe the current version.
+type schemaMigration struct {
+	From        int
+	Description string
+	Apply       func(doc map[string]json.RawMessage) error
+}
+
+// schemaMigrations holds one step per version, in order; schemaMigrations[v] upgrades v to v+1
+var schemaMigrations = []schemaMigration{
+	{
+		From:        0,
+		Description: "drop persisted dte from positions and fill in missing daily_pnl and statistics",
+		Apply:       migrateUnversionedFile,
+	},
+}
+
+// schemaVersionOf returns the schema_version recorded in a storage file; files written
+// before versioning have none and count as version 0
+func schemaVersionOf(doc map[string]json.RawMessage) (int, error) {
+	raw, ok := doc["schema_version"]
+	if !ok {
+		return 0, nil
+	}
+	var version int
+	if err := json.Unmarshal(raw, &version); err != nil {
+		return 0, fmt.Errorf("invalid schema_version: %w", err)
+	}
+	if version < 0 {
+		return 0, fmt.Errorf("invalid schema_version %d", version)
+	}
+	return version, nil
+}
+
+// upgradeSchema decodes a storage file and applies every migration needed to bring it to
+// CurrentSchemaVersion. It returns the upgraded file and the version it started from, and
+// fails with ErrSchemaTooNew for files written by a newer build.
+func upgradeSchema(raw []byte) ([]byte, int, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, 0, err
+	}
+	from, err := schemaVersionOf(doc)
+	if err != nil {
+		return nil, 0, err
+	}
+	if from > CurrentSchemaVersion {
+		return nil, from, fmt.Errorf("%w: file is version %d, this build supports up to %d",
+			ErrSchemaTooNew, from, CurrentSchemaVersion)
+	}
+	if from == CurrentSchemaVersion {
+		return raw, from, nil
+	}
+
+	for version := from; version < CurrentSchemaVersion; version++ {
+		step := schemaMigrations[version]
+		if err := step.Apply(doc); err != nil {
+			return nil, from, fmt.Errorf("migrating schema %d to %d (%s): %w", version, version+1, step.Description, err)
+		}
+	}
+	doc["schema_version"] = json.RawMessage(fmt.Sprint(CurrentSchemaVersion))
+
+	upgraded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, from, err
+	}
+	return upgraded, from, nil
+}
+
+// migrateUnversionedFile upgrades files from before schema_version existed. Positions in
+// those files may carry a stale "dte" key from when DTE was persisted.
+func migrateUnversionedFile(doc map[string]json.RawMessage) error {
+	for _, key := range []string{"current_positions", "history"} {
+		if err := dropPositionKey(doc, key, "dte"); err != nil {
+			return err
+		}
+	}
+	if raw, ok := doc["daily_pnl"]; !ok || string(raw) == "null" {
+		doc["daily_pnl"] = json.RawMessage("{}")
+	}
+	if raw, ok := doc["statistics"]; !ok || string(raw) == "null" {
+		doc["statistics"] = json.RawMessage("{}")
+	}
+	return nil
+}
+
+// dropPositionKey removes field from every position in the list stored under listKey
+func dropPositionKey(doc map[string]json.RawMessage, listKey, field string) error {
+	raw, ok := doc[listKey]
+	if !ok || string(raw) == "null" {
+		return nil
+	}
+	var positions []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &positions); err != nil {
+		return fmt.Errorf("decoding %s: %w", listKey, err)
+	}
+	for _, position := range positions {
+		delete(position, field)
+	}
+	encoded, err := json.Marshal(positions)
+	if err != nil {
+		return err
+	}
+	doc[listKey] = encoded
+	return nil
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const unversionedFile = `{
+  "last_updated": "2025-01-02T15:04:05Z",
+  "current_positions": [
+    {"id": "legacy-1", "symbol": "SPY", "state": "open", "dte": 38, "quantity": 1, "credit_received": 2.5}
+  ],
+  "history": [
+    {"id": "legacy-0", "symbol": "SPY", "state": "closed", "dte": 21, "current_pnl": 125}
+  ],
+  "iv_readings": []
+}`
+
+func TestJSONStorage_LoadUpgradesUnversionedFile(t *testing.T) {
+	dir := mustTempDir(t)
+	path := filepath.Join(dir, "positions.json")
+	if err := os.WriteFile(path, []byte(unversionedFile), 0o600); err != nil {
+		t.Fatalf("Failed to write legacy file: %v", err)
+	}
+
+	s, err := NewJSONStorage(path)
+	if err != nil {
+		t.Fatalf("NewJSONStorage failed: %v", err)
+	}
+	if _, found := s.GetPositionByID("legacy-1"); !found {
+		t.Error("Expected the legacy open position to load")
+	}
+	if s.GetDailyPnL("2025-01-02") != 0 || s.GetStatistics() == nil {
+		t.Error("Expected missing daily P&L and statistics to default to empty")
+	}
+
+	backups, err := filepath.Glob(path + ".v0-*.bak")
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("Expected one timestamped backup of the version 0 file, got %v (%v)", backups, err)
+	}
+	backup, err := os.ReadFile(backups[0])
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+	if string(backup) != unversionedFile {
+		t.Error("Expected the backup to hold the file exactly as it was before the upgrade")
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read upgraded file: %v", err)
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("Upgraded file is not valid JSON: %v", err)
+	}
+	if version, _ := schemaVersionOf(doc); version != CurrentSchemaVersion {
+		t.Errorf("Expected upgraded file at schema %d, got %d", CurrentSchemaVersion, version)
+	}
+	if strings.Contains(string(raw), `"dte"`) {
+		t.Error("Expected persisted dte to be dropped from positions")
+	}
+
+	// A second load finds nothing to upgrade and takes no further backup
+	if _, err := NewJSONStorage(path); err != nil {
+		t.Fatalf("Reloading upgraded file failed: %v", err)
+	}
+	if backups, _ := filepath.Glob(path + ".v*.bak"); len(backups) != 1 {
+		t.Errorf("Expected no backup for a current file, got %v", backups)
+	}
+}
+
+func TestJSONStorage_LoadRefusesNewerSchema(t *testing.T) {
+	dir := mustTempDir(t)
+	path := filepath.Join(dir, "positions.json")
+	newer := `{"schema_version": 99, "current_positions": [], "history": []}`
+	if err := os.WriteFile(path, []byte(newer), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	_, err := NewJSONStorage(path)
+	if !errors.Is(err, ErrSchemaTooNew) {
+		t.Fatalf("Expected ErrSchemaTooNew, got %v", err)
+	}
+	raw, _ := os.ReadFile(path)
+	if string(raw) != newer {
+		t.Error("Expected a newer file to be left untouched")
+	}
+}
+
+func TestSchemaMigrationsCoverEveryVersion(t *testing.T) {
+	if len(schemaMigrations) != CurrentSchemaVersion {
+		t.Fatalf("Expected %d migrations for schema %d, got %d",
+			CurrentSchemaVersion, CurrentSchemaVersion, len(schemaMigrations))
+	}
+	for i, step := range schemaMigrations {
+		if step.From != i || step.Apply == nil {
+			t.Errorf("Migration %d is out of order or has no Apply: from %d", i, step.From)
+		}
+	}
+}
@@ -0,0 +1,831 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+
+	_ "modernc.org/sqlite" // Pure-Go driver registered as "sqlite"
+)
+
+// sqliteSchemaVersion is the database layout this build reads and writes
+const sqliteSchemaVersion = 1
+
+// sqliteTimeLayout stores times in UTC with a fixed width, so they sort and compare as text
+const sqliteTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// sqliteSchema creates the tables. Positions and history keep each position as its full JSON
+// in data, which is what is read back; the other columns, and the adjustments table, copy
+// the fields worth querying directly.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS positions (
+	seq             INTEGER PRIMARY KEY AUTOINCREMENT,
+	id              TEXT NOT NULL UNIQUE,
+	symbol          TEXT NOT NULL,
+	state           TEXT NOT NULL,
+	entry_date      TEXT NOT NULL,
+	expiration      TEXT NOT NULL,
+	put_strike      REAL NOT NULL,
+	call_strike     REAL NOT NULL,
+	quantity        INTEGER NOT NULL,
+	credit_received REAL NOT NULL,
+	data            TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS history (
+	seq         INTEGER PRIMARY KEY AUTOINCREMENT,
+	id          TEXT NOT NULL,
+	symbol      TEXT NOT NULL,
+	entry_date  TEXT NOT NULL,
+	exit_date   TEXT NOT NULL,
+	exit_reason TEXT NOT NULL,
+	pnl         REAL NOT NULL,
+	fees        REAL NOT NULL,
+	data        TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS history_id ON history (id);
+CREATE INDEX IF NOT EXISTS history_exit_date ON history (exit_date);
+CREATE TABLE IF NOT EXISTS adjustments (
+	position_id TEXT NOT NULL,
+	seq         INTEGER NOT NULL,
+	date        TEXT NOT NULL,
+	type        TEXT NOT NULL,
+	description TEXT NOT NULL,
+	old_strike  REAL NOT NULL,
+	new_strike  REAL NOT NULL,
+	credit      REAL NOT NULL
+);
+CREATE INDEX IF NOT EXISTS adjustments_position ON adjustments (position_id, seq);
+CREATE TABLE IF NOT EXISTS daily_pnl (
+	day TEXT PRIMARY KEY,
+	pnl REAL NOT NULL
+);
+CREATE TABLE IF NOT EXISTS iv_readings (
+	seq       INTEGER PRIMARY KEY AUTOINCREMENT,
+	symbol    TEXT NOT NULL,
+	day       TEXT NOT NULL,
+	date      TEXT NOT NULL,
+	timestamp TEXT NOT NULL,
+	iv        REAL NOT NULL,
+	UNIQUE (symbol, day)
+);
+CREATE INDEX IF NOT EXISTS iv_readings_date ON iv_readings (symbol, date);
+`
+
+// Keys of the meta table
+const (
+	metaSchemaVersion = "schema_version"
+	metaStatistics    = "statistics"
+	metaTradingHalt   = "trading_halt"
+	metaEntryPause    = "entry_pause"
+)
+
+// SQLiteStorage implements Interface on a SQLite database. Every change is committed in its
+// own transaction, so nothing is rewritten that did not change.
+//
+// Open positions, statistics, daily P&L, the trading halt and the entry pause are also
+// held in memory and refreshed after each commit, so the reads the bot relies on every
+// cycle cannot fail. History and IV readings are read from the database.
+type SQLiteStorage struct {
+	db       *sql.DB
+	filepath string
+	mu       sync.RWMutex
+
+	positions  []models.Position
+	statistics *Statistics
+	dailyPnL   map[string]float64
+	halt       *models.TradingHalt
+	pause      *models.EntryPause
+}
+
+// Ensure SQLiteStorage implements Importer, and so Interface
+var _ Importer = (*SQLiteStorage)(nil)
+
+// NewSQLiteStorage opens, or creates, a SQLite database at filePath
+func NewSQLiteStorage(filePath string) (*SQLiteStorage, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o700); err != nil {
+		return nil, fmt.Errorf("creating parent directory: %w", err)
+	}
+
+	dsn := (&url.URL{
+		Scheme: "file",
+		Opaque: filePath,
+		RawQuery: url.Values{"_pragma": []string{
+			"busy_timeout(5000)",
+			"journal_mode(WAL)",
+			"synchronous(FULL)",
+		}}.Encode(),
+	}).String()
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening sqlite database: %w", err)
+	}
+	// One connection serializes writers, which SQLite would do anyway
+	db.SetMaxOpenConns(1)
+
+	s := &SQLiteStorage{db: db, filepath: filePath}
+	if err := s.migrate(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	if err := s.Load(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("loading storage: %w", err)
+	}
+	if err := os.Chmod(filePath, 0o600); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("setting database permissions: %w", err)
+	}
+	return s, nil
+}
+
+// migrate creates the schema in a new database and refuses one written by a newer build
+func (s *SQLiteStorage) migrate() error {
+	if _, err := s.db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("creating sqlite schema: %w", err)
+	}
+
+	var raw string
+	err := s.db.QueryRow(`SELECT value FROM meta WHERE key = ?`, metaSchemaVersion).Scan(&raw)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		_, err = s.db.Exec(`INSERT INTO meta (key, value) VALUES (?, ?)`,
+			metaSchemaVersion, fmt.Sprint(sqliteSchemaVersion))
+		if err != nil {
+			return fmt.Errorf("recording sqlite schema version: %w", err)
+		}
+		return nil
+	case err != nil:
+		return fmt.Errorf("reading sqlite schema version: %w", err)
+	}
+
+	var version int
+	if _, err := fmt.Sscan(raw, &version); err != nil {
+		return fmt.Errorf("invalid sqlite schema_version %q: %w", raw, err)
+	}
+	if version > sqliteSchemaVersion {
+		return fmt.Errorf("%w: database is version %d, this build supports up to %d",
+			ErrSchemaTooNew, version, sqliteSchemaVersion)
+	}
+	return nil
+}
+
+// Close closes the database
+func (s *SQLiteStorage) Close() error {
+	return s.db.Close()
+}
+
+// Load rereads the state held in memory from the database
+func (s *SQLiteStorage) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loadUnsafe()
+}
+
+// loadUnsafe rereads the in-memory state; must be called with s.mu held
+func (s *SQLiteStorage) loadUnsafe() error {
+	positions, err := queryPositions(s.db, `SELECT data FROM positions ORDER BY seq`)
+	if err != nil {
+		return fmt.Errorf("reading positions: %w", err)
+	}
+	dailyPnL, err := queryDailyPnL(s.db)
+	if err != nil {
+		return fmt.Errorf("reading daily P&L: %w", err)
+	}
+	statistics := &Statistics{}
+	if _, err := getMeta(s.db, metaStatistics, statistics); err != nil {
+		return err
+	}
+	var halt *models.TradingHalt
+	if _, err := getMeta(s.db, metaTradingHalt, &halt); err != nil {
+		return err
+	}
+	var pause *models.EntryPause
+	if _, err := getMeta(s.db, metaEntryPause, &pause); err != nil {
+		return err
+	}
+
+	s.positions = positions
+	s.dailyPnL = dailyPnL
+	s.statistics = statistics
+	s.halt = halt
+	s.pause = pause
+	return nil
+}
+
+// Save has nothing to write: every change is committed as it is made
+func (s *SQLiteStorage) Save() error {
+	return nil
+}
+
+// withTx runs fn in a transaction, committing if it succeeds
+func (s *SQLiteStorage) withTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// GetCurrentPositions returns all current open positions
+func (s *SQLiteStorage) GetCurrentPositions() []models.Position {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	positions := make([]models.Position, len(s.positions))
+	for i := range s.positions {
+		positions[i] = s.positions[i].Clone()
+	}
+	return positions
+}
+
+// AddPosition adds a new position to the current positions
+func (s *SQLiteStorage) AddPosition(pos *models.Position) error {
+	if pos == nil {
+		return errors.New("position cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.indexOf(pos.ID) >= 0 {
+		return fmt.Errorf("position with ID %s already exists", pos.ID)
+	}
+	cloned := pos.Clone()
+	if err := s.withTx(func(tx *sql.Tx) error {
+		return insertPosition(tx, &cloned)
+	}); err != nil {
+		return err
+	}
+	s.positions = append(s.positions, cloned)
+	return nil
+}
+
+// UpdatePosition updates an existing position
+func (s *SQLiteStorage) UpdatePosition(pos *models.Position) error {
+	if pos == nil {
+		return errors.New("position cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(pos.ID)
+	if i < 0 {
+		return fmt.Errorf("position with ID %s not found", pos.ID)
+	}
+	cloned := pos.Clone()
+	if err := s.withTx(func(tx *sql.Tx) error {
+		data, err := json.Marshal(&cloned)
+		if err != nil {
+			return fmt.Errorf("encoding position %s: %w", cloned.ID, err)
+		}
+		_, err = tx.Exec(`UPDATE positions SET symbol = ?, state = ?, entry_date = ?, expiration = ?,
+			put_strike = ?, call_strike = ?, quantity = ?, credit_received = ?, data = ? WHERE id = ?`,
+			cloned.Symbol, string(cloned.GetCurrentState()), formatTime(cloned.EntryDate), formatTime(cloned.Expiration),
+			cloned.PutStrike, cloned.CallStrike, cloned.Quantity, cloned.CreditReceived, string(data), cloned.ID)
+		if err != nil {
+			return fmt.Errorf("updating position %s: %w", cloned.ID, err)
+		}
+		return replaceAdjustments(tx, &cloned)
+	}); err != nil {
+		return err
+	}
+	s.positions[i] = cloned
+	return nil
+}
+
+// GetPositionByID retrieves a specific position by ID
+func (s *SQLiteStorage) GetPositionByID(id string) (models.Position, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if i := s.indexOf(id); i >= 0 {
+		return s.positions[i].Clone(), true
+	}
+	return models.Position{}, false
+}
+
+// ClosePositionByID moves a position to history, recording its statistics and daily P&L
+func (s *SQLiteStorage) ClosePositionByID(id string, finalPnL float64, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(id)
+	if i < 0 {
+		return fmt.Errorf("position with ID %s not found", id)
+	}
+	closed := s.positions[i].Clone()
+	finalPnL, err := markClosed(&closed, finalPnL, reason)
+	if err != nil {
+		return err
+	}
+
+	statistics := *s.statistics
+	statistics.record(finalPnL, closed.Fees)
+	day := tradingDay(closed.ExitDate)
+
+	if err := s.withTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM positions WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("removing position %s: %w", id, err)
+		}
+		if err := insertHistory(tx, &closed); err != nil {
+			return err
+		}
+		if err := putMeta(tx, metaStatistics, &statistics); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`INSERT INTO daily_pnl (day, pnl) VALUES (?, ?)
+			ON CONFLICT (day) DO UPDATE SET pnl = pnl + excluded.pnl`, day, finalPnL)
+		if err != nil {
+			return fmt.Errorf("updating daily P&L: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	s.positions = append(s.positions[:i:i], s.positions[i+1:]...)
+	s.statistics = &statistics
+	s.dailyPnL[day] += finalPnL
+	return nil
+}
+
+// DeletePosition removes a position without state transitions or history
+func (s *SQLiteStorage) DeletePosition(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.indexOf(id)
+	if i < 0 {
+		return fmt.Errorf("position with ID %s not found", id)
+	}
+	if err := s.withTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM positions WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("removing position %s: %w", id, err)
+		}
+		if _, err := tx.Exec(`DELETE FROM adjustments WHERE position_id = ?`, id); err != nil {
+			return fmt.Errorf("removing adjustments of %s: %w", id, err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	s.positions = append(s.positions[:i:i], s.positions[i+1:]...)
+	return nil
+}
+
+// indexOf returns the index of the open position with id, or -1. Callers hold s.mu.
+func (s *SQLiteStorage) indexOf(id string) int {
+	for i := range s.positions {
+		if s.positions[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetHistory returns all closed positions in the order they were closed. A database error
+// returns an empty history.
+func (s *SQLiteStorage) GetHistory() []models.Position {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	history, err := queryPositions(s.db, `SELECT data FROM history ORDER BY seq`)
+	if err != nil {
+		return []models.Position{}
+	}
+	return history
+}
+
+// HasInHistory checks if a position with the given ID has been closed. A database error
+// reports false.
+func (s *SQLiteStorage) HasInHistory(id string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var found int
+	err := s.db.QueryRow(`SELECT 1 FROM history WHERE id = ? LIMIT 1`, id).Scan(&found)
+	return err == nil
+}
+
+// GetStatistics returns a copy of the performance statistics
+func (s *SQLiteStorage) GetStatistics() *Statistics {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	stats := *s.statistics
+	return &stats
+}
+
+// GetDailyPnL returns the realized P&L for a New York trading day (YYYY-MM-DD)
+func (s *SQLiteStorage) GetDailyPnL(date string) float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dailyPnL[date]
+}
+
+// GetTradingHalt returns a copy of the recorded trading halt, or nil if none is set
+func (s *SQLiteStorage) GetTradingHalt() *models.TradingHalt {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.halt == nil {
+		return nil
+	}
+	halt := *s.halt
+	return &halt
+}
+
+// SetTradingHalt records a trading halt; nil clears the halt
+func (s *SQLiteStorage) SetTradingHalt(halt *models.TradingHalt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var stored *models.TradingHalt
+	if halt != nil {
+		h := *halt
+		stored = &h
+	}
+	if err := s.withTx(func(tx *sql.Tx) error {
+		return putOrDeleteMeta(tx, metaTradingHalt, stored, stored == nil)
+	}); err != nil {
+		return err
+	}
+	s.halt = stored
+	return nil
+}
+
+// GetEntryPause returns a copy of the entry pause in effect, or nil if entries are not paused
+func (s *SQLiteStorage) GetEntryPause() *models.EntryPause {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.pause == nil {
+		return nil
+	}
+	pause := *s.pause
+	return &pause
+}
+
+// SetEntryPause records an entry pause; nil resumes entries
+func (s *SQLiteStorage) SetEntryPause(pause *models.EntryPause) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var stored *models.EntryPause
+	if pause != nil {
+		p := *pause
+		stored = &p
+	}
+	if err := s.withTx(func(tx *sql.Tx) error {
+		return putOrDeleteMeta(tx, metaEntryPause, stored, stored == nil)
+	}); err != nil {
+		return err
+	}
+	s.pause = stored
+	return nil
+}
+
+// StoreIVReading stores an IV reading, replacing any for the same symbol and trading day
+func (s *SQLiteStorage) StoreIVReading(reading *models.IVReading) error {
+	if reading == nil {
+		return fmt.Errorf("cannot store nil IV reading")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.withTx(func(tx *sql.Tx) error {
+		return upsertIVReading(tx, reading)
+	})
+}
+
+// GetIVReadings retrieves IV readings for a symbol dated within [startDate, endDate]
+func (s *SQLiteStorage) GetIVReadings(symbol string, startDate, endDate time.Time) ([]models.IVReading, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return queryIVReadings(s.db, `SELECT symbol, date, timestamp, iv FROM iv_readings
+		WHERE symbol = ? AND date >= ? AND date <= ? ORDER BY seq`,
+		symbol, formatTime(startDate), formatTime(endDate))
+}
+
+// GetLatestIVReading retrieves the most recent IV reading for a symbol, by its timestamp or,
+// for readings without one, its date
+func (s *SQLiteStorage) GetLatestIVReading(symbol string) (*models.IVReading, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	readings, err := queryIVReadings(s.db, `SELECT symbol, date, timestamp, iv FROM iv_readings
+		WHERE symbol = ? AND (timestamp != '' OR date != '')
+		ORDER BY CASE WHEN timestamp != '' THEN timestamp ELSE date END DESC, seq ASC LIMIT 1`,
+		symbol)
+	if err != nil {
+		return nil, err
+	}
+	if len(readings) == 0 {
+		return nil, fmt.Errorf("%w for symbol %s", ErrNoIVReadings, symbol)
+	}
+	return &readings[0], nil
+}
+
+// ImportData replaces everything in the database with data in a single transaction
+func (s *SQLiteStorage) ImportData(data *Data) error {
+	if data == nil {
+		return fmt.Errorf("cannot import nil data")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statistics := &Statistics{}
+	if data.Statistics != nil {
+		*statistics = *data.Statistics
+	}
+	if err := s.withTx(func(tx *sql.Tx) error {
+		for _, table := range []string{"positions", "history", "adjustments", "daily_pnl", "iv_readings"} {
+			if _, err := tx.Exec(`DELETE FROM ` + table); err != nil {
+				return fmt.Errorf("clearing %s: %w", table, err)
+			}
+		}
+		for i := range data.CurrentPositions {
+			if err := insertPosition(tx, &data.CurrentPositions[i]); err != nil {
+				return err
+			}
+		}
+		for i := range data.History {
+			if err := insertHistory(tx, &data.History[i]); err != nil {
+				return err
+			}
+			if err := insertAdjustments(tx, &data.History[i]); err != nil {
+				return err
+			}
+		}
+		for day, pnl := range data.DailyPnL {
+			if _, err := tx.Exec(`INSERT INTO daily_pnl (day, pnl) VALUES (?, ?)`, day, pnl); err != nil {
+				return fmt.Errorf("writing daily P&L for %s: %w", day, err)
+			}
+		}
+		for i := range data.IVReadings {
+			if err := upsertIVReading(tx, &data.IVReadings[i]); err != nil {
+				return err
+			}
+		}
+		if err := putMeta(tx, metaStatistics, statistics); err != nil {
+			return err
+		}
+		if err := putOrDeleteMeta(tx, metaTradingHalt, data.TradingHalt, data.TradingHalt == nil); err != nil {
+			return err
+		}
+		return putOrDeleteMeta(tx, metaEntryPause, data.EntryPause, data.EntryPause == nil)
+	}); err != nil {
+		return err
+	}
+	return s.loadUnsafe()
+}
+
+// ExportData returns everything in the database as a Data snapshot. Read errors leave the
+// affected records out, which VerifyImport reports as a row count mismatch.
+func (s *SQLiteStorage) ExportData() *Data {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data := &Data{
+		SchemaVersion:    CurrentSchemaVersion,
+		LastUpdated:      time.Now().UTC(),
+		CurrentPositions: make([]models.Position, len(s.positions)),
+		DailyPnL:         make(map[string]float64, len(s.dailyPnL)),
+		Statistics:       &Statistics{},
+	}
+	for i := range s.positions {
+		data.CurrentPositions[i] = s.positions[i].Clone()
+	}
+	for day, pnl := range s.dailyPnL {
+		data.DailyPnL[day] = pnl
+	}
+	*data.Statistics = *s.statistics
+	if s.halt != nil {
+		halt := *s.halt
+		data.TradingHalt = &halt
+	}
+	if s.pause != nil {
+		pause := *s.pause
+		data.EntryPause = &pause
+	}
+	if history, err := queryPositions(s.db, `SELECT data FROM history ORDER BY seq`); err == nil {
+		data.History = history
+	}
+	if readings, err := queryIVReadings(s.db,
+		`SELECT symbol, date, timestamp, iv FROM iv_readings ORDER BY seq`); err == nil {
+		data.IVReadings = readings
+	}
+	return data
+}
+
+// insertPosition writes an open position and its adjustments
+func insertPosition(tx *sql.Tx, pos *models.Position) error {
+	data, err := json.Marshal(pos)
+	if err != nil {
+		return fmt.Errorf("encoding position %s: %w", pos.ID, err)
+	}
+	_, err = tx.Exec(`INSERT INTO positions (id, symbol, state, entry_date, expiration,
+		put_strike, call_strike, quantity, credit_received, data) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		pos.ID, pos.Symbol, string(pos.GetCurrentState()), formatTime(pos.EntryDate), formatTime(pos.Expiration),
+		pos.PutStrike, pos.CallStrike, pos.Quantity, pos.CreditReceived, string(data))
+	if err != nil {
+		return fmt.Errorf("inserting position %s: %w", pos.ID, err)
+	}
+	return replaceAdjustments(tx, pos)
+}
+
+// insertHistory appends a closed position to history
+func insertHistory(tx *sql.Tx, pos *models.Position) error {
+	data, err := json.Marshal(pos)
+	if err != nil {
+		return fmt.Errorf("encoding position %s: %w", pos.ID, err)
+	}
+	_, err = tx.Exec(`INSERT INTO history (id, symbol, entry_date, exit_date, exit_reason, pnl, fees, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		pos.ID, pos.Symbol, formatTime(pos.EntryDate), formatTime(pos.ExitDate), pos.ExitReason,
+		pos.CurrentPnL, pos.Fees, string(data))
+	if err != nil {
+		return fmt.Errorf("inserting history for %s: %w", pos.ID, err)
+	}
+	return nil
+}
+
+// replaceAdjustments rewrites the adjustments rows of a position
+func replaceAdjustments(tx *sql.Tx, pos *models.Position) error {
+	if _, err := tx.Exec(`DELETE FROM adjustments WHERE position_id = ?`, pos.ID); err != nil {
+		return fmt.Errorf("clearing adjustments of %s: %w", pos.ID, err)
+	}
+	return insertAdjustments(tx, pos)
+}
+
+func insertAdjustments(tx *sql.Tx, pos *models.Position) error {
+	for i, adj := range pos.Adjustments {
+		_, err := tx.Exec(`INSERT INTO adjustments (position_id, seq, date, type, description,
+			old_strike, new_strike, credit) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			pos.ID, i, formatTime(adj.Date), string(adj.Type), adj.Description,
+			adj.OldStrike, adj.NewStrike, adj.Credit)
+		if err != nil {
+			return fmt.Errorf("inserting adjustment for %s: %w", pos.ID, err)
+		}
+	}
+	return nil
+}
+
+// upsertIVReading stores a reading, replacing one for the same symbol and trading day in place
+func upsertIVReading(tx *sql.Tx, reading *models.IVReading) error {
+	_, err := tx.Exec(`INSERT INTO iv_readings (symbol, day, date, timestamp, iv) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (symbol, day) DO UPDATE SET
+			date = excluded.date, timestamp = excluded.timestamp, iv = excluded.iv`,
+		reading.Symbol, tradingDay(reading.Date), formatTime(reading.Date), formatTime(reading.Timestamp), reading.IV)
+	if err != nil {
+		return fmt.Errorf("storing IV reading for %s: %w", reading.Symbol, err)
+	}
+	return nil
+}
+
+// queryPositions decodes the data column of every row a query returns
+func queryPositions(db *sql.DB, query string, args ...any) ([]models.Position, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	positions := []models.Position{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var pos models.Position
+		if err := json.Unmarshal([]byte(data), &pos); err != nil {
+			return nil, fmt.Errorf("decoding position: %w", err)
+		}
+		positions = append(positions, pos)
+	}
+	return positions, rows.Err()
+}
+
+// queryIVReadings scans symbol, date, timestamp and iv from every row a query returns
+func queryIVReadings(db *sql.DB, query string, args ...any) ([]models.IVReading, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("reading IV readings: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var readings []models.IVReading
+	for rows.Next() {
+		var reading models.IVReading
+		var date, timestamp string
+		if err := rows.Scan(&reading.Symbol, &date, &timestamp, &reading.IV); err != nil {
+			return nil, fmt.Errorf("reading IV readings: %w", err)
+		}
+		if reading.Date, err = parseTime(date); err != nil {
+			return nil, err
+		}
+		if reading.Timestamp, err = parseTime(timestamp); err != nil {
+			return nil, err
+		}
+		readings = append(readings, reading)
+	}
+	return readings, rows.Err()
+}
+
+func queryDailyPnL(db *sql.DB) (map[string]float64, error) {
+	rows, err := db.Query(`SELECT day, pnl FROM daily_pnl`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	dailyPnL := make(map[string]float64)
+	for rows.Next() {
+		var day string
+		var pnl float64
+		if err := rows.Scan(&day, &pnl); err != nil {
+			return nil, err
+		}
+		dailyPnL[day] = pnl
+	}
+	return dailyPnL, rows.Err()
+}
+
+// getMeta decodes the JSON stored under key into v, reporting whether the key exists
+func getMeta(db *sql.DB, key string, v any) (bool, error) {
+	var raw string
+	err := db.QueryRow(`SELECT value FROM meta WHERE key = ?`, key).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reading %s: %w", key, err)
+	}
+	if err := json.Unmarshal([]byte(raw), v); err != nil {
+		return false, fmt.Errorf("decoding %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// putMeta stores v as JSON under key
+func putMeta(tx *sql.Tx, key string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", key, err)
+	}
+	_, err = tx.Exec(`INSERT INTO meta (key, value) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value`, key, string(raw))
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", key, err)
+	}
+	return nil
+}
+
+// putOrDeleteMeta stores v under key, or removes the key when clear is set
+func putOrDeleteMeta(tx *sql.Tx, key string, v any, clear bool) error {
+	if !clear {
+		return putMeta(tx, key, v)
+	}
+	if _, err := tx.Exec(`DELETE FROM meta WHERE key = ?`, key); err != nil {
+		return fmt.Errorf("clearing %s: %w", key, err)
+	}
+	return nil
+}
+
+// formatTime renders t for a text column; the zero time is stored as ""
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(sqliteTimeLayout)
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(sqliteTimeLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid stored time %q: %w", s, err)
+	}
+	return t, nil
+}
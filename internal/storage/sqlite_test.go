@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+)
+
+func openSQLite(t *testing.T, path string) *SQLiteStorage {
+	t.Helper()
+	s, err := NewSQLiteStorage(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage failed: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func newOpenTestPosition(t *testing.T, id string) *models.Position {
+	t.Helper()
+	pos := models.NewPosition(id, "SPY", 445, 455, time.Now().AddDate(0, 0, 30), 1)
+	setupPositionForState(t, pos, models.StateFirstDown)
+	pos.Fees = 1.40
+	return pos
+}
+
+func TestSQLiteStorage_StateSurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strangler.db")
+	s := openSQLite(t, path)
+
+	kept := newOpenTestPosition(t, "kept")
+	kept.Adjustments = []models.Adjustment{
+		{Date: time.Now(), Type: models.AdjustmentRoll, OldStrike: 455, NewStrike: 460, Credit: 0.8, Description: "roll call"},
+	}
+	if err := s.AddPosition(kept); err != nil {
+		t.Fatalf("AddPosition failed: %v", err)
+	}
+	closed := newOpenTestPosition(t, "closed")
+	if err := s.AddPosition(closed); err != nil {
+		t.Fatalf("AddPosition failed: %v", err)
+	}
+	if err := s.ClosePositionByID("closed", -50, "time"); err != nil {
+		t.Fatalf("ClosePositionByID failed: %v", err)
+	}
+	if err := s.SetTradingHalt(&models.TradingHalt{Date: "2025-03-03", Reason: "daily loss", LossLimit: 2000}); err != nil {
+		t.Fatalf("SetTradingHalt failed: %v", err)
+	}
+	if err := s.SetEntryPause(&models.EntryPause{Reason: "FOMC", PausedBy: "operator"}); err != nil {
+		t.Fatalf("SetEntryPause failed: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	reopened := openSQLite(t, path)
+	positions := reopened.GetCurrentPositions()
+	if len(positions) != 1 || positions[0].ID != "kept" {
+		t.Fatalf("Expected only the kept position, got %+v", positions)
+	}
+	if positions[0].GetCurrentState() != models.StateFirstDown {
+		t.Errorf("Expected state %s after reopening, got %s", models.StateFirstDown, positions[0].GetCurrentState())
+	}
+	if len(positions[0].Adjustments) != 1 || positions[0].Adjustments[0].NewStrike != 460 {
+		t.Errorf("Expected the roll adjustment after reopening, got %+v", positions[0].Adjustments)
+	}
+
+	history := reopened.GetHistory()
+	if len(history) != 1 || history[0].ID != "closed" || math.Abs(history[0].CurrentPnL+51.40) > 1e-9 {
+		t.Fatalf("Expected closed position with P&L net of fees in history, got %+v", history)
+	}
+	if !reopened.HasInHistory("closed") || reopened.HasInHistory("kept") {
+		t.Error("HasInHistory should report only the closed position")
+	}
+	if stats := reopened.GetStatistics(); stats.TotalTrades != 1 || stats.LosingTrades != 1 || stats.TotalFees != 1.40 {
+		t.Errorf("Unexpected statistics after reopening: %+v", stats)
+	}
+	if got := reopened.GetDailyPnL(tradingDay(history[0].ExitDate)); math.Abs(got+51.40) > 1e-9 {
+		t.Errorf("Expected daily P&L -51.40, got %.2f", got)
+	}
+	if halt := reopened.GetTradingHalt(); halt == nil || halt.LossLimit != 2000 {
+		t.Errorf("Expected trading halt to survive reopening, got %+v", halt)
+	}
+	if pause := reopened.GetEntryPause(); pause == nil || pause.Reason != "FOMC" {
+		t.Errorf("Expected entry pause to survive reopening, got %+v", pause)
+	}
+
+	// The adjustments table follows the positions for ad-hoc queries
+	var rows int
+	if err := reopened.db.QueryRow(`SELECT COUNT(*) FROM adjustments WHERE position_id = 'kept'`).Scan(&rows); err != nil {
+		t.Fatalf("Querying adjustments failed: %v", err)
+	}
+	if rows != 1 {
+		t.Errorf("Expected 1 adjustment row, got %d", rows)
+	}
+
+	if err := reopened.SetTradingHalt(nil); err != nil {
+		t.Fatalf("Clearing halt failed: %v", err)
+	}
+	if err := reopened.DeletePosition("kept"); err != nil {
+		t.Fatalf("DeletePosition failed: %v", err)
+	}
+	if err := reopened.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if reopened.GetTradingHalt() != nil || len(reopened.GetCurrentPositions()) != 0 {
+		t.Error("Expected halt cleared and position deleted after reload")
+	}
+}
+
+func TestSQLiteStorage_IVReadings(t *testing.T) {
+	s := openSQLite(t, filepath.Join(t.TempDir(), "iv.db"))
+
+	day1 := time.Date(2025, 3, 3, 21, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	for _, r := range []models.IVReading{
+		{Symbol: "SPY", Date: day1, IV: 0.15, Timestamp: day1},
+		{Symbol: "SPY", Date: day2, IV: 0.18, Timestamp: day2},
+		{Symbol: "SPY", Date: day1.Add(time.Hour), IV: 0.16, Timestamp: day1.Add(time.Hour)}, // Same New York day as day1
+		{Symbol: "QQQ", Date: day2, IV: 0.22, Timestamp: day2},
+	} {
+		if err := s.StoreIVReading(&r); err != nil {
+			t.Fatalf("StoreIVReading failed: %v", err)
+		}
+	}
+
+	readings, err := s.GetIVReadings("SPY", day1, day2)
+	if err != nil {
+		t.Fatalf("GetIVReadings failed: %v", err)
+	}
+	if len(readings) != 2 || readings[0].IV != 0.16 || readings[1].IV != 0.18 {
+		t.Fatalf("Expected day1 replaced in place then day2, got %+v", readings)
+	}
+	if !readings[1].Date.Equal(day2) {
+		t.Errorf("Expected date %v, got %v", day2, readings[1].Date)
+	}
+
+	latest, err := s.GetLatestIVReading("SPY")
+	if err != nil {
+		t.Fatalf("GetLatestIVReading failed: %v", err)
+	}
+	if latest.IV != 0.18 {
+		t.Errorf("Expected latest IV 0.18, got %.2f", latest.IV)
+	}
+
+	if _, err := s.GetLatestIVReading("IWM"); !errors.Is(err, ErrNoIVReadings) {
+		t.Errorf("Expected ErrNoIVReadings, got %v", err)
+	}
+}
+
+func TestSQLiteStorage_RejectsNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "newer.db")
+	s := openSQLite(t, path)
+	if _, err := s.db.Exec(`UPDATE meta SET value = '99' WHERE key = ?`, metaSchemaVersion); err != nil {
+		t.Fatalf("Setting schema version failed: %v", err)
+	}
+	_ = s.Close()
+
+	if _, err := NewSQLiteStorage(path); !errors.Is(err, ErrSchemaTooNew) {
+		t.Fatalf("Expected ErrSchemaTooNew, got %v", err)
+	}
+}
@@ -36,12 +36,15 @@ type JSONStorage struct {
 
 // Data represents the complete data structure stored in JSON files.
 type Data struct {
-	LastUpdated      time.Time          `json:"last_updated"`
-	CurrentPositions []models.Position  `json:"current_positions"`
-	DailyPnL         map[string]float64 `json:"daily_pnl"`
-	Statistics       *Statistics        `json:"statistics"`
-	History          []models.Position  `json:"history"`
-	IVReadings       []models.IVReading `json:"iv_readings"` // Historical IV data
+	SchemaVersion    int                 `json:"schema_version"` // See CurrentSchemaVersion
+	LastUpdated      time.Time           `json:"last_updated"`
+	CurrentPositions []models.Position   `json:"current_positions"`
+	DailyPnL         map[string]float64  `json:"daily_pnl"`
+	Statistics       *Statistics         `json:"statistics"`
+	History          []models.Position   `json:"history"`
+	IVReadings       []models.IVReading  `json:"iv_readings"`            // Historical IV data
+	TradingHalt      *models.TradingHalt `json:"trading_halt,omitempty"` // Daily loss halt, if one was triggered
+	EntryPause       *models.EntryPause  `json:"entry_pause,omitempty"`  // Operator pause on new entries, if one is in effect
 }
 
 // Statistics represents performance metrics and analytics data.
@@ -56,6 +59,7 @@ type Statistics struct {
 	AverageLoss        float64 `json:"average_loss"`          // Average loss magnitude (positive)
 	MaxSingleTradeLoss float64 `json:"max_single_trade_loss"` // Largest single trade loss (negative)
 	CurrentStreak      int     `json:"current_streak"`
+	TotalFees          float64 `json:"total_fees"` // Commissions and fees already deducted from TotalPnL
 }
 
 // getNYLocation returns the cached America/New_York timezone location
@@ -76,6 +80,7 @@ func NewJSONStorage(filePath string) (*JSONStorage, error) {
 	s := &JSONStorage{
 		filepath: filePath,
 		data: &Data{
+			SchemaVersion: CurrentSchemaVersion,
 			DailyPnL:   make(map[string]float64),
 			Statistics: &Statistics{},
 		},
@@ -137,12 +142,42 @@ func (s *JSONStorage) Load() error {
 		return err
 	}
 
+	upgraded, fromVersion, err := upgradeSchema(data)
+	if err != nil {
+		return err
+	}
+
 	var loaded Data
-	if err := json.Unmarshal(data, &loaded); err != nil {
+	if err := json.Unmarshal(upgraded, &loaded); err != nil {
 		return err
 	}
+
+	if fromVersion < CurrentSchemaVersion {
+		// Keep the file as it was before rewriting it in the new format
+		backup, err := s.backupPath(fromVersion, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		if err := s.copyFile(s.filepath, backup); err != nil {
+			return fmt.Errorf("backing up storage before schema upgrade: %w", err)
+		}
+		previous := s.data
+		s.data = &loaded
+		s.ensureDefaults()
+		if err := s.saveUnsafe(); err != nil {
+			s.data = previous
+			return fmt.Errorf("saving upgraded storage (backup at %s): %w", backup, err)
+		}
+		return nil
+	}
+
 	s.data = &loaded
+	s.ensureDefaults()
+	return nil
+}
 
+// ensureDefaults fills in the maps and statistics a loaded file may leave nil
+func (s *JSONStorage) ensureDefaults() {
 	if s.data == nil {
 		s.data = &Data{}
 	}
@@ -152,8 +187,15 @@ func (s *JSONStorage) Load() error {
 	if s.data.DailyPnL == nil {
 		s.data.DailyPnL = make(map[string]float64)
 	}
+}
 
-	return nil
+// backupPath names the copy of the storage file kept before upgrading it from version
+func (s *JSONStorage) backupPath(version int, now time.Time) (string, error) {
+	path := fmt.Sprintf("%s.v%d-%s.bak", s.filepath, version, now.Format("20060102T150405Z"))
+	if err := s.validateFilePath(path); err != nil {
+		return "", fmt.Errorf("invalid backup path: %w", err)
+	}
+	return path, nil
 }
 
 // Save writes position data to the JSON file.
@@ -166,6 +208,7 @@ func (s *JSONStorage) Save() error {
 // createDataSnapshot creates a deep copy of the current data for atomic saving
 func (s *JSONStorage) createDataSnapshot() *Data {
 	snapshot := &Data{
+		SchemaVersion: s.data.SchemaVersion,
 		LastUpdated: s.data.LastUpdated, // Will be updated by caller
 		DailyPnL:    make(map[string]float64),
 		Statistics:  &Statistics{},
@@ -201,6 +244,15 @@ func (s *JSONStorage) createDataSnapshot() *Data {
 	// Deep copy IVReadings
 	copy(snapshot.IVReadings, s.data.IVReadings)
 
+	if s.data.TradingHalt != nil {
+		halt := *s.data.TradingHalt
+		snapshot.TradingHalt = &halt
+	}
+	if s.data.EntryPause != nil {
+		pause := *s.data.EntryPause
+		snapshot.EntryPause = &pause
+	}
+
 	return snapshot
 }
 
@@ -213,6 +265,7 @@ func (s *JSONStorage) saveUnsafe() error {
 
 	// Create a snapshot of the current data to avoid mutation-on-failure risk
 	snapshot := s.createDataSnapshot()
+	snapshot.SchemaVersion = CurrentSchemaVersion
 	snapshot.LastUpdated = time.Now().UTC()
 
 	// Create temp file in the same directory as the target file to avoid EXDEV
@@ -463,10 +516,15 @@ func (s *JSONStorage) syncParentDir() error {
 
 
 
-func (s *JSONStorage) updateStatistics(pnl float64) {
-	stats := s.data.Statistics
+func (s *JSONStorage) updateStatistics(pnl, fees float64) {
+	s.data.Statistics.record(pnl, fees)
+}
+
+// record adds one closed trade's net P&L and fees to the running statistics
+func (stats *Statistics) record(pnl, fees float64) {
 	stats.TotalTrades++
 	stats.TotalPnL += pnl
+	stats.TotalFees += fees
 
 	if pnl > 0 {
 		stats.WinningTrades++
@@ -527,6 +585,54 @@ func (s *JSONStorage) GetDailyPnL(date string) float64 {
 	return s.data.DailyPnL[date]
 }
 
+// GetTradingHalt returns a copy of the recorded trading halt, or nil if none is set.
+func (s *JSONStorage) GetTradingHalt() *models.TradingHalt {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.data.TradingHalt == nil {
+		return nil
+	}
+	halt := *s.data.TradingHalt
+	return &halt
+}
+
+// SetTradingHalt records a trading halt and saves it; nil clears the halt.
+func (s *JSONStorage) SetTradingHalt(halt *models.TradingHalt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if halt == nil {
+		s.data.TradingHalt = nil
+	} else {
+		h := *halt
+		s.data.TradingHalt = &h
+	}
+	return s.saveUnsafe()
+}
+
+// GetEntryPause returns a copy of the entry pause in effect, or nil if entries are not paused.
+func (s *JSONStorage) GetEntryPause() *models.EntryPause {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.data.EntryPause == nil {
+		return nil
+	}
+	pause := *s.data.EntryPause
+	return &pause
+}
+
+// SetEntryPause records an entry pause and saves it; nil resumes entries.
+func (s *JSONStorage) SetEntryPause(pause *models.EntryPause) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pause == nil {
+		s.data.EntryPause = nil
+	} else {
+		p := *pause
+		s.data.EntryPause = &p
+	}
+	return s.saveUnsafe()
+}
+
 // GetHistory returns all historical closed positions.
 func (s *JSONStorage) GetHistory() []models.Position {
 	s.mu.RLock()
@@ -584,16 +690,9 @@ func (s *JSONStorage) StoreIVReading(reading *models.IVReading) error {
 	}
 
 	// Check if reading already exists for this symbol and date
-	loc, err := getNYLocation()
-	if err != nil {
-		// Fallback to UTC if timezone loading fails
-		loc = time.UTC
-	}
+	day := tradingDay(reading.Date)
 	for i, existing := range s.data.IVReadings {
-		e := existing.Date.In(loc)
-		r := reading.Date.In(loc)
-		sameDay := e.Year() == r.Year() && e.YearDay() == r.YearDay()
-		if existing.Symbol == reading.Symbol && sameDay {
+		if existing.Symbol == reading.Symbol && tradingDay(existing.Date) == day {
 			// Update existing reading
 			s.data.IVReadings[i] = *reading
 			return s.saveUnsafe()
@@ -776,56 +875,9 @@ func (s *JSONStorage) ClosePositionByID(id string, finalPnL float64, reason stri
 		return fmt.Errorf("failed to clone position for closing")
 	}
 	
-	// Use FSM transition to ensure proper state management
-	// Map the reason to appropriate state transition condition
-	var condition string
-	switch reason {
-	case "manual", "force_close":
-		condition = models.ConditionForceClose
-	case "hard_stop", "stop_loss":
-		condition = models.ConditionHardStop
-	case "profit_target", "time":
-		condition = models.ConditionExitConditions
-	case "emergency_exit", "escalate":
-		condition = models.ConditionEmergencyExit
-	default:
-		// Infer condition from current state if reason is generic
-		currentState := closedPosition.GetCurrentState()
-		switch currentState {
-		case models.StateOpen:
-			condition = models.ConditionPositionClosed
-		case models.StateSubmitted:
-			condition = models.ConditionOrderTimeout
-		case models.StateFirstDown, models.StateSecondDown:
-			condition = models.ConditionExitConditions
-		case models.StateThirdDown:
-			condition = models.ConditionHardStop
-		case models.StateFourthDown:
-			condition = models.ConditionEmergencyExit
-		case models.StateError:
-			condition = models.ConditionForceClose
-		case models.StateAdjusting:
-			condition = models.ConditionHardStop
-		case models.StateRolling:
-			condition = models.ConditionForceClose
-		default:
-			condition = models.ConditionExitConditions // fallback
-		}
-	}
-	
-	if err := closedPosition.TransitionState(models.StateClosed, condition); err != nil {
-		return fmt.Errorf("failed to transition position to closed state: %w", err)
-	}
-	
-	// Update position with closing details
-	closedPosition.CurrentPnL = finalPnL
-	
-	// If TransitionState doesn't set these, ensure they are recorded
-	if closedPosition.ExitReason == "" {
-		closedPosition.ExitReason = reason
-	}
-	if closedPosition.ExitDate.IsZero() {
-		closedPosition.ExitDate = time.Now().UTC()
+	finalPnL, err := markClosed(closedPosition, finalPnL, reason)
+	if err != nil {
+		return err
 	}
 	
 	// Update positions list
@@ -835,27 +887,80 @@ func (s *JSONStorage) ClosePositionByID(id string, finalPnL float64, reason stri
 	s.data.History = append(s.data.History, *closedPosition)
 	
 	// Update statistics
-	s.updateStatistics(finalPnL)
+	s.updateStatistics(finalPnL, closedPosition.Fees)
 	
 	// Update daily P&L using New York timezone for correct trading day classification
-	closedAt := closedPosition.ExitDate
-	if closedAt.IsZero() {
-		closedAt = time.Now().UTC()
-	}
-	nyLoc, err := getNYLocation()
-	if err != nil {
-		// Fallback to UTC if timezone loading fails
-		day := closedAt.Format("2006-01-02")
-		s.data.DailyPnL[day] += finalPnL
-	} else {
-		closedAtNY := closedAt.In(nyLoc)
-		day := closedAtNY.Format("2006-01-02")
-		s.data.DailyPnL[day] += finalPnL
-	}
+	s.data.DailyPnL[tradingDay(closedPosition.ExitDate)] += finalPnL
 	
 	return s.saveUnsafe()
 }
 
+// markClosed transitions a position being closed to StateClosed and records its exit. It
+// returns finalPnL net of the position's fees, which is what history and statistics hold.
+func markClosed(closed *models.Position, finalPnL float64, reason string) (float64, error) {
+	if err := closed.TransitionState(models.StateClosed, closeCondition(closed, reason)); err != nil {
+		return 0, fmt.Errorf("failed to transition position to closed state: %w", err)
+	}
+
+	// The recorded P&L is net of fees paid on every fill
+	finalPnL -= closed.Fees
+	closed.CurrentPnL = finalPnL
+
+	// If TransitionState doesn't set these, ensure they are recorded
+	if closed.ExitReason == "" {
+		closed.ExitReason = reason
+	}
+	if closed.ExitDate.IsZero() {
+		closed.ExitDate = time.Now().UTC()
+	}
+	return finalPnL, nil
+}
+
+// closeCondition maps a close reason to the state transition condition for closing; generic
+// reasons are inferred from the position's current state
+func closeCondition(pos *models.Position, reason string) string {
+	switch reason {
+	case "manual", "force_close":
+		return models.ConditionForceClose
+	case "hard_stop", "stop_loss":
+		return models.ConditionHardStop
+	case "profit_target", "time":
+		return models.ConditionExitConditions
+	case "emergency_exit", "escalate":
+		return models.ConditionEmergencyExit
+	}
+
+	switch pos.GetCurrentState() {
+	case models.StateOpen:
+		return models.ConditionPositionClosed
+	case models.StateSubmitted:
+		return models.ConditionOrderTimeout
+	case models.StateFirstDown, models.StateSecondDown:
+		return models.ConditionExitConditions
+	case models.StateThirdDown:
+		return models.ConditionHardStop
+	case models.StateFourthDown:
+		return models.ConditionEmergencyExit
+	case models.StateError:
+		return models.ConditionForceClose
+	case models.StateAdjusting:
+		return models.ConditionHardStop
+	case models.StateRolling:
+		return models.ConditionForceClose
+	default:
+		return models.ConditionExitConditions // fallback
+	}
+}
+
+// tradingDay returns the New York date of t as YYYY-MM-DD, the key DailyPnL uses
+func tradingDay(t time.Time) string {
+	if loc, err := getNYLocation(); err == nil {
+		t = t.In(loc)
+	}
+	// Fall back to t's own zone if timezone loading fails
+	return t.Format("2006-01-02")
+}
+
 // DeletePosition removes a position from storage without state transitions or history.
 // This is used for cleaning up phantom/invalid positions that never properly entered the system.
 func (s *JSONStorage) DeletePosition(id string) error {
@@ -1,9 +1,13 @@
 package storage
 
 import (
+	"math"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
 )
 
 func mustTempDir(t *testing.T) string {
@@ -37,5 +41,152 @@ func TestNewJSONStorage(t *testing.T) {
 	}
 }
 
+func TestJSONStorage_TradingHaltSurvivesReload(t *testing.T) {
+	dir := mustTempDir(t)
+	path := filepath.Join(dir, "test.json")
+
+	storage, err := NewJSONStorage(path)
+	if err != nil {
+		t.Fatalf("NewJSONStorage failed: %v", err)
+	}
+	if storage.GetTradingHalt() != nil {
+		t.Fatal("Expected no trading halt initially")
+	}
+	if err := storage.SetTradingHalt(&models.TradingHalt{Date: "2025-03-03", Reason: "daily loss", LossLimit: 2000}); err != nil {
+		t.Fatalf("SetTradingHalt failed: %v", err)
+	}
+
+	reloaded, err := NewJSONStorage(path)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	halt := reloaded.GetTradingHalt()
+	if !halt.ActiveOn("2025-03-03") || halt.LossLimit != 2000 {
+		t.Errorf("Expected persisted halt for 2025-03-03, got %+v", halt)
+	}
+
+	if err := reloaded.SetTradingHalt(nil); err != nil {
+		t.Fatalf("Clearing halt failed: %v", err)
+	}
+	if reloaded.GetTradingHalt() != nil {
+		t.Error("Expected halt to be cleared")
+	}
+}
+
+func TestJSONStorage_EntryPauseSurvivesReload(t *testing.T) {
+	dir := mustTempDir(t)
+	path := filepath.Join(dir, "test.json")
+
+	storage, err := NewJSONStorage(path)
+	if err != nil {
+		t.Fatalf("NewJSONStorage failed: %v", err)
+	}
+	if storage.GetEntryPause() != nil {
+		t.Fatal("Expected entries not to be paused initially")
+	}
+	if err := storage.SetEntryPause(&models.EntryPause{Reason: "CPI release", PausedBy: "127.0.0.1"}); err != nil {
+		t.Fatalf("SetEntryPause failed: %v", err)
+	}
+
+	reloaded, err := NewJSONStorage(path)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	pause := reloaded.GetEntryPause()
+	if pause == nil || pause.Reason != "CPI release" {
+		t.Fatalf("Expected persisted entry pause, got %+v", pause)
+	}
+
+	if err := reloaded.SetEntryPause(nil); err != nil {
+		t.Fatalf("Resuming entries failed: %v", err)
+	}
+	if reloaded.GetEntryPause() != nil {
+		t.Error("Expected entry pause to be cleared")
+	}
+}
+
+func TestJSONStorage_ClosePositionByIDDeductsFees(t *testing.T) {
+	dir := mustTempDir(t)
+	storage, err := NewJSONStorage(filepath.Join(dir, "test.json"))
+	if err != nil {
+		t.Fatalf("NewJSONStorage failed: %v", err)
+	}
+
+	position := models.NewPosition("fees-pos", "SPY", 400, 460, time.Now().AddDate(0, 0, 45), 1)
+	if err := position.TransitionState(models.StateSubmitted, models.ConditionOrderPlaced); err != nil {
+		t.Fatalf("Failed to set up position: %v", err)
+	}
+	if err := position.TransitionState(models.StateOpen, models.ConditionOrderFilled); err != nil {
+		t.Fatalf("Failed to set up position: %v", err)
+	}
+	position.Quantity = 2
+	position.CreditReceived = 2.50
+	position.Fees = 2.80 // Entry and exit of 2 strangles at $0.35 per contract
+	if err := storage.AddPosition(position); err != nil {
+		t.Fatalf("AddPosition failed: %v", err)
+	}
+
+	if err := storage.ClosePositionByID("fees-pos", 250, ""); err != nil {
+		t.Fatalf("ClosePositionByID failed: %v", err)
+	}
+
+	history := storage.GetHistory()
+	if len(history) != 1 || math.Abs(history[0].CurrentPnL-247.20) > 1e-9 {
+		t.Fatalf("Expected closed P&L of $247.20 net of fees, got %+v", history)
+	}
+	stats := storage.GetStatistics()
+	if math.Abs(stats.TotalPnL-247.20) > 1e-9 || math.Abs(stats.TotalFees-2.80) > 1e-9 {
+		t.Errorf("Expected statistics net of $2.80 fees, got total $%.2f fees $%.2f", stats.TotalPnL, stats.TotalFees)
+	}
+}
+
 // Additional tests would go here, focused on the new multi-position API
-// The comprehensive interface tests in interface_test.go provide the main coverage
\ No newline at end of file
+// The comprehensive interface tests in interface_test.go provide the main coverage
+func TestJSONStorage_ReloadRestoresStateMachine(t *testing.T) {
+	dir := mustTempDir(t)
+	path := filepath.Join(dir, "test.json")
+	storage, err := NewJSONStorage(path)
+	if err != nil {
+		t.Fatalf("NewJSONStorage failed: %v", err)
+	}
+
+	position := models.NewPosition("machine-pos", "SPY", 400, 460, time.Now().AddDate(0, 0, 45), 1)
+	for _, step := range []struct {
+		to        models.PositionState
+		condition string
+	}{
+		{models.StateSubmitted, models.ConditionOrderPlaced},
+		{models.StateOpen, models.ConditionOrderFilled},
+		{models.StateFirstDown, models.ConditionStartManagement},
+		{models.StateSecondDown, models.ConditionStrikeChallenged},
+		{models.StateAdjusting, models.ConditionRollUntested},
+		{models.StateFirstDown, models.ConditionAdjustmentComplete},
+	} {
+		if err := position.TransitionState(step.to, step.condition); err != nil {
+			t.Fatalf("Failed to transition to %s: %v", step.to, err)
+		}
+	}
+	position.Quantity = 1
+	position.CreditReceived = 2.50
+	if err := storage.AddPosition(position); err != nil {
+		t.Fatalf("AddPosition failed: %v", err)
+	}
+
+	reloaded, err := NewJSONStorage(path)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	restored, found := reloaded.GetPositionByID("machine-pos")
+	if !found {
+		t.Fatal("Expected position after reload")
+	}
+	if restored.StateMachine == nil {
+		t.Fatal("Expected the state machine to be restored from storage")
+	}
+	if got := restored.StateMachine.GetTransitionCount(models.StateAdjusting); got != 1 {
+		t.Errorf("Expected 1 adjustment to survive the reload, got %d", got)
+	}
+	if err := restored.ValidateState(); err != nil {
+		t.Errorf("Expected restored position to validate, got %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+)
+
+// RollOrder describes a single-leg roll of one side of a strangle within the same expiration.
+type RollOrder struct {
+	Side       broker.OptionType // Side being rolled
+	Expiration string
+	OldStrike  float64
+	NewStrike  float64
+	CloseDebit float64 // Per-share mid to buy back the old leg
+	OpenCredit float64 // Per-share mid for the new leg
+}
+
+// NetCredit returns the per-share credit collected by the roll (negative for a debit).
+func (r *RollOrder) NetCredit() float64 {
+	return r.OpenCredit - r.CloseDebit
+}
+
+// TestedSide reports which short strike spot is pressuring and whether it is within
+// threshold points of that strike. A breached strike always counts as challenged.
+func TestedSide(position *models.Position, spot, threshold float64) (broker.OptionType, bool) {
+	if position == nil || spot <= 0 {
+		return "", false
+	}
+	putDistance := spot - position.PutStrike
+	callDistance := position.CallStrike - spot
+
+	if putDistance <= callDistance {
+		return broker.OptionTypePut, putDistance <= threshold
+	}
+	return broker.OptionTypeCall, callDistance <= threshold
+}
+
+// StrikeBreached reports whether spot has reached or crossed the tested short strike.
+func StrikeBreached(position *models.Position, tested broker.OptionType, spot float64) bool {
+	if position == nil || spot <= 0 {
+		return false
+	}
+	switch tested {
+	case broker.OptionTypePut:
+		return spot <= position.PutStrike
+	case broker.OptionTypeCall:
+		return spot >= position.CallStrike
+	default:
+		return false
+	}
+}
+
+// FindUntestedRoll selects a new strike at the target delta for the untested side of the
+// position, in the same expiration. The new strike must move toward spot and the roll
+// must collect a net credit. Greeks missing from the chain are computed at spot.
+func (s *StrangleStrategy) FindUntestedRoll(position *models.Position, tested broker.OptionType, spot float64) (*RollOrder, error) {
+	if position == nil {
+		return nil, fmt.Errorf("position is nil")
+	}
+
+	expiration := position.Expiration.Format("2006-01-02")
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	options, err := s.getCachedOptionChainWithContext(ctx, position.Symbol, expiration, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get option chain: %w", err)
+	}
+	options = s.withLocalGreeks(options, spot)
+
+	roll := &RollOrder{Expiration: expiration}
+	switch tested {
+	case broker.OptionTypePut:
+		roll.Side = broker.OptionTypeCall
+		roll.OldStrike = position.CallStrike
+		roll.NewStrike = s.findStrikeByDelta(options, s.config.DeltaTarget, false)
+		if roll.NewStrike == 0 {
+			return nil, fmt.Errorf("no call strike found near target delta")
+		}
+		if roll.NewStrike >= roll.OldStrike {
+			return nil, fmt.Errorf("call strike %.2f is already at or inside target delta strike %.2f",
+				roll.OldStrike, roll.NewStrike)
+		}
+		if roll.NewStrike <= position.PutStrike {
+			return nil, fmt.Errorf("new call strike %.2f would cross put strike %.2f", roll.NewStrike, position.PutStrike)
+		}
+	case broker.OptionTypeCall:
+		roll.Side = broker.OptionTypePut
+		roll.OldStrike = position.PutStrike
+		roll.NewStrike = s.findStrikeByDelta(options, -s.config.DeltaTarget, true)
+		if roll.NewStrike == 0 {
+			return nil, fmt.Errorf("no put strike found near target delta")
+		}
+		if roll.NewStrike <= roll.OldStrike {
+			return nil, fmt.Errorf("put strike %.2f is already at or inside target delta strike %.2f",
+				roll.OldStrike, roll.NewStrike)
+		}
+		if roll.NewStrike >= position.CallStrike {
+			return nil, fmt.Errorf("new put strike %.2f would cross call strike %.2f", roll.NewStrike, position.CallStrike)
+		}
+	default:
+		return nil, fmt.Errorf("invalid tested side %q", tested)
+	}
+
+	if err := priceRoll(options, roll); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Untested %s roll found: %.2f -> %.2f, net credit %.2f (close %.2f, open %.2f)",
+		roll.Side, roll.OldStrike, roll.NewStrike, roll.NetCredit(), roll.CloseDebit, roll.OpenCredit)
+	return roll, nil
+}
+
+// FindStraddleRoll builds the Third Down repair: the untested side is rolled to the
+// tested strike in the same expiration, turning the strangle into a straddle.
+func (s *StrangleStrategy) FindStraddleRoll(position *models.Position, tested broker.OptionType) (*RollOrder, error) {
+	if position == nil {
+		return nil, fmt.Errorf("position is nil")
+	}
+
+	roll := &RollOrder{Expiration: position.Expiration.Format("2006-01-02")}
+	switch tested {
+	case broker.OptionTypePut:
+		roll.Side = broker.OptionTypeCall
+		roll.OldStrike = position.CallStrike
+		roll.NewStrike = position.PutStrike
+	case broker.OptionTypeCall:
+		roll.Side = broker.OptionTypePut
+		roll.OldStrike = position.PutStrike
+		roll.NewStrike = position.CallStrike
+	default:
+		return nil, fmt.Errorf("invalid tested side %q", tested)
+	}
+	if roll.OldStrike == roll.NewStrike {
+		return nil, fmt.Errorf("position is already a straddle at %.2f", roll.NewStrike)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	options, err := s.getCachedOptionChainWithContext(ctx, position.Symbol, roll.Expiration, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get option chain: %w", err)
+	}
+	if err := priceRoll(options, roll); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Straddle roll found: %s %.2f -> %.2f, net credit %.2f (close %.2f, open %.2f)",
+		roll.Side, roll.OldStrike, roll.NewStrike, roll.NetCredit(), roll.CloseDebit, roll.OpenCredit)
+	return roll, nil
+}
+
+// priceRoll fills in mid prices for both legs of the roll and rejects rolls that
+// have unusable quotes or do not collect a net credit.
+func priceRoll(options []broker.Option, roll *RollOrder) error {
+	oldLeg := broker.GetOptionByStrike(options, roll.OldStrike, roll.Side)
+	newLeg := broker.GetOptionByStrike(options, roll.NewStrike, roll.Side)
+	if oldLeg == nil || newLeg == nil {
+		return fmt.Errorf("could not find %s quotes for strikes %.2f -> %.2f", roll.Side, roll.OldStrike, roll.NewStrike)
+	}
+	if oldLeg.Ask <= 0 || newLeg.Bid <= 0 || oldLeg.Bid > oldLeg.Ask || newLeg.Bid > newLeg.Ask {
+		return fmt.Errorf("invalid quotes - old bid/ask: %.2f/%.2f, new bid/ask: %.2f/%.2f",
+			oldLeg.Bid, oldLeg.Ask, newLeg.Bid, newLeg.Ask)
+	}
+
+	roll.CloseDebit = (oldLeg.Bid + oldLeg.Ask) / 2
+	roll.OpenCredit = (newLeg.Bid + newLeg.Ask) / 2
+	if roll.NetCredit() <= 0 {
+		return fmt.Errorf("roll %.2f -> %.2f is not a credit: close %.2f, open %.2f",
+			roll.OldStrike, roll.NewStrike, roll.CloseDebit, roll.OpenCredit)
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package strategy
+
+import (
+	"io"
+	"log"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/pricing"
+	"github.com/eddiefleurent/scranton_strangler/internal/storage"
+)
+
+// rollTestChain models SPY trading near 420 after a drop toward a 400/460 strangle
+func rollTestChain() []broker.Option {
+	return []broker.Option{
+		{Strike: 400, OptionType: "put", Bid: 3.00, Ask: 3.20, Greeks: &broker.Greeks{Delta: -0.30}},
+		{Strike: 405, OptionType: "put", Bid: 4.00, Ask: 4.20, Greeks: &broker.Greeks{Delta: -0.36}},
+		{Strike: 435, OptionType: "call", Bid: 1.50, Ask: 1.60, Greeks: &broker.Greeks{Delta: 0.16}},
+		{Strike: 445, OptionType: "call", Bid: 0.70, Ask: 0.80, Greeks: &broker.Greeks{Delta: 0.09}},
+		{Strike: 460, OptionType: "call", Bid: 0.20, Ask: 0.30, Greeks: &broker.Greeks{Delta: 0.03}},
+	}
+}
+
+func newRollTestStrategy(chain []broker.Option) *StrangleStrategy {
+	mockBroker := &mockBrokerForStrategy{chain: chain}
+	config := &Config{Symbol: "SPY", DeltaTarget: 0.16}
+	return NewStrangleStrategy(mockBroker, config, log.New(io.Discard, "", 0), storage.NewMockStorage())
+}
+
+func TestTestedSide(t *testing.T) {
+	position := &models.Position{PutStrike: 400, CallStrike: 460}
+
+	tests := []struct {
+		name           string
+		spot           float64
+		wantSide       broker.OptionType
+		wantChallenged bool
+	}{
+		{"centered", 430, broker.OptionTypePut, false},
+		{"put challenged", 408, broker.OptionTypePut, true},
+		{"put breached", 395, broker.OptionTypePut, true},
+		{"call challenged", 452, broker.OptionTypeCall, true},
+		{"call not yet challenged", 449, broker.OptionTypeCall, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			side, challenged := TestedSide(position, tt.spot, 10)
+			if side != tt.wantSide || challenged != tt.wantChallenged {
+				t.Errorf("TestedSide(%.0f) = (%s, %t), want (%s, %t)",
+					tt.spot, side, challenged, tt.wantSide, tt.wantChallenged)
+			}
+		})
+	}
+
+	if _, challenged := TestedSide(nil, 420, 10); challenged {
+		t.Error("nil position should never be challenged")
+	}
+}
+
+func TestStrangleStrategy_FindUntestedRoll(t *testing.T) {
+	position := &models.Position{
+		Symbol:     "SPY",
+		PutStrike:  400,
+		CallStrike: 460,
+		Expiration: time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC),
+		Quantity:   1,
+	}
+
+	t.Run("rolls untested call down to target delta", func(t *testing.T) {
+		s := newRollTestStrategy(rollTestChain())
+		roll, err := s.FindUntestedRoll(position, broker.OptionTypePut, 420)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if roll.Side != broker.OptionTypeCall || roll.OldStrike != 460 || roll.NewStrike != 435 {
+			t.Errorf("unexpected roll: %+v", roll)
+		}
+		if math.Abs(roll.NetCredit()-1.30) > 1e-9 {
+			t.Errorf("NetCredit() = %.4f, want 1.30", roll.NetCredit())
+		}
+	})
+
+	t.Run("no roll when untested strike is already at target", func(t *testing.T) {
+		s := newRollTestStrategy(rollTestChain())
+		atTarget := *position
+		atTarget.CallStrike = 435
+		if _, err := s.FindUntestedRoll(&atTarget, broker.OptionTypePut, 420); err == nil {
+			t.Error("expected error when call is already at target delta")
+		}
+	})
+
+	t.Run("rejects debit roll", func(t *testing.T) {
+		chain := rollTestChain()
+		chain[2].Bid, chain[2].Ask = 0.05, 0.10
+		s := newRollTestStrategy(chain)
+		if _, err := s.FindUntestedRoll(position, broker.OptionTypePut, 420); err == nil {
+			t.Error("expected error for a roll that does not collect credit")
+		}
+	})
+
+	t.Run("computes missing greeks at spot", func(t *testing.T) {
+		// A quoted chain at 15% vol with no greeks, as the sandbox returns it
+		const spot, vol = 100.0, 0.15
+		model := pricing.Model{}
+		expiration := time.Now().AddDate(0, 0, 30)
+		exp := expiration.Format("2006-01-02")
+		years, err := pricing.YearsToExpiration(exp, time.Now())
+		if err != nil {
+			t.Fatalf("YearsToExpiration error: %v", err)
+		}
+		var chain []broker.Option
+		wantCall, best := 0.0, math.MaxFloat64
+		for strike := 80.0; strike <= 120; strike++ {
+			for _, isCall := range []bool{false, true} {
+				optType := "put"
+				if isCall {
+					optType = "call"
+					if d := math.Abs(model.Greeks(true, spot, strike, years, vol).Delta - 0.16); d < best {
+						best, wantCall = d, strike
+					}
+				}
+				price := model.Price(isCall, spot, strike, years, vol)
+				chain = append(chain, broker.Option{
+					Strike: strike, OptionType: optType, ExpirationDate: exp,
+					Bid: math.Max(price-0.01, 0), Ask: price + 0.01,
+				})
+			}
+		}
+
+		s := newRollTestStrategy(chain)
+		drifted := &models.Position{Symbol: "SPY", PutStrike: 90, CallStrike: 120, Expiration: expiration, Quantity: 1}
+		roll, err := s.FindUntestedRoll(drifted, broker.OptionTypePut, spot)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if roll.Side != broker.OptionTypeCall || roll.NewStrike != wantCall {
+			t.Errorf("roll = %s to %.0f, want call to 16 delta strike %.0f", roll.Side, roll.NewStrike, wantCall)
+		}
+	})
+}
+
+func TestStrikeBreached(t *testing.T) {
+	position := &models.Position{PutStrike: 400, CallStrike: 460}
+
+	if StrikeBreached(position, broker.OptionTypePut, 401) {
+		t.Error("put should not be breached above the strike")
+	}
+	if !StrikeBreached(position, broker.OptionTypePut, 400) {
+		t.Error("put should be breached at the strike")
+	}
+	if !StrikeBreached(position, broker.OptionTypeCall, 462) {
+		t.Error("call should be breached above the strike")
+	}
+	if StrikeBreached(nil, broker.OptionTypePut, 390) {
+		t.Error("nil position should never be breached")
+	}
+}
+
+func TestStrangleStrategy_FindStraddleRoll(t *testing.T) {
+	position := &models.Position{
+		Symbol:     "SPY",
+		PutStrike:  400,
+		CallStrike: 435,
+		Expiration: time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC),
+		Quantity:   1,
+	}
+	chain := append(rollTestChain(),
+		broker.Option{Strike: 400, OptionType: "call", Bid: 8.90, Ask: 9.10, Greeks: &broker.Greeks{Delta: 0.55}})
+
+	t.Run("rolls untested call down to the put strike", func(t *testing.T) {
+		s := newRollTestStrategy(chain)
+		roll, err := s.FindStraddleRoll(position, broker.OptionTypePut)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if roll.Side != broker.OptionTypeCall || roll.OldStrike != 435 || roll.NewStrike != 400 {
+			t.Errorf("unexpected roll: %+v", roll)
+		}
+		if math.Abs(roll.NetCredit()-7.45) > 1e-9 {
+			t.Errorf("NetCredit() = %.4f, want 7.45", roll.NetCredit())
+		}
+	})
+
+	t.Run("already a straddle", func(t *testing.T) {
+		s := newRollTestStrategy(chain)
+		straddle := *position
+		straddle.CallStrike = 400
+		if _, err := s.FindStraddleRoll(&straddle, broker.OptionTypePut); err == nil {
+			t.Error("expected error when position is already a straddle")
+		}
+	})
+
+	t.Run("missing quote for tested strike", func(t *testing.T) {
+		s := newRollTestStrategy(rollTestChain())
+		if _, err := s.FindStraddleRoll(position, broker.OptionTypePut); err == nil {
+			t.Error("expected error when the new leg has no quote")
+		}
+	})
+}
+
+func TestStrangleStrategy_ProfitTargetFor(t *testing.T) {
+	s := newRollTestStrategy(nil)
+	s.config.ProfitTarget = 0.50
+	s.config.ThirdDownProfitTarget = 0.25
+
+	position := models.NewPosition("pt-test", "SPY", 400, 460, time.Now().AddDate(0, 0, 30), 1)
+	if got := s.ProfitTargetFor(position); got != 0.50 {
+		t.Errorf("ProfitTargetFor(idle) = %.2f, want 0.50", got)
+	}
+
+	position.StateMachine = models.NewStateMachineFromState(models.StateThirdDown)
+	position.State = models.StateThirdDown
+	if got := s.ProfitTargetFor(position); got != 0.25 {
+		t.Errorf("ProfitTargetFor(third down) = %.2f, want 0.25", got)
+	}
+
+	position.StateMachine = models.NewStateMachineFromState(models.StateFourthDown)
+	position.State = models.StateFourthDown
+	position.SetFourthDownOption(models.OptionB)
+	if got := s.ProfitTargetFor(position); got != 0.25 {
+		t.Errorf("ProfitTargetFor(fourth down B) = %.2f, want 0.25", got)
+	}
+	position.SetFourthDownOption(models.OptionA)
+	if got := s.ProfitTargetFor(position); got != 0 {
+		t.Errorf("ProfitTargetFor(fourth down A) = %.2f, want 0 (breakeven)", got)
+	}
+
+	position.StateMachine = models.NewStateMachineFromState(models.StateThirdDown)
+	position.State = models.StateThirdDown
+	s.config.ThirdDownProfitTarget = 0
+	if got := s.ProfitTargetFor(position); got != 0.50 {
+		t.Errorf("ProfitTargetFor(third down, unset) = %.2f, want fallback 0.50", got)
+	}
+}
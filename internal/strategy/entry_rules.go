@@ -0,0 +1,68 @@
+package strategy
+
+import "fmt"
+
+// defaultEntryRuleName labels entries that use the base DTE and delta settings
+const defaultEntryRuleName = "default"
+
+// EntryRule selects the target DTE and delta for new entries within an IV Rank band.
+// A rule matches IV Ranks in [MinIVR, MaxIVR); a MaxIVR of 100 also matches an IVR of 100.
+type EntryRule struct {
+	Name        string
+	MinIVR      float64
+	MaxIVR      float64
+	DTETarget   int
+	DTERange    []int   // Acceptable DTE range [min, max]
+	DeltaTarget float64 // Fractional, e.g. 0.30 for 30 delta
+}
+
+// matches reports whether ivr falls inside the rule's band
+func (r EntryRule) matches(ivr float64) bool {
+	if ivr < r.MinIVR {
+		return false
+	}
+	return ivr < r.MaxIVR || (r.MaxIVR >= 100 && ivr <= 100)
+}
+
+// defaultEntryRule returns the base DTE and delta from the strategy config
+func (s *StrangleStrategy) defaultEntryRule() EntryRule {
+	return EntryRule{
+		Name:        defaultEntryRuleName,
+		DTETarget:   s.config.DTETarget,
+		DTERange:    s.config.DTERange,
+		DeltaTarget: s.config.DeltaTarget,
+	}
+}
+
+// selectEntryRule picks the first IVR rule matching the current IV Rank. It returns
+// the default rule, with the reason, when no rules are configured, IV history is too
+// short, or no band matches. stats is nil when IV Rank could not be computed.
+func (s *StrangleStrategy) selectEntryRule() (rule EntryRule, stats *IVStats, reason string) {
+	rule = s.defaultEntryRule()
+	if len(s.config.IVRRules) == 0 {
+		return rule, nil, "no IVR rules configured"
+	}
+
+	currentIV, err := s.getCurrentImpliedVolatility()
+	if err != nil {
+		return rule, nil, fmt.Sprintf("IV unavailable: %v", err)
+	}
+	readings, err := s.getIVHistory()
+	if err != nil {
+		return rule, nil, fmt.Sprintf("IV history unavailable: %v", err)
+	}
+	if need := s.minIVRReadings(); len(readings) < need {
+		return rule, nil, fmt.Sprintf("only %d IV readings (need %d)", len(readings), need)
+	}
+	stats, err = CalculateIVStats(currentIV, readings)
+	if err != nil {
+		return rule, nil, err.Error()
+	}
+
+	for _, r := range s.config.IVRRules {
+		if r.matches(stats.Rank) {
+			return r, stats, fmt.Sprintf("IVR %.1f in [%.0f, %.0f)", stats.Rank, r.MinIVR, r.MaxIVR)
+		}
+	}
+	return rule, stats, fmt.Sprintf("no IVR rule matches IVR %.1f", stats.Rank)
+}
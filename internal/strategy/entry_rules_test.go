@@ -0,0 +1,143 @@
+package strategy
+
+import (
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/storage"
+)
+
+// roadmapRules are the ROADMAP Phase 3 IV regimes
+var roadmapRules = []EntryRule{
+	{Name: "low_iv", MinIVR: 0, MaxIVR: 30, DTETarget: 60, DTERange: []int{55, 65}, DeltaTarget: 0.16},
+	{Name: "normal_iv", MinIVR: 30, MaxIVR: 50, DTETarget: 45, DTERange: []int{40, 50}, DeltaTarget: 0.16},
+	{Name: "high_iv", MinIVR: 50, MaxIVR: 100, DTETarget: 30, DTERange: []int{25, 35}, DeltaTarget: 0.30},
+}
+
+func TestEntryRule_Matches(t *testing.T) {
+	tests := []struct {
+		ivr  float64
+		want string
+	}{
+		{0, "low_iv"},
+		{29.9, "low_iv"},
+		{30, "normal_iv"},
+		{50, "high_iv"},
+		{100, "high_iv"},
+	}
+	for _, tt := range tests {
+		got := ""
+		for _, r := range roadmapRules {
+			if r.matches(tt.ivr) {
+				got = r.Name
+				break
+			}
+		}
+		if got != tt.want {
+			t.Errorf("IVR %.1f matched %q, want %q", tt.ivr, got, tt.want)
+		}
+	}
+}
+
+// newEntryRuleTestStrategy builds a strategy on a $100 underlying whose ATM IV is currentIV,
+// with the given days of IV history alternating between 10% and 20%
+func newEntryRuleTestStrategy(t *testing.T, currentIV float64, historyDays int) *StrangleStrategy {
+	t.Helper()
+	greeks := func(delta float64) *broker.Greeks { return &broker.Greeks{Delta: delta, MidIV: currentIV} }
+	var expirations []string
+	for _, dte := range []int{30, 45, 60} {
+		expirations = append(expirations, time.Now().AddDate(0, 0, dte).Format("2006-01-02"))
+	}
+	mockBroker := &mockBrokerForStrategy{
+		quote:       &broker.QuoteItem{Last: 100.0},
+		expirations: expirations,
+		chain: []broker.Option{
+			{Strike: 90, OptionType: "put", Bid: 0.40, Ask: 0.50, Greeks: greeks(-0.10)},
+			{Strike: 95, OptionType: "put", Bid: 0.90, Ask: 1.00, Greeks: greeks(-0.16)},
+			{Strike: 97, OptionType: "put", Bid: 1.40, Ask: 1.50, Greeks: greeks(-0.30)},
+			{Strike: 100, OptionType: "call", Bid: 2.40, Ask: 2.60, Greeks: greeks(0.50)},
+			{Strike: 103, OptionType: "call", Bid: 1.40, Ask: 1.50, Greeks: greeks(0.30)},
+			{Strike: 105, OptionType: "call", Bid: 0.90, Ask: 1.00, Greeks: greeks(0.16)},
+			{Strike: 110, OptionType: "call", Bid: 0.40, Ask: 0.50, Greeks: greeks(0.10)},
+		},
+	}
+	store := storage.NewMockStorage()
+	for _, r := range ivHistory(historyDays, 0.10, 0.20) {
+		if err := store.StoreIVReading(&r); err != nil {
+			t.Fatalf("failed to store reading: %v", err)
+		}
+	}
+	config := &Config{
+		Symbol:        "SPY",
+		DTETarget:     45,
+		DTERange:      []int{40, 50},
+		DeltaTarget:   0.16,
+		MinCredit:     0.50,
+		AllocationPct: 1.0,
+		IVRRules:      roadmapRules,
+	}
+	return NewStrangleStrategy(mockBroker, config, log.New(io.Discard, "", 0), store)
+}
+
+func TestStrangleStrategy_FindStrangleStrikes_IVRRules(t *testing.T) {
+	tests := []struct {
+		name       string
+		currentIV  float64
+		history    int
+		wantRule   string
+		wantDTE    int
+		wantPut    float64
+		wantCall   float64
+		wantIVStat bool
+	}{
+		{"low IVR uses 60 DTE at 16 delta", 0.11, 70, "low_iv", 60, 95, 105, true},
+		{"high IVR uses 30 DTE at 30 delta", 0.19, 70, "high_iv", 30, 97, 103, true},
+		{"short history uses base config", 0.19, 40, defaultEntryRuleName, 45, 95, 105, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newEntryRuleTestStrategy(t, tt.currentIV, tt.history)
+
+			order, err := s.FindStrangleStrikes()
+			if err != nil {
+				t.Fatalf("FindStrangleStrikes() error: %v", err)
+			}
+			if order.EntryRule != tt.wantRule {
+				t.Errorf("EntryRule = %q, want %q", order.EntryRule, tt.wantRule)
+			}
+			if (order.IVStats != nil) != tt.wantIVStat {
+				t.Errorf("IVStats = %+v, want present=%v", order.IVStats, tt.wantIVStat)
+			}
+			wantExp := time.Now().AddDate(0, 0, tt.wantDTE).Format("2006-01-02")
+			if order.Expiration != wantExp {
+				t.Errorf("Expiration = %s, want %s", order.Expiration, wantExp)
+			}
+			if order.PutStrike != tt.wantPut || order.CallStrike != tt.wantCall {
+				t.Errorf("strikes = %.0f/%.0f, want %.0f/%.0f", order.PutStrike, order.CallStrike, tt.wantPut, tt.wantCall)
+			}
+		})
+	}
+}
+
+func TestStrangleStrategy_GetVariedDTETarget_UsesRuleRange(t *testing.T) {
+	s := newEntryRuleTestStrategy(t, 0.19, 0)
+	rule := roadmapRules[2]
+
+	if got := s.getVariedDTETarget(rule); got != 30 {
+		t.Errorf("with no positions got %d, want rule target 30", got)
+	}
+
+	// An open position at the rule target pushes the next entry two days out
+	position := models.NewPosition("existing", "SPY", 95, 105, time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 30), 1)
+	if err := s.storage.AddPosition(position); err != nil {
+		t.Fatalf("failed to add position: %v", err)
+	}
+	if got := s.getVariedDTETarget(rule); got != 28 {
+		t.Errorf("with a 30 DTE position got %d, want 28", got)
+	}
+}
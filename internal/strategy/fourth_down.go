@@ -0,0 +1,241 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+)
+
+// fourthDownPuntWindowDays is how close to the MaxDTE time exit a Fourth Down position
+// must be before a time roll (Option C) is preferred over staying in the current cycle.
+const fourthDownPuntWindowDays = 7
+
+// PuntOrder describes an Option C time roll: the whole position is closed and a new
+// strangle is opened at the target delta in the next monthly expiration.
+type PuntOrder struct {
+	Expiration     string
+	ExpirationDate time.Time
+	PutStrike      float64
+	CallStrike     float64
+	CloseDebit     float64 // Per-share mid to buy back the current legs
+	OpenCredit     float64 // Per-share mid for the new strangle
+}
+
+// NetCredit returns the per-share credit collected by the punt (negative for a debit).
+func (p *PuntOrder) NetCredit() float64 {
+	return p.OpenCredit - p.CloseDebit
+}
+
+// pointsPastBreakeven returns how far spot is beyond the tested side's breakeven,
+// measured with the net credit including adjustments. Negative values mean spot is
+// still inside the breakeven.
+func pointsPastBreakeven(position *models.Position, tested broker.OptionType, spot float64) float64 {
+	netCredit := position.GetNetCredit()
+	switch tested {
+	case broker.OptionTypePut:
+		return (position.PutStrike - netCredit) - spot
+	case broker.OptionTypeCall:
+		return spot - (position.CallStrike + netCredit)
+	default:
+		return math.Inf(-1)
+	}
+}
+
+// ApproachingBreakeven reports whether spot is within threshold points of, or beyond,
+// the tested side's breakeven. This is the trigger for Fourth Down.
+func ApproachingBreakeven(position *models.Position, tested broker.OptionType, spot, threshold float64) bool {
+	if position == nil || spot <= 0 {
+		return false
+	}
+	return pointsPastBreakeven(position, tested, spot) >= -threshold
+}
+
+// ChooseFourthDownOption evaluates the Fourth Down decision matrix and returns the
+// selected option with a human-readable reason:
+//   - Option C (punt) when the position is within a week of the MaxDTE time exit and
+//     has not been time-rolled before
+//   - Option A (inverted strangle) when spot is past breakeven and adjustments remain
+//   - Option B (hold straddle) otherwise
+func (s *StrangleStrategy) ChooseFourthDownOption(position *models.Position, spot float64) (models.FourthDownOption, string) {
+	if position == nil {
+		return models.OptionNone, "position is nil"
+	}
+
+	dte := position.CalculateDTE()
+	daysToTimeExit := dte - s.config.MaxDTE
+	tested, _ := TestedSide(position, spot, 0)
+	pastBreakeven := pointsPastBreakeven(position, tested, spot)
+	canPunt := position.CanRoll() && position.CanPunt()
+	canAdjust := position.CanAdjust()
+
+	if daysToTimeExit <= fourthDownPuntWindowDays && canPunt {
+		return models.OptionC, fmt.Sprintf("%d DTE leaves %d days before the %d DTE exit; rolling out in time",
+			dte, daysToTimeExit, s.config.MaxDTE)
+	}
+	if pastBreakeven >= 0 {
+		if canAdjust {
+			return models.OptionA, fmt.Sprintf("spot %.2f is %.2f points past the %s breakeven with %d DTE; inverting untested side",
+				spot, pastBreakeven, tested, dte)
+		}
+		if canPunt {
+			return models.OptionC, fmt.Sprintf("spot %.2f is %.2f points past the %s breakeven and no adjustments remain; rolling out in time",
+				spot, pastBreakeven, tested)
+		}
+	}
+	return models.OptionB, fmt.Sprintf("spot %.2f is %.2f points inside the %s breakeven with %d DTE; holding straddle",
+		spot, -pastBreakeven, tested, dte)
+}
+
+// FindInvertedRoll builds Option A: the untested side is rolled past the tested strike,
+// to the strike nearest spot, inverting the strangle for additional credit.
+func (s *StrangleStrategy) FindInvertedRoll(position *models.Position, tested broker.OptionType, spot float64) (*RollOrder, error) {
+	if position == nil {
+		return nil, fmt.Errorf("position is nil")
+	}
+
+	roll := &RollOrder{Expiration: position.Expiration.Format("2006-01-02")}
+	var testedStrike float64
+	switch tested {
+	case broker.OptionTypePut:
+		roll.Side = broker.OptionTypeCall
+		roll.OldStrike = position.CallStrike
+		testedStrike = position.PutStrike
+	case broker.OptionTypeCall:
+		roll.Side = broker.OptionTypePut
+		roll.OldStrike = position.PutStrike
+		testedStrike = position.CallStrike
+	default:
+		return nil, fmt.Errorf("invalid tested side %q", tested)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	options, err := s.getCachedOptionChainWithContext(ctx, position.Symbol, roll.Expiration, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get option chain: %w", err)
+	}
+
+	bestDistance := math.Inf(1)
+	for _, opt := range options {
+		if !broker.OptionTypeMatches(opt.OptionType, roll.Side) {
+			continue
+		}
+		// An inverted strangle has the call below the put (or the put above the call)
+		if (roll.Side == broker.OptionTypeCall && opt.Strike >= testedStrike) ||
+			(roll.Side == broker.OptionTypePut && opt.Strike <= testedStrike) {
+			continue
+		}
+		if d := math.Abs(opt.Strike - spot); d < bestDistance {
+			bestDistance = d
+			roll.NewStrike = opt.Strike
+		}
+	}
+	if roll.NewStrike == 0 {
+		return nil, fmt.Errorf("no %s strike available past tested strike %.2f", roll.Side, testedStrike)
+	}
+	if err := priceRoll(options, roll); err != nil {
+		return nil, err
+	}
+
+	s.logger.Printf("Inverted roll found: %s %.2f -> %.2f past tested %.2f, net credit %.2f",
+		roll.Side, roll.OldStrike, roll.NewStrike, testedStrike, roll.NetCredit())
+	return roll, nil
+}
+
+// FindPuntRoll builds Option C: close the whole position and reopen a target-delta
+// strangle in the next monthly expiration after the current one. The punt must
+// collect a net credit.
+func (s *StrangleStrategy) FindPuntRoll(position *models.Position, spot float64) (*PuntOrder, error) {
+	if position == nil {
+		return nil, fmt.Errorf("position is nil")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	exps, err := s.broker.GetExpirationsCtx(ctx, position.Symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expirations: %w", err)
+	}
+	nextExp, ok := nextMonthlyExpiration(exps, position.Expiration)
+	if !ok {
+		return nil, fmt.Errorf("no monthly expiration after %s", position.Expiration.Format("2006-01-02"))
+	}
+	punt := &PuntOrder{Expiration: nextExp.Format("2006-01-02"), ExpirationDate: nextExp}
+
+	current, err := s.getCachedOptionChainWithContext(ctx, position.Symbol, position.Expiration.Format("2006-01-02"), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current option chain: %w", err)
+	}
+	put := broker.GetOptionByStrike(current, position.PutStrike, broker.OptionTypePut)
+	call := broker.GetOptionByStrike(current, position.CallStrike, broker.OptionTypeCall)
+	if put == nil || call == nil || put.Ask <= 0 || call.Ask <= 0 {
+		return nil, fmt.Errorf("missing quotes to close %.2f/%.2f", position.PutStrike, position.CallStrike)
+	}
+	punt.CloseDebit = (put.Bid+put.Ask)/2 + (call.Bid+call.Ask)/2
+
+	options, err := s.getCachedOptionChainWithContext(ctx, position.Symbol, punt.Expiration, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get option chain for %s: %w", punt.Expiration, err)
+	}
+	options = s.withLocalGreeks(options, spot)
+	punt.PutStrike = s.findStrikeByDelta(options, -s.config.DeltaTarget, true)
+	punt.CallStrike = s.findStrikeByDelta(options, s.config.DeltaTarget, false)
+	if punt.PutStrike == 0 || punt.CallStrike == 0 {
+		return nil, fmt.Errorf("no strikes found near target delta in %s", punt.Expiration)
+	}
+	if err := s.validateStrikeSelection(punt.PutStrike, punt.CallStrike, spot); err != nil {
+		return nil, fmt.Errorf("strike validation failed: %w", err)
+	}
+	punt.OpenCredit = s.calculateExpectedCredit(options, punt.PutStrike, punt.CallStrike)
+	if punt.OpenCredit <= 0 {
+		return nil, fmt.Errorf("no usable credit for %.2f/%.2f in %s", punt.PutStrike, punt.CallStrike, punt.Expiration)
+	}
+	if punt.NetCredit() <= 0 {
+		return nil, fmt.Errorf("punt to %s is not a credit: close %.2f, open %.2f",
+			punt.Expiration, punt.CloseDebit, punt.OpenCredit)
+	}
+
+	s.logger.Printf("Punt found: %s %.2f/%.2f, net credit %.2f (close %.2f, open %.2f)",
+		punt.Expiration, punt.PutStrike, punt.CallStrike, punt.NetCredit(), punt.CloseDebit, punt.OpenCredit)
+	return punt, nil
+}
+
+// nextMonthlyExpiration returns the earliest standard monthly expiration (third Friday)
+// in exps that falls after the given date. When the third Friday is an exchange holiday,
+// such as Good Friday, the monthly expires the Thursday before; a Thursday counts when
+// the broker lists no expiration on the Friday after it.
+func nextMonthlyExpiration(exps []string, after time.Time) (time.Time, bool) {
+	afterDay := after.UTC().Truncate(24 * time.Hour)
+	listed := make(map[string]bool, len(exps))
+	for _, e := range exps {
+		listed[e] = true
+	}
+	var monthlies []time.Time
+	for _, e := range exps {
+		d, err := time.Parse("2006-01-02", e)
+		if err != nil || !d.After(afterDay) {
+			continue
+		}
+		thirdFriday := d
+		if d.Weekday() == time.Thursday {
+			thirdFriday = d.AddDate(0, 0, 1)
+			if listed[thirdFriday.Format("2006-01-02")] {
+				continue
+			}
+		}
+		if thirdFriday.Weekday() == time.Friday && thirdFriday.Day() >= 15 && thirdFriday.Day() <= 21 {
+			monthlies = append(monthlies, d)
+		}
+	}
+	if len(monthlies) == 0 {
+		return time.Time{}, false
+	}
+	sort.Slice(monthlies, func(i, j int) bool { return monthlies[i].Before(monthlies[j]) })
+	return monthlies[0], true
+}
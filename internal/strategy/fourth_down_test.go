@@ -0,0 +1,180 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+)
+
+// newFourthDownPosition returns a 400 straddle (after Second and Third Down rolls) with the given DTE
+func newFourthDownPosition(dte int) *models.Position {
+	position := &models.Position{
+		ID:             "fourth-down-test",
+		Symbol:         "SPY",
+		PutStrike:      400,
+		CallStrike:     400,
+		Expiration:     time.Now().UTC().AddDate(0, 0, dte),
+		Quantity:       1,
+		CreditReceived: 2.50,
+		State:          models.StateFourthDown,
+		Adjustments: []models.Adjustment{
+			{Type: models.AdjustmentRoll, Credit: 1.30},
+			{Type: models.AdjustmentRoll, Credit: 8.75},
+		},
+	}
+	position.StateMachine = models.NewStateMachineFromState(models.StateFourthDown)
+	return position
+}
+
+func TestApproachingBreakeven(t *testing.T) {
+	position := newFourthDownPosition(40) // Net credit 12.55, put breakeven 387.45
+
+	if ApproachingBreakeven(position, broker.OptionTypePut, 399, 10) {
+		t.Error("spot 11.55 points inside breakeven should not trigger Fourth Down")
+	}
+	if !ApproachingBreakeven(position, broker.OptionTypePut, 397, 10) {
+		t.Error("spot within 10 points of breakeven should trigger Fourth Down")
+	}
+	if !ApproachingBreakeven(position, broker.OptionTypePut, 380, 10) {
+		t.Error("spot past breakeven should trigger Fourth Down")
+	}
+}
+
+func TestStrangleStrategy_ChooseFourthDownOption(t *testing.T) {
+	s := newRollTestStrategy(nil)
+	s.config.MaxDTE = 21
+
+	tests := []struct {
+		name       string
+		dte        int
+		spot       float64
+		maxAdjust  int
+		maxRolls   int
+		wantOption models.FourthDownOption
+	}{
+		{"near time exit punts", 25, 392, 3, 1, models.OptionC},
+		{"past breakeven inverts", 40, 385, 3, 1, models.OptionA},
+		{"inside breakeven holds", 40, 392, 3, 1, models.OptionB},
+		{"past breakeven without adjustments punts", 40, 385, 0, 1, models.OptionC},
+		{"near time exit after a punt holds", 25, 392, 3, 0, models.OptionB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			position := newFourthDownPosition(tt.dte)
+			position.StateMachine.SetMaxAdjustments(tt.maxAdjust)
+			position.StateMachine.SetMaxTimeRolls(tt.maxRolls)
+
+			option, reason := s.ChooseFourthDownOption(position, tt.spot)
+			if option != tt.wantOption {
+				t.Errorf("ChooseFourthDownOption() = %s (%s), want %s", option, reason, tt.wantOption)
+			}
+			if reason == "" {
+				t.Error("expected a reason for the decision")
+			}
+		})
+	}
+}
+
+func TestStrangleStrategy_FindInvertedRoll(t *testing.T) {
+	chain := []broker.Option{
+		{Strike: 390, OptionType: "call", Bid: 10.90, Ask: 11.10},
+		{Strike: 395, OptionType: "call", Bid: 7.90, Ask: 8.10},
+		{Strike: 400, OptionType: "call", Bid: 4.90, Ask: 5.10},
+		{Strike: 405, OptionType: "call", Bid: 2.90, Ask: 3.10},
+	}
+	s := newRollTestStrategy(chain)
+
+	roll, err := s.FindInvertedRoll(newFourthDownPosition(40), broker.OptionTypePut, 392)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if roll.Side != broker.OptionTypeCall || roll.OldStrike != 400 || roll.NewStrike != 390 {
+		t.Errorf("unexpected roll: %+v", roll)
+	}
+	if math.Abs(roll.NetCredit()-6.00) > 1e-9 {
+		t.Errorf("NetCredit() = %.4f, want 6.00", roll.NetCredit())
+	}
+
+	// No strikes below the tested put means there is nothing to invert into
+	s = newRollTestStrategy(chain[2:])
+	if _, err := s.FindInvertedRoll(newFourthDownPosition(40), broker.OptionTypePut, 392); err == nil {
+		t.Error("expected error when no call strike is below the tested strike")
+	}
+}
+
+func TestStrangleStrategy_FindPuntRoll(t *testing.T) {
+	position := newFourthDownPosition(10)
+	position.PutStrike, position.CallStrike = 410, 430
+
+	nextMonthly := position.Expiration.AddDate(0, 1, 0)
+	for nextMonthly.Weekday() != time.Friday || nextMonthly.Day() < 15 || nextMonthly.Day() > 21 {
+		nextMonthly = nextMonthly.AddDate(0, 0, 1)
+	}
+
+	mockBroker := &mockBrokerForStrategy{
+		expirations: []string{
+			position.Expiration.Format("2006-01-02"),
+			nextMonthly.AddDate(0, 0, -7).Format("2006-01-02"), // Weekly, not a third Friday
+			nextMonthly.Format("2006-01-02"),
+		},
+		chain: []broker.Option{
+			{Strike: 395, OptionType: "put", Bid: 1.90, Ask: 2.00, Greeks: &broker.Greeks{Delta: -0.16}},
+			{Strike: 410, OptionType: "put", Bid: 1.00, Ask: 1.10, Greeks: &broker.Greeks{Delta: -0.30}},
+			{Strike: 430, OptionType: "call", Bid: 0.50, Ask: 0.60, Greeks: &broker.Greeks{Delta: 0.30}},
+			{Strike: 445, OptionType: "call", Bid: 1.90, Ask: 2.00, Greeks: &broker.Greeks{Delta: 0.16}},
+		},
+	}
+	s := newRollTestStrategy(nil)
+	s.broker = mockBroker
+
+	punt, err := s.FindPuntRoll(position, 420)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if punt.Expiration != nextMonthly.Format("2006-01-02") {
+		t.Errorf("Expiration = %s, want %s", punt.Expiration, nextMonthly.Format("2006-01-02"))
+	}
+	if punt.PutStrike != 395 || punt.CallStrike != 445 {
+		t.Errorf("strikes = %.0f/%.0f, want 395/445", punt.PutStrike, punt.CallStrike)
+	}
+	if math.Abs(punt.NetCredit()-2.30) > 1e-9 {
+		t.Errorf("NetCredit() = %.4f, want 2.30", punt.NetCredit())
+	}
+
+	mockBroker.expirations = mockBroker.expirations[:2]
+	s.chainCache = make(map[string]*optionChainCacheEntry)
+	if _, err := s.FindPuntRoll(position, 420); err == nil {
+		t.Error("expected error when no later monthly expiration exists")
+	}
+}
+
+func TestNextMonthlyExpiration(t *testing.T) {
+	after := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+	exps := []string{"2024-12-20", "2024-12-27", "2025-01-10", "2025-01-17", "2025-02-21", "bad"}
+
+	got, ok := nextMonthlyExpiration(exps, after)
+	if !ok || got.Format("2006-01-02") != "2025-01-17" {
+		t.Errorf("nextMonthlyExpiration() = %s, %t; want 2025-01-17", got.Format("2006-01-02"), ok)
+	}
+
+	if _, ok := nextMonthlyExpiration([]string{"2024-12-27", "2025-01-10"}, after); ok {
+		t.Error("expected no monthly expiration among weeklies")
+	}
+
+	// April 2025's third Friday is Good Friday, so the monthly expires on Thursday the 17th
+	goodFriday := []string{"2025-04-11", "2025-04-16", "2025-04-17", "2025-04-25", "2025-05-16"}
+	got, ok = nextMonthlyExpiration(goodFriday, time.Date(2025, 3, 21, 0, 0, 0, 0, time.UTC))
+	if !ok || got.Format("2006-01-02") != "2025-04-17" {
+		t.Errorf("nextMonthlyExpiration() = %s, %t; want 2025-04-17", got.Format("2006-01-02"), ok)
+	}
+
+	// A Thursday is only the monthly when its Friday is not listed
+	got, ok = nextMonthlyExpiration([]string{"2025-01-16", "2025-01-17"}, after)
+	if !ok || got.Format("2006-01-02") != "2025-01-17" {
+		t.Errorf("nextMonthlyExpiration() = %s, %t; want 2025-01-17", got.Format("2006-01-02"), ok)
+	}
+}
@@ -0,0 +1,145 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+)
+
+const (
+	// defaultIVLookbackDays is the IV history window when Config.IVLookbackDays is unset
+	defaultIVLookbackDays = 252
+	// defaultMinIVRReadings is the fewest stored readings that make IV Rank meaningful when
+	// Config.MinIVRReadings is unset; below it the entry gate falls back to the absolute MinIVPct rule.
+	defaultMinIVRReadings = 60
+)
+
+// IVStats summarises the current IV against its stored history. IV values are
+// percentages; Rank and Percentile are 0-100.
+type IVStats struct {
+	Current    float64
+	Low        float64
+	High       float64
+	Rank       float64 // Where Current sits between Low and High
+	Percentile float64 // Share of readings below Current
+	Readings   int
+}
+
+// CalculateIVStats computes IV Rank and IV Percentile of currentIV (decimal) against
+// the given readings. It returns an error when there are no readings.
+func CalculateIVStats(currentIV float64, readings []models.IVReading) (*IVStats, error) {
+	if len(readings) == 0 {
+		return nil, fmt.Errorf("no IV readings")
+	}
+
+	stats := &IVStats{
+		Current:  currentIV * 100,
+		Low:      readings[0].IV * 100,
+		High:     readings[0].IV * 100,
+		Readings: len(readings),
+	}
+	below := 0
+	for _, r := range readings {
+		iv := r.IV * 100
+		if iv < stats.Low {
+			stats.Low = iv
+		}
+		if iv > stats.High {
+			stats.High = iv
+		}
+		if iv < stats.Current {
+			below++
+		}
+	}
+
+	if stats.High > stats.Low {
+		stats.Rank = (stats.Current - stats.Low) / (stats.High - stats.Low) * 100
+		stats.Rank = clampPercent(stats.Rank)
+	}
+	stats.Percentile = float64(below) / float64(len(readings)) * 100
+	return stats, nil
+}
+
+// clampPercent limits v to [0, 100]; the current IV can sit outside the stored range
+func clampPercent(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 100 {
+		return 100
+	}
+	return v
+}
+
+// GetIVStats returns IV Rank and IV Percentile for the current ATM IV over the
+// configured lookback of stored daily readings.
+func (s *StrangleStrategy) GetIVStats() (*IVStats, error) {
+	currentIV, err := s.getCurrentImpliedVolatility()
+	if err != nil {
+		return nil, fmt.Errorf("%s IV unavailable: %w", s.config.Symbol, err)
+	}
+	readings, err := s.getIVHistory()
+	if err != nil {
+		return nil, err
+	}
+	return CalculateIVStats(currentIV, readings)
+}
+
+// getIVHistory returns the most recent lookback readings for the strategy symbol,
+// oldest first. Readings are daily, so the lookback counts trading days.
+func (s *StrangleStrategy) getIVHistory() ([]models.IVReading, error) {
+	if s.storage == nil {
+		return nil, fmt.Errorf("no storage available for IV history")
+	}
+	lookback := s.config.IVLookbackDays
+	if lookback <= 0 {
+		lookback = defaultIVLookbackDays
+	}
+
+	// 252 trading days span roughly 365 calendar days; pad for holidays
+	end := time.Now().UTC()
+	start := end.AddDate(0, 0, -(lookback*365/252 + 7))
+	readings, err := s.storage.GetIVReadings(s.config.Symbol, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get IV readings: %w", err)
+	}
+
+	sort.Slice(readings, func(i, j int) bool { return readings[i].Date.Before(readings[j].Date) })
+	if len(readings) > lookback {
+		readings = readings[len(readings)-lookback:]
+	}
+	return readings, nil
+}
+
+// minIVRReadings returns how many stored readings IV Rank needs before it gates entries
+func (s *StrangleStrategy) minIVRReadings() int {
+	if s.config.MinIVRReadings <= 0 {
+		return defaultMinIVRReadings
+	}
+	return s.config.MinIVRReadings
+}
+
+// checkIVRankThreshold gates entry on IV Rank. ok reports whether enough history
+// exists to use the rank; when it is false the caller falls back to absolute IV.
+func (s *StrangleStrategy) checkIVRankThreshold(currentIV float64) (canTrade bool, reason string, ok bool) {
+	readings, err := s.getIVHistory()
+	if err != nil {
+		return false, fmt.Sprintf("IV history unavailable: %v", err), false
+	}
+	if need := s.minIVRReadings(); len(readings) < need {
+		return false, fmt.Sprintf("only %d IV readings (need %d)", len(readings), need), false
+	}
+	stats, err := CalculateIVStats(currentIV, readings)
+	if err != nil {
+		return false, err.Error(), false
+	}
+
+	if stats.Rank >= s.config.MinIVR {
+		return true, fmt.Sprintf("%s IVR elevated: %.1f >= %.1f (IV %.1f%%, IVP %.1f, %d readings)",
+			s.config.Symbol, stats.Rank, s.config.MinIVR, stats.Current, stats.Percentile, stats.Readings), true
+	}
+	return false, fmt.Sprintf("%s IVR too low: %.1f < %.1f (IV %.1f%%, IVP %.1f, %d readings)",
+		s.config.Symbol, stats.Rank, s.config.MinIVR, stats.Current, stats.Percentile, stats.Readings), true
+}
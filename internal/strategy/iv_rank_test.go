@@ -0,0 +1,134 @@
+package strategy
+
+import (
+	"io"
+	"log"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/storage"
+)
+
+// ivHistory returns one reading per day ending yesterday, cycling through ivs
+func ivHistory(days int, ivs ...float64) []models.IVReading {
+	readings := make([]models.IVReading, 0, days)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for i := 0; i < days; i++ {
+		readings = append(readings, models.IVReading{
+			Symbol: "SPY",
+			Date:   today.AddDate(0, 0, -(days - i)),
+			IV:     ivs[i%len(ivs)],
+		})
+	}
+	return readings
+}
+
+func TestCalculateIVStats(t *testing.T) {
+	readings := ivHistory(5, 0.10, 0.15, 0.20, 0.25, 0.30)
+
+	stats, err := CalculateIVStats(0.25, readings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(stats.Rank-75) > 1e-9 {
+		t.Errorf("Rank = %.2f, want 75", stats.Rank)
+	}
+	if math.Abs(stats.Percentile-60) > 1e-9 {
+		t.Errorf("Percentile = %.2f, want 60", stats.Percentile)
+	}
+	if stats.Low != 10 || stats.High != 30 || stats.Readings != 5 {
+		t.Errorf("unexpected range: %+v", stats)
+	}
+
+	// Current IV above the stored range is clamped to 100
+	stats, _ = CalculateIVStats(0.40, readings)
+	if stats.Rank != 100 || stats.Percentile != 100 {
+		t.Errorf("expected rank and percentile of 100, got %+v", stats)
+	}
+
+	if _, err := CalculateIVStats(0.20, nil); err == nil {
+		t.Error("expected error with no readings")
+	}
+}
+
+func TestStrangleStrategy_CheckVolatilityThreshold_IVRank(t *testing.T) {
+	tests := []struct {
+		name           string
+		history        int
+		minReadings    int
+		currentIV      float64
+		expectCanTrade bool
+		expectReason   string
+	}{
+		{"rank above minimum", 70, 0, 0.16, true, "IVR elevated"},
+		{"rank below minimum despite high absolute IV", 70, 0, 0.13, false, "IVR too low"},
+		{"too little history falls back to absolute IV", 40, 0, 0.13, false, "IV too low"},
+		{"too little history passes absolute IV", 40, 0, 0.35, true, "IV elevated"},
+		{"configured minimum ranks shorter history", 40, 30, 0.16, true, "IVR elevated"},
+		{"configured minimum above history falls back", 70, 100, 0.16, false, "IV too low"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBroker := &mockBrokerForStrategy{
+				quote:       &broker.QuoteItem{Last: 400.0},
+				expirations: []string{time.Now().AddDate(0, 0, 45).Format("2006-01-02")},
+				chain: []broker.Option{
+					{Strike: 400, OptionType: "call", Bid: 2.0, Ask: 2.2, Greeks: &broker.Greeks{MidIV: tt.currentIV}},
+				},
+			}
+			store := storage.NewMockStorage()
+			for _, r := range ivHistory(tt.history, 0.10, 0.20) {
+				if err := store.StoreIVReading(&r); err != nil {
+					t.Fatalf("failed to store reading: %v", err)
+				}
+			}
+			config := &Config{Symbol: "SPY", DTETarget: 45, MinIVPct: 30, MinIVR: 50, IVLookbackDays: 252,
+				MinIVRReadings: tt.minReadings}
+			s := NewStrangleStrategy(mockBroker, config, log.New(io.Discard, "", 0), store)
+
+			canTrade, reason := s.CheckVolatilityThreshold()
+			if canTrade != tt.expectCanTrade {
+				t.Errorf("CheckVolatilityThreshold() = %v (%s), want %v", canTrade, reason, tt.expectCanTrade)
+			}
+			if !strings.Contains(reason, tt.expectReason) {
+				t.Errorf("reason %q does not contain %q", reason, tt.expectReason)
+			}
+		})
+	}
+}
+
+func TestStrangleStrategy_GetIVStats_UsesLookback(t *testing.T) {
+	mockBroker := &mockBrokerForStrategy{
+		quote:       &broker.QuoteItem{Last: 400.0},
+		expirations: []string{time.Now().AddDate(0, 0, 45).Format("2006-01-02")},
+		chain: []broker.Option{
+			{Strike: 400, OptionType: "call", Bid: 2.0, Ask: 2.2, Greeks: &broker.Greeks{MidIV: 0.20}},
+		},
+	}
+	store := storage.NewMockStorage()
+	// Older readings at 50% fall outside a 10-day lookback
+	for _, r := range append(ivHistory(30, 0.50)[:20], ivHistory(9, 0.10, 0.30)...) {
+		if err := store.StoreIVReading(&r); err != nil {
+			t.Fatalf("failed to store reading: %v", err)
+		}
+	}
+	config := &Config{Symbol: "SPY", DTETarget: 45, IVLookbackDays: 10}
+	s := NewStrangleStrategy(mockBroker, config, log.New(io.Discard, "", 0), store)
+
+	stats, err := s.GetIVStats()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 9 history readings plus today's stored reading
+	if stats.Readings != 10 || stats.High != 30 {
+		t.Errorf("expected 10 readings capped at 30%% IV, got %+v", stats)
+	}
+	if math.Abs(stats.Rank-50) > 1e-9 {
+		t.Errorf("Rank = %.2f, want 50", stats.Rank)
+	}
+}
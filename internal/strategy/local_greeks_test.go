@@ -0,0 +1,73 @@
+package strategy
+
+import (
+	"io"
+	"log"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/pricing"
+	"github.com/eddiefleurent/scranton_strangler/internal/storage"
+)
+
+func TestStrangleStrategy_FindStrangleStrikes_ComputesMissingGreeks(t *testing.T) {
+	model := pricing.Model{RiskFreeRate: 0.045, DividendYield: 0.013}
+	exp := time.Now().AddDate(0, 0, 45).Format("2006-01-02")
+	years, err := pricing.YearsToExpiration(exp, time.Now())
+	if err != nil {
+		t.Fatalf("YearsToExpiration error: %v", err)
+	}
+
+	// A quoted chain at 15% vol with no greeks, as the sandbox returns it
+	const spot, vol = 100.0, 0.15
+	var chain []broker.Option
+	wantPut, wantCall := 0.0, 0.0
+	bestPut, bestCall := math.MaxFloat64, math.MaxFloat64
+	for strike := 80.0; strike <= 120; strike++ {
+		for _, isCall := range []bool{false, true} {
+			price := model.Price(isCall, spot, strike, years, vol)
+			optType, best, want := "put", &bestPut, &wantPut
+			if isCall {
+				optType, best, want = "call", &bestCall, &wantCall
+			}
+			if d := math.Abs(math.Abs(model.Greeks(isCall, spot, strike, years, vol).Delta) - 0.16); d < *best {
+				*best, *want = d, strike
+			}
+			chain = append(chain, broker.Option{
+				Strike: strike, OptionType: optType, ExpirationDate: exp,
+				Bid: math.Max(price-0.01, 0), Ask: price + 0.01,
+			})
+		}
+	}
+
+	mockBroker := &mockBrokerForStrategy{
+		quote:       &broker.QuoteItem{Last: spot},
+		expirations: []string{exp},
+		chain:       chain,
+	}
+	config := &Config{
+		Symbol: "SPY", DTETarget: 45, DTERange: []int{40, 50}, DeltaTarget: 0.16,
+		MinCredit: 0.10, AllocationPct: 1.0, MinIVPct: 10,
+		RiskFreeRate: model.RiskFreeRate, DividendYield: model.DividendYield,
+	}
+	s := NewStrangleStrategy(mockBroker, config, log.New(io.Discard, "", 0), storage.NewMockStorage())
+
+	canTrade, reason := s.CheckVolatilityThreshold()
+	if !canTrade || !strings.Contains(reason, "15.0%") {
+		t.Errorf("CheckVolatilityThreshold() = %v (%s), want 15.0%% IV from the quotes", canTrade, reason)
+	}
+
+	order, err := s.FindStrangleStrikes()
+	if err != nil {
+		t.Fatalf("FindStrangleStrikes() error: %v", err)
+	}
+	if order.PutStrike != wantPut || order.CallStrike != wantCall {
+		t.Errorf("strikes = %.0f/%.0f, want 16 delta strikes %.0f/%.0f", order.PutStrike, order.CallStrike, wantPut, wantCall)
+	}
+	if mockBroker.chain[0].Greeks != nil {
+		t.Error("broker chain should not be modified")
+	}
+}
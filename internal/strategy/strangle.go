@@ -7,13 +7,16 @@ import (
 	"log"
 	"math"
 	"sort"
+	"strconv"
 	"sync"
 	"time"
 
 	"golang.org/x/sync/singleflight"
 
 	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/events"
 	"github.com/eddiefleurent/scranton_strangler/internal/models"
+	"github.com/eddiefleurent/scranton_strangler/internal/pricing"
 	"github.com/eddiefleurent/scranton_strangler/internal/storage"
 )
 
@@ -39,6 +42,7 @@ type StrangleStrategy struct {
 	sf         singleflight.Group                // Singleflight to dedupe concurrent identical calls
 	storage    storage.Interface                 // Storage for historical IV data
 	nyLocation *time.Location                    // Cached America/New_York location
+	calendar   events.Calendar                   // Economic events that block new entries; nil disables
 }
 
 // Config contains configuration parameters for the strangle strategy.
@@ -48,9 +52,18 @@ type Config struct {
 	DTERange        []int   // Acceptable DTE range [min, max]
 	DeltaTarget     float64 // 0.16 for 16 delta
 	ProfitTarget    float64 // 0.50 for 50%
+	ThirdDownProfitTarget float64 // 0.25 for 25%, applied once a position reaches Third Down
 	MaxDTE          int     // 21 days to exit
 	AllocationPct   float64 // 0.35 for 35%
 	MinIVPct        float64 // 15.0 for 15% SPY ATM IV threshold
+	MinIVR          float64 // Minimum IV Rank (0-100) to enter; 0 gates on MinIVPct only
+	IVLookbackDays  int     // Trading days of IV history for IV Rank/Percentile (default: 252)
+	MinIVRReadings  int     // Stored IV readings needed before IV Rank gates entries (default: 60)
+	IVRRules        []EntryRule // Optional IV regimes overriding DTETarget, DTERange and DeltaTarget; first match wins
+	EventWindow     time.Duration // Entries are blocked this long before and after a calendar event
+	EventMinImpact  events.Impact // Lowest event impact that blocks entries (default: high)
+	RiskFreeRate    float64 // Annualised rate for locally computed greeks (0.045 = 4.5%)
+	DividendYield   float64 // Annualised dividend yield for locally computed greeks
 	MinCredit       float64 // $2.00
 	EscalateLossPct float64 // e.g., 2.0 (200% loss triggers escalation)
 	StopLossPct     float64 // e.g., 2.5 (250% loss triggers hard stop)
@@ -74,6 +87,8 @@ const (
 	ExitReasonEscalate ExitReason = "escalate"
 	// ExitReasonStopLoss indicates exit due to stop loss
 	ExitReasonStopLoss ExitReason = "stop_loss"
+	// ExitReasonDailyLoss indicates exit because the account hit its daily loss limit
+	ExitReasonDailyLoss ExitReason = "daily_loss"
 	// ExitReasonManual indicates manual exit
 	ExitReasonManual ExitReason = "manual"
 	// ExitReasonError indicates exit due to error
@@ -106,15 +121,15 @@ func NewStrangleStrategy(b broker.Broker, config *Config, logger *log.Logger, st
 func (s *StrangleStrategy) CheckEntryConditions() (bool, string) {
 	// Position existence is enforced by storage layer
 
-	// Check volatility threshold using absolute SPY IV level
+	// Check volatility threshold (IV Rank when configured and history allows, else absolute IV)
 	canTrade, reason := s.CheckVolatilityThreshold()
 	if !canTrade {
 		return false, reason
 	}
 
-	// Check for major events (simplified)
-	if s.hasMajorEventsNearby() {
-		return false, "major event within 48 hours"
+	// Stand aside around scheduled economic events
+	if blocked, reason := s.hasMajorEventsNearby(); blocked {
+		return false, reason
 	}
 
 	return true, "entry conditions met"
@@ -128,8 +143,13 @@ func (s *StrangleStrategy) FindStrangleStrikes() (*StrangleOrder, error) {
 		return nil, err
 	}
 
+	// Pick DTE and delta for the current IV regime
+	rule, ivStats, ruleReason := s.selectEntryRule()
+	s.logger.Printf("Entry rule %q: target DTE %d, delta %.2f (%s)",
+		rule.Name, rule.DTETarget, rule.DeltaTarget, ruleReason)
+
 	// Vary DTE target to avoid identical positions
-	targetDTE := s.getVariedDTETarget()
+	targetDTE := s.getVariedDTETarget(rule)
 
 	// Find expiration around target DTE
 	targetExp := s.findTargetExpiration(targetDTE)
@@ -144,10 +164,11 @@ func (s *StrangleStrategy) FindStrangleStrikes() (*StrangleOrder, error) {
 	if err != nil {
 		return nil, err
 	}
+	options = s.withLocalGreeks(options, quote.Last)
 
 	// Find strikes closest to target delta
-	putStrike := s.findStrikeByDelta(options, -s.config.DeltaTarget, true)
-	callStrike := s.findStrikeByDelta(options, s.config.DeltaTarget, false)
+	putStrike := s.findStrikeByDelta(options, -rule.DeltaTarget, true)
+	callStrike := s.findStrikeByDelta(options, rule.DeltaTarget, false)
 
 	// Validate strikes
 	if putStrike == 0 || callStrike == 0 {
@@ -184,6 +205,8 @@ func (s *StrangleStrategy) FindStrangleStrikes() (*StrangleOrder, error) {
 		Quantity:     quantity,
 		SpotPrice:    quote.Last,
 		ProfitTarget: s.config.ProfitTarget,
+		EntryRule:    rule.Name,
+		IVStats:      ivStats,
 	}, nil
 }
 
@@ -197,13 +220,23 @@ func (s *StrangleStrategy) GetCurrentIV() float64 {
 	return currentIV * 100 // Convert to percentage
 }
 
-// CheckVolatilityThreshold checks if current SPY IV exceeds the minimum threshold for selling
+// CheckVolatilityThreshold checks if current SPY IV exceeds the minimum threshold for selling.
+// When MinIVR is set the gate uses IV Rank, falling back to the absolute MinIVPct rule
+// until enough IV history has been stored.
 func (s *StrangleStrategy) CheckVolatilityThreshold() (bool, string) {
 	currentIV, err := s.getCurrentImpliedVolatility()
 	if err != nil {
 		return false, fmt.Sprintf("%s IV unavailable: %v", s.config.Symbol, err)
 	}
 
+	if s.config.MinIVR > 0 {
+		canTrade, reason, ok := s.checkIVRankThreshold(currentIV)
+		if ok {
+			return canTrade, reason
+		}
+		s.logger.Printf("IV Rank gate unavailable (%s), falling back to absolute IV threshold", reason)
+	}
+
 	ivPercent := currentIV * 100
 	threshold := s.config.MinIVPct // Configurable threshold from config.yaml
 
@@ -236,6 +269,8 @@ func (s *StrangleStrategy) getCurrentImpliedVolatility() (float64, error) {
 		return 0, fmt.Errorf("failed to get quote: %w", err)
 	}
 
+	chain = s.withLocalGreeks(chain, quote.Last)
+
 	// Prefer ATM call; fall back to ATM put, then nearest-with-IV
 	atmStrike := s.findNearestStrike(chain, quote.Last)
 	if atmStrike == 0 {
@@ -479,26 +514,52 @@ func (s *StrangleStrategy) GetCurrentPositionValue(position *models.Position) (f
 	return (putValue + callValue) * float64(position.Quantity) * sharesPerContract, nil
 }
 
-func (s *StrangleStrategy) hasMajorEventsNearby() bool {
-	// Check for FOMC, CPI, etc.
-	// For MVP, simplified check
-	return false
+// SetEventCalendar sets the economic event calendar used to block new entries
+func (s *StrangleStrategy) SetEventCalendar(calendar events.Calendar) {
+	s.calendar = calendar
 }
 
-// getVariedDTETarget returns a DTE target that varies based on existing positions
-// to avoid opening identical trades
-func (s *StrangleStrategy) getVariedDTETarget() int {
+// hasMajorEventsNearby reports whether an event of at least EventMinImpact falls within
+// EventWindow of now, with a reason naming the event. A calendar that cannot be read
+// blocks entry rather than risk selling premium into an unknown release.
+func (s *StrangleStrategy) hasMajorEventsNearby() (bool, string) {
+	if s.calendar == nil || s.config.EventWindow <= 0 {
+		return false, ""
+	}
+	minImpact := s.config.EventMinImpact
+	if !minImpact.Valid() {
+		minImpact = events.ImpactHigh
+	}
+
+	event, err := events.NextEventWithin(s.calendar, time.Now(), s.config.EventWindow, minImpact)
+	if err != nil {
+		return true, fmt.Sprintf("event calendar unavailable: %v", err)
+	}
+	if event == nil {
+		return false, ""
+	}
+	return true, fmt.Sprintf("%s impact event within %s: %s", event.Impact, formatWindow(s.config.EventWindow), event)
+}
+
+// formatWindow renders an event window in hours, e.g. "48h"
+func formatWindow(d time.Duration) string {
+	return strconv.FormatFloat(d.Hours(), 'f', -1, 64) + "h"
+}
+
+// getVariedDTETarget returns a DTE target within the rule's range that varies based on
+// existing positions to avoid opening identical trades
+func (s *StrangleStrategy) getVariedDTETarget(rule EntryRule) int {
 	// Early guard for nil storage (prevents panic in tests or dry runs)
 	if s.storage == nil {
-		return s.config.DTETarget
+		return rule.DTETarget
 	}
 
 	// Get existing positions to check their DTEs
 	positions := s.storage.GetCurrentPositions()
 
-	// If no positions, use the configured target
+	// If no positions, use the rule's target
 	if len(positions) == 0 {
-		return s.config.DTETarget
+		return rule.DTETarget
 	}
 
 	// Collect existing DTEs
@@ -512,23 +573,24 @@ func (s *StrangleStrategy) getVariedDTETarget() int {
 	// Default range if not configured
 	minDTE := 40
 	maxDTE := 50
-	if len(s.config.DTERange) >= 2 {
-		minDTE = s.config.DTERange[0]
-		maxDTE = s.config.DTERange[1]
+	if len(rule.DTERange) >= 2 {
+		minDTE = rule.DTERange[0]
+		maxDTE = rule.DTERange[1]
 	}
 
-	// Try to find a DTE that's not already used
-	preferredDTEs := []int{45, 43, 47, 41, 49, 40, 50, 42, 48, 44, 46}
-	for _, dte := range preferredDTEs {
+	// Try to find a DTE that's not already used, working outward from the target
+	preferredOffsets := []int{0, -2, 2, -4, 4, -5, 5, -3, 3, -1, 1}
+	for _, offset := range preferredOffsets {
+		dte := rule.DTETarget + offset
 		if dte >= minDTE && dte <= maxDTE && !existingDTEs[dte] {
 			s.logger.Printf("Using varied DTE target: %d (avoiding existing: %v)", dte, existingDTEs)
 			return dte
 		}
 	}
 
-	// If all preferred DTEs are taken, just use the configured target
-	s.logger.Printf("All preferred DTEs taken, using default: %d", s.config.DTETarget)
-	return s.config.DTETarget
+	// If all preferred DTEs are taken, just use the rule's target
+	s.logger.Printf("All preferred DTEs taken, using default: %d", rule.DTETarget)
+	return rule.DTETarget
 }
 
 func (s *StrangleStrategy) findTargetExpiration(targetDTE int) string {
@@ -624,6 +686,17 @@ func (s *StrangleStrategy) findTargetExpiration(targetDTE int) string {
 	return originalTarget.Format("2006-01-02")
 }
 
+// withLocalGreeks fills in greeks and mid IV that the broker left out of the chain,
+// using Black-Scholes with the configured rate and dividend yield
+func (s *StrangleStrategy) withLocalGreeks(chain []broker.Option, spot float64) []broker.Option {
+	model := pricing.Model{RiskFreeRate: s.config.RiskFreeRate, DividendYield: s.config.DividendYield}
+	out, filled := model.FillMissingGreeks(chain, spot, time.Now())
+	if filled > 0 {
+		s.logger.Printf("Computed greeks locally for %d of %d options (missing from broker chain)", filled, len(chain))
+	}
+	return out
+}
+
 // getCacheTTL returns the configured cache TTL or default if not set
 func (s *StrangleStrategy) getCacheTTL() time.Duration {
 	if s.config.CacheTTL > 0 {
@@ -883,7 +956,7 @@ func (s *StrangleStrategy) CheckExitConditions(position *models.Position) (bool,
 		return true, ExitReasonError
 	}
 	profitPct := currentPnL / absTotalNetCredit
-	if profitPct >= s.config.ProfitTarget {
+	if profitPct >= s.ProfitTargetFor(position) {
 		return true, ExitReasonProfitTarget
 	}
 
@@ -894,27 +967,23 @@ func (s *StrangleStrategy) CheckExitConditions(position *models.Position) (bool,
 	}
 	if profitPct <= -escalateThreshold {
 		// First check if we've reached the stop loss threshold
-		sl := s.config.StopLossPct
-		if sl <= 0 {
-			sl = 2.5
-		} // Default to 250% to match old behavior
-		// Respect account-equity risk cap by converting it to "credit units"
-		if s.config.MaxPositionLoss > 0 {
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			defer cancel()
-			if equity, err := s.broker.GetAccountBalanceCtx(ctx); err == nil && equity > 0 && absTotalNetCredit > 0 {
-				riskDollars := equity * (s.config.MaxPositionLoss / 100.0)
-				riskStopLossPct := riskDollars / absTotalNetCredit
-				sl = math.Min(sl, riskStopLossPct)
-			}
-		}
-		if profitPct <= -sl {
+		if profitPct <= -s.StopLossFor(absTotalNetCredit, s.StopLossEquity(context.Background())) {
 			return true, ExitReasonStopLoss
 		}
 		// Otherwise trigger escalate action
 		return true, ExitReasonEscalate
 	}
 
+	// Fourth Down options carry their own holding limits
+	if position.GetCurrentState() == models.StateFourthDown {
+		check := position.Clone()
+		check.CurrentPnL = currentPnL
+		if exit, why := check.ShouldEmergencyExit(s.config.MaxDTE, escalateThreshold); exit {
+			s.logger.Printf("Position %s: %s", position.ID, why)
+			return true, ExitReasonEscalate
+		}
+	}
+
 	// Check DTE using strategy config
 	currentDTE := position.CalculateDTE()
 	if currentDTE <= s.config.MaxDTE {
@@ -924,6 +993,66 @@ func (s *StrangleStrategy) CheckExitConditions(position *models.Position) (bool,
 	return false, ExitReasonNone
 }
 
+// ProfitTargetFor returns the profit target fraction that applies to the position.
+// Third Down positions have already been repaired into a straddle, so they are
+// closed at the smaller ThirdDownProfitTarget instead of the normal target. In
+// Fourth Down an inverted strangle (Option A) is closed at breakeven or better,
+// while a held straddle (Option B) keeps the Third Down target.
+func (s *StrangleStrategy) ProfitTargetFor(position *models.Position) float64 {
+	if position == nil {
+		return s.config.ProfitTarget
+	}
+	switch position.GetCurrentState() {
+	case models.StateThirdDown:
+		if s.config.ThirdDownProfitTarget > 0 {
+			return s.config.ThirdDownProfitTarget
+		}
+	case models.StateFourthDown:
+		if position.GetFourthDownOption() == models.OptionA {
+			return 0
+		}
+		if s.config.ThirdDownProfitTarget > 0 {
+			return s.config.ThirdDownProfitTarget
+		}
+	}
+	return s.config.ProfitTarget
+}
+
+// StopLossFor returns the loss, as a ratio of the position's total credit in dollars,
+// at which it is stopped out. This is StopLossPct, tightened when risk.max_position_loss
+// of equity would be hit first. Pass the equity from StopLossEquity; zero leaves the
+// equity cap out.
+func (s *StrangleStrategy) StopLossFor(absTotalNetCredit, equity float64) float64 {
+	sl := s.config.StopLossPct
+	if sl <= 0 {
+		sl = 2.5
+	} // Default to 250% to match old behavior
+	// Respect account-equity risk cap by converting it to "credit units"
+	if s.config.MaxPositionLoss > 0 && absTotalNetCredit > 0 && equity > 0 {
+		riskDollars := equity * (s.config.MaxPositionLoss / 100.0)
+		riskStopLossPct := riskDollars / absTotalNetCredit
+		sl = math.Min(sl, riskStopLossPct)
+	}
+	return sl
+}
+
+// StopLossEquity returns the account equity StopLossFor caps losses against, or zero
+// when risk.max_position_loss is off or the balance is unavailable. Fetch it once and
+// reuse it for every position checked together.
+func (s *StrangleStrategy) StopLossEquity(ctx context.Context) float64 {
+	if s.config.MaxPositionLoss <= 0 {
+		return 0
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	equity, err := s.broker.GetAccountBalanceCtx(ctx)
+	if err != nil || equity <= 0 {
+		s.logger.Printf("Warning: account equity unavailable for the position loss cap: %v", err)
+		return 0
+	}
+	return equity
+}
+
 // CalculatePnL calculates the current profit/loss for a position.
 func (s *StrangleStrategy) CalculatePnL(pos *models.Position) float64 {
 	// Use the unified CalculatePositionPnL implementation
@@ -1000,4 +1129,6 @@ type StrangleOrder struct {
 	Quantity     int
 	SpotPrice    float64
 	ProfitTarget float64
+	EntryRule    string   // Name of the IVR rule that set DTE and delta ("default" for the base config)
+	IVStats      *IVStats // IV Rank at selection; nil when no rules are configured or history is short
 }
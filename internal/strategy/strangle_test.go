@@ -3,6 +3,7 @@ package strategy
 import (
 	"context"
 	"errors"
+	"io"
 	"log"
 	"math"
 	"strings"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/eddiefleurent/scranton_strangler/internal/broker"
+	"github.com/eddiefleurent/scranton_strangler/internal/events"
 	"github.com/eddiefleurent/scranton_strangler/internal/models"
 	"github.com/eddiefleurent/scranton_strangler/internal/storage"
 )
@@ -58,6 +60,14 @@ func (m *mockBrokerForStrategy) GetQuote(symbol string) (*broker.QuoteItem, erro
 	return m.quote, m.quoteError
 }
 
+func (m *mockBrokerForStrategy) GetQuotes(symbols []string) ([]broker.QuoteItem, error) {
+	return nil, m.quoteError
+}
+
+func (m *mockBrokerForStrategy) GetQuotesCtx(ctx context.Context, symbols []string) ([]broker.QuoteItem, error) {
+	return nil, m.quoteError
+}
+
 func (m *mockBrokerForStrategy) GetExpirations(symbol string) ([]string, error) {
 	return m.expirations, m.expirationsErr
 }
@@ -101,6 +111,17 @@ func (m *mockBrokerForStrategy) CancelOrderCtx(ctx context.Context, orderID int)
 	return m.CancelOrder(orderID)
 }
 
+func (m *mockBrokerForStrategy) ModifyOrder(orderID int, price, tickSize float64, duration string) (*broker.OrderResponse, error) {
+	resp := &broker.OrderResponse{}
+	resp.Order.ID = orderID
+	resp.Order.Status = "ok"
+	return resp, nil
+}
+
+func (m *mockBrokerForStrategy) ModifyOrderCtx(ctx context.Context, orderID int, price, tickSize float64, duration string) (*broker.OrderResponse, error) {
+	return m.ModifyOrder(orderID, price, tickSize, duration)
+}
+
 func (m *mockBrokerForStrategy) GetOrders() (*broker.OrdersResponse, error) {
 	return &broker.OrdersResponse{}, nil
 }
@@ -125,6 +146,14 @@ func (m *mockBrokerForStrategy) PlaceSellToCloseOrder(optionSymbol string, quant
 	return &broker.OrderResponse{}, nil
 }
 
+func (m *mockBrokerForStrategy) PlaceMultiLegOrder(legs []broker.OrderLeg, priceType broker.NetPriceType, price float64, duration string, tag string) (*broker.OrderResponse, error) {
+	return &broker.OrderResponse{}, nil
+}
+
+func (m *mockBrokerForStrategy) PlaceMultiLegOrderCtx(ctx context.Context, legs []broker.OrderLeg, priceType broker.NetPriceType, price float64, duration string, tag string) (*broker.OrderResponse, error) {
+	return &broker.OrderResponse{}, nil
+}
+
 func (m *mockBrokerForStrategy) PlaceBuyToCloseMarketOrder(optionSymbol string, quantity int, duration string, tag string) (*broker.OrderResponse, error) {
 	return &broker.OrderResponse{}, nil
 }
@@ -263,6 +292,53 @@ func TestStrangleStrategy_CheckVolatilityThreshold(t *testing.T) {
 	}
 }
 
+func TestStrangleStrategy_CheckEntryConditions_EventCalendar(t *testing.T) {
+	now := time.Now()
+	calendar := events.NewStaticCalendar([]events.Event{
+		{Name: "FOMC rate decision", Type: "FOMC", Time: now.Add(30 * time.Hour), Impact: events.ImpactHigh},
+		{Name: "Retail sales", Type: "RETAIL", Time: now.Add(6 * time.Hour), Impact: events.ImpactMedium},
+	})
+
+	tests := []struct {
+		name         string
+		window       time.Duration
+		minImpact    events.Impact
+		calendar     events.Calendar
+		expectEnter  bool
+		expectReason string
+	}{
+		{"high impact event inside window", 48 * time.Hour, events.ImpactHigh, calendar, false, "FOMC rate decision (FOMC)"},
+		{"event outside window", 24 * time.Hour, events.ImpactHigh, calendar, true, "entry conditions met"},
+		{"medium event blocks at medium minimum", 24 * time.Hour, events.ImpactMedium, calendar, false, "Retail sales (RETAIL)"},
+		{"no calendar", 48 * time.Hour, events.ImpactHigh, nil, true, "entry conditions met"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBroker := &mockBrokerForStrategy{
+				quote:       &broker.QuoteItem{Last: 400.0},
+				expirations: []string{now.AddDate(0, 0, 45).Format("2006-01-02")},
+				chain: []broker.Option{
+					{Strike: 400, OptionType: "call", Bid: 2.0, Ask: 2.2, Greeks: &broker.Greeks{MidIV: 0.35}},
+				},
+			}
+			config := &Config{Symbol: "SPY", DTETarget: 45, MinIVPct: 30, EventWindow: tt.window, EventMinImpact: tt.minImpact}
+			s := NewStrangleStrategy(mockBroker, config, log.New(io.Discard, "", 0), storage.NewMockStorage())
+			if tt.calendar != nil {
+				s.SetEventCalendar(tt.calendar)
+			}
+
+			canEnter, reason := s.CheckEntryConditions()
+			if canEnter != tt.expectEnter {
+				t.Errorf("CheckEntryConditions() = %v (%s), want %v", canEnter, reason, tt.expectEnter)
+			}
+			if !strings.Contains(reason, tt.expectReason) {
+				t.Errorf("reason %q does not contain %q", reason, tt.expectReason)
+			}
+		})
+	}
+}
+
 func TestStrangleStrategy_GetCurrentIV(t *testing.T) {
 	// Set up mock with option chain containing Greeks data
 	mockBroker := &mockBrokerForStrategy{
@@ -544,6 +620,7 @@ func TestStrangleStrategy_CheckExitConditions(t *testing.T) {
 	cfg := &Config{
 		Symbol:          "SPY",
 		ProfitTarget:    0.50, // 50%
+		ThirdDownProfitTarget: 0.25, // 25% once repaired into a straddle
 		MaxDTE:          21,   // Default MaxDTE value
 		MaxPositionLoss: 2.5,  // Allow up to 250% loss (same as default stop loss)
 	}
@@ -616,6 +693,21 @@ func TestStrangleStrategy_CheckExitConditions(t *testing.T) {
 			expectedExit:   true,
 			expectedReason: "stop_loss",
 		},
+		{
+			name: "third down reduced profit target reached",
+			position: &models.Position{
+				Symbol:         "SPY",
+				PutStrike:      400.0,
+				CallStrike:     420.0,
+				Expiration:     time.Now().AddDate(0, 0, 35),
+				Quantity:       1,
+				CreditReceived: 3.50, // Stored as per-share credit (total credit: $350)
+				DTE:            35,
+				State:          models.StateThirdDown,
+			},
+			expectedExit:   true,
+			expectedReason: "profit_target",
+		},
 		{
 			name: "no exit conditions met",
 			position: &models.Position{
@@ -915,6 +1007,10 @@ func setupTestScenarioPrices(t *testing.T, mockClient *mockBroker, expiration st
 		mockClient.setOptionPrice(expiration, 400.0, "put", 6.00)
 		mockClient.setOptionPrice(expiration, 420.0, "call", 6.25)
 		// Total: 12.25, P&L = 3.50 - 12.25 = -8.75 per contract = -$875
+	case "third down reduced profit target reached":
+		// Credit 3.50, current value 2.60: P&L $90 (~26%) clears the 25% Third Down target only
+		mockClient.setOptionPrice(expiration, 400.0, "put", 1.30)
+		mockClient.setOptionPrice(expiration, 420.0, "call", 1.30)
 	case "no exit conditions met":
 		// Same as max DTE but different DTE in position
 		mockClient.setOptionPrice(expiration, 400.0, "put", 1.50)
@@ -969,6 +1065,14 @@ func (m *mockBroker) GetQuote(_ string) (*broker.QuoteItem, error) {
 	return &broker.QuoteItem{Last: 420.0}, nil
 }
 
+func (m *mockBroker) GetQuotes(_ []string) ([]broker.QuoteItem, error) {
+	return nil, nil
+}
+
+func (m *mockBroker) GetQuotesCtx(_ context.Context, _ []string) ([]broker.QuoteItem, error) {
+	return nil, nil
+}
+
 func (m *mockBroker) GetExpirations(_ string) ([]string, error) {
 	var exps []string
 	start := time.Now().UTC()
@@ -1084,24 +1188,7 @@ func (m *mockBroker) CloseStranglePositionCtx(
 
 func (m *mockBroker) GetOrderStatus(orderID int) (*broker.OrderResponse, error) {
 	return &broker.OrderResponse{
-		Order: struct {
-			CreateDate        string  `json:"create_date"`
-			Type              string  `json:"type"`
-			Symbol            string  `json:"symbol"`
-			Side              string  `json:"side"`
-			Class             string  `json:"class"`
-			Status            string  `json:"status"`
-			Duration          string  `json:"duration"`
-			TransactionDate   string  `json:"transaction_date"`
-			AvgFillPrice      float64 `json:"avg_fill_price"`
-			ExecQuantity      float64 `json:"exec_quantity"`
-			LastFillPrice     float64 `json:"last_fill_price"`
-			LastFillQuantity  float64 `json:"last_fill_quantity"`
-			RemainingQuantity float64 `json:"remaining_quantity"`
-			ID                int     `json:"id"`
-			Price             float64 `json:"price"`
-			Quantity          float64 `json:"quantity"`
-		}{
+		Order: broker.Order{
 			ID:     orderID,
 			Status: "filled",
 		},
@@ -1114,24 +1201,7 @@ func (m *mockBroker) GetOrderStatusCtx(_ context.Context, orderID int) (*broker.
 
 func (m *mockBroker) CancelOrder(orderID int) (*broker.OrderResponse, error) {
 	return &broker.OrderResponse{
-		Order: struct {
-			CreateDate        string  `json:"create_date"`
-			Type              string  `json:"type"`
-			Symbol            string  `json:"symbol"`
-			Side              string  `json:"side"`
-			Class             string  `json:"class"`
-			Status            string  `json:"status"`
-			Duration          string  `json:"duration"`
-			TransactionDate   string  `json:"transaction_date"`
-			AvgFillPrice      float64 `json:"avg_fill_price"`
-			ExecQuantity      float64 `json:"exec_quantity"`
-			LastFillPrice     float64 `json:"last_fill_price"`
-			LastFillQuantity  float64 `json:"last_fill_quantity"`
-			RemainingQuantity float64 `json:"remaining_quantity"`
-			ID                int     `json:"id"`
-			Price             float64 `json:"price"`
-			Quantity          float64 `json:"quantity"`
-		}{
+		Order: broker.Order{
 			ID:     orderID,
 			Status: "canceled",
 		},
@@ -1142,6 +1212,17 @@ func (m *mockBroker) CancelOrderCtx(_ context.Context, orderID int) (*broker.Ord
 	return m.CancelOrder(orderID)
 }
 
+func (m *mockBroker) ModifyOrder(orderID int, price, tickSize float64, duration string) (*broker.OrderResponse, error) {
+	resp := &broker.OrderResponse{}
+	resp.Order.ID = orderID
+	resp.Order.Status = "ok"
+	return resp, nil
+}
+
+func (m *mockBroker) ModifyOrderCtx(_ context.Context, orderID int, price, tickSize float64, duration string) (*broker.OrderResponse, error) {
+	return m.ModifyOrder(orderID, price, tickSize, duration)
+}
+
 func (m *mockBroker) GetOrders() (*broker.OrdersResponse, error) {
 	return &broker.OrdersResponse{}, nil
 }
@@ -1170,6 +1251,27 @@ func (m *mockBroker) PlaceSellToCloseOrder(
 	return nil, nil
 }
 
+func (m *mockBroker) PlaceMultiLegOrder(
+	_ []broker.OrderLeg,
+	_ broker.NetPriceType,
+	_ float64,
+	_ string,
+	_ string,
+) (*broker.OrderResponse, error) {
+	return nil, nil
+}
+
+func (m *mockBroker) PlaceMultiLegOrderCtx(
+	_ context.Context,
+	_ []broker.OrderLeg,
+	_ broker.NetPriceType,
+	_ float64,
+	_ string,
+	_ string,
+) (*broker.OrderResponse, error) {
+	return nil, nil
+}
+
 func (m *mockBroker) PlaceBuyToCloseMarketOrder(
 	_ string,
 	_ int,
@@ -13,6 +13,7 @@ import (
 
 	"github.com/eddiefleurent/scranton_strangler/internal/broker"
 	"github.com/eddiefleurent/scranton_strangler/internal/config"
+	"github.com/eddiefleurent/scranton_strangler/internal/storage"
 )
 
 func main() {
@@ -44,6 +45,7 @@ func main() {
 
 	// Create clean positions.json structure
 	cleanData := map[string]interface{}{
+		"schema_version":     storage.CurrentSchemaVersion,
 		"last_updated":       time.Now().Format(time.RFC3339),
 		"current_positions":  []interface{}{},
 		"daily_pnl":         map[string]float64{},
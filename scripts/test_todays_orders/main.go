@@ -40,7 +40,7 @@ func main() {
 
 	// Initialize storage
 	testStoragePath := filepath.Join(os.TempDir(), "vxx_proxy_test.json")
-	store, err := storage.NewStorage(testStoragePath)
+	store, err := storage.NewStorage(storage.BackendJSON, testStoragePath)
 	if err != nil {
 		log.Fatalf("Failed to initialize storage: %v", err)
 	}
@@ -54,6 +54,11 @@ func main() {
 		MaxDTE:              cfg.Strategy.Exit.MaxDTE,
 		AllocationPct:       cfg.Strategy.AllocationPct,
 		MinIVPct:            cfg.Strategy.Entry.MinIVPct,
+		MinIVR:              cfg.Strategy.Entry.MinIVR,
+		IVLookbackDays:      cfg.Strategy.Entry.IVLookbackDays,
+		MinIVRReadings:      cfg.Strategy.Entry.MinIVRReadings,
+		RiskFreeRate:        cfg.Strategy.Pricing.RiskFreeRate,
+		DividendYield:       cfg.Strategy.Pricing.DividendYield,
 		MinCredit:           cfg.Strategy.Entry.MinCredit,
 		EscalateLossPct:     cfg.Strategy.EscalateLossPct,
 		StopLossPct:         cfg.Strategy.Exit.StopLossPct,